@@ -0,0 +1,182 @@
+package alerts
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	kitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/services"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+)
+
+// EVENTS_BUFFER is how many batches of events can wait for the engine before the poller drops them
+const EVENTS_BUFFER = 64
+
+const (
+	notificationSent   = "sent"
+	notificationFailed = "failed"
+)
+
+// SeriesGetter reads the observations a window is compared with, the service implements it
+type SeriesGetter interface {
+	GetSeries(ctx context.Context, req services.GetSeriesRequest) *services.GetSeriesResponse
+}
+
+// Engine evaluates the rules with the new observations of the poller and sends the alerts of the ones
+// that fire to every notifier. The revisions of past values are not evaluated, they are not news.
+type Engine struct {
+	service       SeriesGetter
+	rules         []models.AlertRule
+	notifiers     []Notifier
+	timeout       time.Duration
+	notifications kitmetrics.Counter
+	logger        log.Logger
+	events        chan []models.ObservationEvent
+	// fired is the date of the last observation every rule fired on, only the goroutine of Run uses it
+	fired map[string]time.Time
+}
+
+func NewEngine(service SeriesGetter, rules []models.AlertRule, notifiers []Notifier, timeout time.Duration, notifications kitmetrics.Counter, logger log.Logger) *Engine {
+	return &Engine{
+		service:       service,
+		rules:         rules,
+		notifiers:     notifiers,
+		timeout:       timeout,
+		notifications: notifications,
+		logger:        logger,
+		events:        make(chan []models.ObservationEvent, EVENTS_BUFFER),
+		fired:         map[string]time.Time{},
+	}
+}
+
+// Series returns the series of the rules, the poller scrapes them
+func (engine *Engine) Series() []string {
+	seen := map[string]bool{}
+	series := []string{}
+	for _, rule := range engine.rules {
+		if !seen[rule.Series] {
+			seen[rule.Series] = true
+			series = append(series, rule.Series)
+		}
+	}
+	sort.Strings(series)
+	return series
+}
+
+// Publish hands the events to the engine without waiting for the rules, a slow notifier does not hold
+// the poller
+func (engine *Engine) Publish(events ...models.ObservationEvent) {
+	select {
+	case engine.events <- events:
+	default:
+		_ = level.Error(engine.logger).Log("msg", "alert engine busy, events dropped", "events", len(events))
+	}
+}
+
+// Run evaluates the events until the context is cancelled
+func (engine *Engine) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case events := <-engine.events:
+			engine.handle(ctx, events)
+		}
+	}
+}
+
+// handle evaluates the rules of the series of every event. A rule fires once per observation, the same
+// observation detected again, or an older one, does not fire it again.
+func (engine *Engine) handle(ctx context.Context, events []models.ObservationEvent) {
+	for _, event := range events {
+		if event.Type != models.EventObservation {
+			continue
+		}
+		for _, rule := range engine.rules {
+			if rule.Series != event.Series || !event.Date.After(engine.fired[rule.Name]) {
+				continue
+			}
+			alert, err := engine.evaluate(ctx, rule, event)
+			if err != nil {
+				_ = level.Error(engine.logger).Log("msg", "error evaluating alert rule", "rule", rule.Name, "error", err)
+				continue
+			}
+			if alert != nil {
+				engine.fired[rule.Name] = event.Date
+				engine.notify(ctx, *alert)
+			}
+		}
+	}
+}
+
+// evaluate returns the alert of the rule, or nil when it does not fire. A change with window is compared
+// with the last observation at the start of the window, the rest with the observation before the event.
+func (engine *Engine) evaluate(ctx context.Context, rule models.AlertRule, event models.ObservationEvent) (*models.Alert, error) {
+	alert := &models.Alert{
+		Rule:        rule.Name,
+		Series:      event.Series,
+		Date:        event.Date,
+		Value:       event.Value,
+		Reference:   event.Previous,
+		TriggeredAt: time.Now(),
+	}
+	if rule.Type == models.RuleChange && rule.Window != "" {
+		reference, err := engine.observationAt(ctx, rule, event.Date)
+		if err != nil || reference == nil {
+			return nil, err
+		}
+		alert.Reference = &reference.Value
+		alert.ReferenceDate = &reference.Date
+	}
+
+	fires, message := check(rule, alert.Value, alert.Reference)
+	if !fires {
+		return nil, nil
+	}
+	alert.Message = message
+	if rule.Window != "" {
+		alert.Message += " in " + rule.Window
+	}
+	return alert, nil
+}
+
+// observationAt returns the last observation on or before the start of the window, nil when the series
+// has none within its lookback
+func (engine *Engine) observationAt(ctx context.Context, rule models.AlertRule, date time.Time) (*models.Observation, error) {
+	dateTo, err := utils.SubtractPeriod(date, rule.Window)
+	if err != nil {
+		return nil, err
+	}
+	response := engine.service.GetSeries(ctx, services.GetSeriesRequest{
+		Series:   rule.Series,
+		DateFrom: dateTo.AddDate(0, 0, -services.LookbackDays(rule.Series)),
+		DateTo:   dateTo,
+	})
+	if response.Err != nil {
+		return nil, response.Err
+	}
+	if len(response.Observations) == 0 {
+		return nil, nil
+	}
+	return &response.Observations[len(response.Observations)-1], nil
+}
+
+// notify sends the alert to every notifier, one failing does not stop the others
+func (engine *Engine) notify(ctx context.Context, alert models.Alert) {
+	for _, notifier := range engine.notifiers {
+		notifyCtx, cancel := context.WithTimeout(ctx, engine.timeout)
+		err := notifier.Notify(notifyCtx, alert)
+		cancel()
+
+		result := notificationSent
+		if err != nil {
+			result = notificationFailed
+			_ = level.Error(engine.logger).Log("msg", "error notifying alert", "rule", alert.Rule, "notifier", notifier.Name(), "error", err)
+		}
+		engine.notifications.With("rule", alert.Rule, "notifier", notifier.Name(), "result", result).Add(1)
+	}
+}
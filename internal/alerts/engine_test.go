@@ -0,0 +1,224 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/services"
+)
+
+var (
+	tbpWeekly         = models.AlertRule{Name: "tbp_weekly", Series: "tbp", Type: models.RuleChange, Window: "1w"}
+	inflationTarget   = models.AlertRule{Name: "inflation_target", Series: "inflation_cr", Type: models.RuleOutsideBand, Min: 2, Max: 4}
+	exchangeRateDaily = models.AlertRule{Name: "exchange_rate_daily", Series: "exchange_rate", Type: models.RuleChange, Window: "1d", Percent: 1}
+)
+
+// fakeSeries answers the observations of its history within the range, like the service
+type fakeSeries map[string][]models.Observation
+
+func (series fakeSeries) GetSeries(_ context.Context, req services.GetSeriesRequest) *services.GetSeriesResponse {
+	observations := []models.Observation{}
+	for _, observation := range series[req.Series] {
+		if !observation.Date.Before(req.DateFrom) && !observation.Date.After(req.DateTo) {
+			observations = append(observations, observation)
+		}
+	}
+	return &services.GetSeriesResponse{Series: req.Series, Observations: observations}
+}
+
+func day(month time.Month, day int) time.Time {
+	return time.Date(2026, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func observation(series string, date time.Time, value float64) models.Observation {
+	return models.Observation{Series: series, Date: date, Value: value}
+}
+
+func event(series string, date time.Time, previous float64, value float64) models.ObservationEvent {
+	return models.ObservationEvent{Type: models.EventObservation, Series: series, Date: date, Value: value, Previous: &previous}
+}
+
+func newTestEngine(history fakeSeries, rules ...models.AlertRule) (*Engine, *FakeNotifier) {
+	notifier := NewFakeNotifier()
+	engine := NewEngine(history, rules, []Notifier{notifier}, time.Second, discard.NewCounter(), log.NewNopLogger())
+	return engine, notifier
+}
+
+// firedOn returns the dates of the observations of the alerts
+func firedOn(alerts []models.Alert) []time.Time {
+	dates := []time.Time{}
+	for _, alert := range alerts {
+		dates = append(dates, alert.Date)
+	}
+	return dates
+}
+
+func sameDates(a []time.Time, b []time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestEngineRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     models.AlertRule
+		history  fakeSeries
+		events   []models.ObservationEvent
+		expected []time.Time
+	}{
+		{
+			// the tbp of a week before is the reference, not the observation before the event
+			name: "tbp changed from the previous week",
+			rule: tbpWeekly,
+			history: fakeSeries{"tbp": {
+				observation("tbp", day(10, 8), 4.10),
+				observation("tbp", day(10, 12), 4.00),
+				observation("tbp", day(10, 13), 4.10),
+				observation("tbp", day(10, 19), 4.00),
+				observation("tbp", day(10, 20), 4.25),
+			}},
+			events: []models.ObservationEvent{
+				event("tbp", day(10, 19), 4.10, 4.00),
+				event("tbp", day(10, 20), 4.00, 4.25),
+			},
+			expected: []time.Time{day(10, 20)},
+		},
+		{
+			// the inflation fires when it leaves the band, not again while it stays out
+			name: "inflation outside of the target",
+			rule: inflationTarget,
+			events: []models.ObservationEvent{
+				event("inflation_cr", day(5, 1), 2.5, 3.0),
+				event("inflation_cr", day(6, 1), 3.0, 4.2),
+				event("inflation_cr", day(7, 1), 4.2, 4.5),
+				event("inflation_cr", day(8, 1), 4.5, 3.5),
+				event("inflation_cr", day(9, 1), 3.5, 1.8),
+			},
+			expected: []time.Time{day(6, 1), day(9, 1)},
+		},
+		{
+			name: "exchange rate moved more than 1% in a day",
+			rule: exchangeRateDaily,
+			history: fakeSeries{"exchange_rate": {
+				observation("exchange_rate", day(10, 15), 500),
+				observation("exchange_rate", day(10, 16), 503),
+				observation("exchange_rate", day(10, 17), 510),
+				observation("exchange_rate", day(10, 18), 506),
+			}},
+			events: []models.ObservationEvent{
+				event("exchange_rate", day(10, 16), 500, 503),
+				event("exchange_rate", day(10, 17), 503, 510),
+				event("exchange_rate", day(10, 18), 510, 506),
+			},
+			expected: []time.Time{day(10, 17)},
+		},
+		{
+			name: "revisions are not evaluated",
+			rule: exchangeRateDaily,
+			history: fakeSeries{"exchange_rate": {
+				observation("exchange_rate", day(10, 15), 500),
+				observation("exchange_rate", day(10, 16), 600),
+			}},
+			events: []models.ObservationEvent{
+				{Type: models.EventRevision, Series: "exchange_rate", Date: day(10, 16), Value: 600},
+			},
+			expected: []time.Time{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			engine, notifier := newTestEngine(test.history, test.rule)
+			engine.handle(context.Background(), test.events)
+
+			alerts := notifier.Alerts()
+			if fired := firedOn(alerts); !sameDates(fired, test.expected) {
+				t.Fatalf("fired on %v, expected %v", fired, test.expected)
+			}
+			for _, alert := range alerts {
+				if alert.Rule != test.rule.Name || alert.Series != test.rule.Series || alert.Message == "" {
+					t.Errorf("unexpected alert %+v", alert)
+				}
+			}
+		})
+	}
+}
+
+func TestEngineComparesWithTheStartOfTheWindow(t *testing.T) {
+	engine, notifier := newTestEngine(fakeSeries{"exchange_rate": {
+		observation("exchange_rate", day(10, 15), 500),
+		observation("exchange_rate", day(10, 17), 510),
+	}}, exchangeRateDaily)
+
+	// nothing was published on the 16th, the 15th is the last observation at the start of the window
+	engine.handle(context.Background(), []models.ObservationEvent{event("exchange_rate", day(10, 17), 500, 510)})
+
+	alerts := notifier.Alerts()
+	if len(alerts) != 1 {
+		t.Fatalf("%d alerts, expected 1", len(alerts))
+	}
+	if alerts[0].Reference == nil || *alerts[0].Reference != 500 || alerts[0].ReferenceDate == nil || !alerts[0].ReferenceDate.Equal(day(10, 15)) {
+		t.Errorf("unexpected reference of %+v", alerts[0])
+	}
+}
+
+func TestEngineDoesNotFireTwiceOnTheSameObservation(t *testing.T) {
+	engine, notifier := newTestEngine(nil, inflationTarget)
+	ctx := context.Background()
+
+	outside := event("inflation_cr", day(6, 1), 3.0, 4.2)
+	engine.handle(ctx, []models.ObservationEvent{outside, outside})
+	engine.handle(ctx, []models.ObservationEvent{outside})
+	// a late observation before the one that fired is not news either
+	engine.handle(ctx, []models.ObservationEvent{event("inflation_cr", day(5, 1), 3.0, 4.3)})
+	if fired := firedOn(notifier.Alerts()); !sameDates(fired, []time.Time{day(6, 1)}) {
+		t.Fatalf("fired on %v, expected once on %v", fired, day(6, 1))
+	}
+
+	engine.handle(ctx, []models.ObservationEvent{
+		event("inflation_cr", day(7, 1), 4.2, 3.0),
+		event("inflation_cr", day(8, 1), 3.0, 4.4),
+	})
+	if fired := firedOn(notifier.Alerts()); !sameDates(fired, []time.Time{day(6, 1), day(8, 1)}) {
+		t.Errorf("fired on %v, expected again on %v after it came back to the band", fired, day(8, 1))
+	}
+}
+
+func TestEngineRunNotifiesThePublishedEvents(t *testing.T) {
+	engine, notifier := newTestEngine(nil, inflationTarget, exchangeRateDaily)
+	failing := NewFakeNotifier()
+	failing.Err = errors.New("unavailable")
+	engine.notifiers = []Notifier{failing, notifier}
+
+	if series := engine.Series(); len(series) != 2 || series[0] != "exchange_rate" || series[1] != "inflation_cr" {
+		t.Errorf("the poller would scrape %v", series)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go engine.Run(ctx)
+	engine.Publish(event("inflation_cr", day(6, 1), 3.0, 4.2))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(notifier.Alerts()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("the published event was not notified")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if alerts := notifier.Alerts(); len(alerts) != 1 || alerts[0].Rule != inflationTarget.Name {
+		t.Errorf("unexpected alerts %+v", alerts)
+	}
+}
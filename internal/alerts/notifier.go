@@ -0,0 +1,110 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/configuration"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+)
+
+// Notifier sends the alerts somewhere a person reads them. A new channel only needs to implement it and
+// be added to NewNotifiers.
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, alert models.Alert) error
+}
+
+// NewNotifiers returns the notifiers of ALERT_NOTIFIERS, checking they have what they need to send
+func NewNotifiers(config configuration.AlertConfig, logger log.Logger) ([]Notifier, error) {
+	notifiers := []Notifier{}
+	for _, name := range config.Notifiers {
+		switch strings.TrimSpace(name) {
+		case "log":
+			notifiers = append(notifiers, NewLogNotifier(logger))
+		case "smtp":
+			notifier, err := NewSMTPNotifier(config)
+			if err != nil {
+				return nil, err
+			}
+			notifiers = append(notifiers, notifier)
+		case "telegram":
+			notifier, err := NewTelegramNotifier(config)
+			if err != nil {
+				return nil, err
+			}
+			notifiers = append(notifiers, notifier)
+		default:
+			return nil, fmt.Errorf("%w, got %q", utils.ErrNotifier, name)
+		}
+	}
+	return notifiers, nil
+}
+
+// subject is the first line of the notifications
+func subject(alert models.Alert) string {
+	return fmt.Sprintf("[libertadfinanciera] %s", alert.Rule)
+}
+
+// text is the body of the notifications
+func text(alert models.Alert) string {
+	return fmt.Sprintf("%s on %s", alert.Message, alert.Date.Format(utils.DATE_FORMAT))
+}
+
+// LogNotifier writes the alerts to the logs, it needs nothing else to run locally
+type LogNotifier struct {
+	logger log.Logger
+}
+
+func NewLogNotifier(logger log.Logger) *LogNotifier {
+	return &LogNotifier{logger: logger}
+}
+
+func (notifier *LogNotifier) Name() string {
+	return "log"
+}
+
+func (notifier *LogNotifier) Notify(_ context.Context, alert models.Alert) error {
+	return level.Warn(notifier.logger).Log("msg", "alert triggered", "rule", alert.Rule, "series", alert.Series,
+		"date", alert.Date.Format(utils.DATE_FORMAT), "value", alert.Value, "message", alert.Message)
+}
+
+// FakeNotifier keeps the alerts in memory, to check the rules without sending anything. Err makes every
+// notification fail.
+type FakeNotifier struct {
+	mutex  sync.Mutex
+	alerts []models.Alert
+	Err    error
+}
+
+func NewFakeNotifier() *FakeNotifier {
+	return &FakeNotifier{}
+}
+
+func (notifier *FakeNotifier) Name() string {
+	return "fake"
+}
+
+func (notifier *FakeNotifier) Notify(_ context.Context, alert models.Alert) error {
+	notifier.mutex.Lock()
+	defer notifier.mutex.Unlock()
+	if notifier.Err != nil {
+		return notifier.Err
+	}
+	notifier.alerts = append(notifier.alerts, alert)
+	return nil
+}
+
+// Alerts returns the alerts notified so far, the oldest first
+func (notifier *FakeNotifier) Alerts() []models.Alert {
+	notifier.mutex.Lock()
+	defer notifier.mutex.Unlock()
+	alerts := make([]models.Alert, len(notifier.alerts))
+	copy(alerts, notifier.alerts)
+	return alerts
+}
@@ -0,0 +1,103 @@
+package alerts
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/services"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+)
+
+// LoadRules reads the rules of a JSON file, a list of models.AlertRule. A rule that is not valid stops the
+// start, an alert that never fires would go unnoticed.
+func LoadRules(path string) ([]models.AlertRule, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	rules := []models.AlertRule{}
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&rules); err != nil {
+		return nil, fmt.Errorf("%w: %s: %s", utils.ErrAlertRule, path, err)
+	}
+
+	names := map[string]bool{}
+	for i := range rules {
+		if rules[i].Name == "" {
+			rules[i].Name = rules[i].Series + "_" + rules[i].Type
+		}
+		if names[rules[i].Name] {
+			return nil, fmt.Errorf("%w: rule %q is repeated, the names should be unique", utils.ErrAlertRule, rules[i].Name)
+		}
+		names[rules[i].Name] = true
+		if err := validateRule(rules[i]); err != nil {
+			return nil, err
+		}
+	}
+	return rules, nil
+}
+
+func validateRule(rule models.AlertRule) error {
+	if _, err := services.ParseSeries(rule.Series); err != nil {
+		return fmt.Errorf("%w: rule %q: %s", utils.ErrAlertRule, rule.Name, err)
+	}
+	switch rule.Type {
+	case models.RuleChange:
+		if rule.Window != "" {
+			if _, err := utils.SubtractPeriod(utils.Now(), rule.Window); err != nil {
+				return fmt.Errorf("%w: rule %q: window: %s", utils.ErrAlertRule, rule.Name, err)
+			}
+		}
+		if rule.Percent < 0 {
+			return fmt.Errorf("%w: rule %q: the percent should not be negative", utils.ErrAlertRule, rule.Name)
+		}
+	case models.RuleAbove, models.RuleBelow:
+	case models.RuleOutsideBand:
+		if rule.Min >= rule.Max {
+			return fmt.Errorf("%w: rule %q: min should be less than max", utils.ErrAlertRule, rule.Name)
+		}
+	default:
+		return fmt.Errorf("%w: rule %q: type %q not supported. Should be %s, %s, %s or %s", utils.ErrAlertRule, rule.Name,
+			rule.Type, models.RuleChange, models.RuleAbove, models.RuleBelow, models.RuleOutsideBand)
+	}
+	return nil
+}
+
+// check tells whether the rule fires with the value and the reference it is compared with, and why. The
+// thresholds and the bands without reference fire on the value alone.
+func check(rule models.AlertRule, value float64, reference *float64) (bool, string) {
+	switch rule.Type {
+	case models.RuleChange:
+		if reference == nil {
+			return false, ""
+		}
+		change := value - *reference
+		if rule.Percent == 0 {
+			return change != 0, fmt.Sprintf("%s changed from %v to %v", rule.Series, *reference, value)
+		}
+		if *reference == 0 {
+			return false, ""
+		}
+		percent := change / math.Abs(*reference) * 100
+		return math.Abs(percent) >= rule.Percent, fmt.Sprintf("%s changed %+.2f%%, from %v to %v", rule.Series, percent, *reference, value)
+	case models.RuleAbove:
+		fires := value > rule.Threshold && (reference == nil || *reference <= rule.Threshold)
+		return fires, fmt.Sprintf("%s went above %v: %v", rule.Series, rule.Threshold, value)
+	case models.RuleBelow:
+		fires := value < rule.Threshold && (reference == nil || *reference >= rule.Threshold)
+		return fires, fmt.Sprintf("%s went below %v: %v", rule.Series, rule.Threshold, value)
+	case models.RuleOutsideBand:
+		outside := func(value float64) bool {
+			return value < rule.Min || value > rule.Max
+		}
+		fires := outside(value) && (reference == nil || !outside(*reference))
+		return fires, fmt.Sprintf("%s left the band %v to %v: %v", rule.Series, rule.Min, rule.Max, value)
+	}
+	return false, ""
+}
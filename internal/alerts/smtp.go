@@ -0,0 +1,106 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/configuration"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+)
+
+// SMTPNotifier emails the alerts. The connection is upgraded with STARTTLS when the server offers it, and
+// the credentials are only sent over TLS or to localhost.
+type SMTPNotifier struct {
+	address  string
+	host     string
+	username string
+	password string
+	from     string
+	to       []string
+}
+
+func NewSMTPNotifier(config configuration.AlertConfig) (*SMTPNotifier, error) {
+	if config.SMTPHost == "" || config.SMTPFrom == "" || len(config.SMTPTo) == 0 {
+		return nil, errors.New("the smtp notifier needs SMTP_HOST, SMTP_FROM and SMTP_TO")
+	}
+	return &SMTPNotifier{
+		address:  net.JoinHostPort(config.SMTPHost, config.SMTPPort),
+		host:     config.SMTPHost,
+		username: config.SMTPUsername,
+		password: config.SMTPPassword,
+		from:     config.SMTPFrom,
+		to:       config.SMTPTo,
+	}, nil
+}
+
+func (notifier *SMTPNotifier) Name() string {
+	return "smtp"
+}
+
+// Notify speaks SMTP by itself instead of smtp.SendMail, so the context bounds the whole conversation
+func (notifier *SMTPNotifier) Notify(ctx context.Context, alert models.Alert) error {
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", notifier.address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, notifier.host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: notifier.host}); err != nil {
+			return err
+		}
+	}
+	if notifier.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", notifier.username, notifier.password, notifier.host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(notifier.from); err != nil {
+		return err
+	}
+	for _, to := range notifier.to {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(notifier.message(alert)); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+func (notifier *SMTPNotifier) message(alert models.Alert) []byte {
+	message := &bytes.Buffer{}
+	fmt.Fprintf(message, "From: %s\r\n", notifier.from)
+	fmt.Fprintf(message, "To: %s\r\n", strings.Join(notifier.to, ", "))
+	fmt.Fprintf(message, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject(alert)))
+	fmt.Fprintf(message, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	message.WriteString(text(alert))
+	message.WriteString("\r\n")
+	return message.Bytes()
+}
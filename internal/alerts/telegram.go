@@ -0,0 +1,86 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/configuration"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+)
+
+// TelegramNotifier sends the alerts to a chat through a bot. TELEGRAM_API_URL can point to a local
+// server to try it without a bot.
+type TelegramNotifier struct {
+	url    string
+	chatID string
+	client *http.Client
+}
+
+type telegramMessage struct {
+	ChatID string `json:"chat_id"`
+	Text   string `json:"text"`
+}
+
+type telegramResponse struct {
+	OK          bool   `json:"ok"`
+	Description string `json:"description"`
+}
+
+func NewTelegramNotifier(config configuration.AlertConfig) (*TelegramNotifier, error) {
+	if config.TelegramBotToken == "" || config.TelegramChatID == "" {
+		return nil, errors.New("the telegram notifier needs TELEGRAM_BOT_TOKEN and TELEGRAM_CHAT_ID")
+	}
+	return &TelegramNotifier{
+		url:    strings.TrimSuffix(config.TelegramAPIURL, "/") + "/bot" + config.TelegramBotToken + "/sendMessage",
+		chatID: config.TelegramChatID,
+		client: &http.Client{},
+	}, nil
+}
+
+func (notifier *TelegramNotifier) Name() string {
+	return "telegram"
+}
+
+func (notifier *TelegramNotifier) Notify(ctx context.Context, alert models.Alert) error {
+	body, err := json.Marshal(telegramMessage{
+		ChatID: notifier.chatID,
+		Text:   subject(alert) + "\n" + text(alert),
+	})
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, notifier.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := notifier.client.Do(request)
+	if err != nil {
+		// the url has the token of the bot, it is not logged
+		return fmt.Errorf("telegram request failed: %w", unwrapURLError(err))
+	}
+	defer response.Body.Close()
+
+	result := telegramResponse{}
+	_ = json.NewDecoder(response.Body).Decode(&result)
+	if response.StatusCode != http.StatusOK || !result.OK {
+		return fmt.Errorf("telegram answered %s: %s", response.Status, result.Description)
+	}
+	return nil
+}
+
+// unwrapURLError drops the url of the request from the error
+func unwrapURLError(err error) error {
+	urlError := &url.Error{}
+	if errors.As(err, &urlError) {
+		return urlError.Err
+	}
+	return err
+}
@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/configuration"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+)
+
+// maximumCachedKeys bounds the lookups remembered, random keys must not grow the memory forever
+const maximumCachedKeys = 10000
+
+// KeyRepository is where the keys of the clients are stored
+type KeyRepository interface {
+	GetAPIKey(ctx context.Context, keyHash string) (*models.APIKey, error)
+}
+
+type cachedKey struct {
+	key     *models.APIKey
+	expires time.Time
+}
+
+// Keys looks up the API keys in the configuration and then in the repository. The lookups in the
+// repository, found or not, are remembered for a while.
+type Keys struct {
+	configured map[string]models.APIKey
+	repository KeyRepository
+	ttl        time.Duration
+
+	mutex  sync.Mutex
+	cached map[string]cachedKey
+}
+
+// Hash is how the keys are stored, only their SHA-256
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewKeys reads the keys of API_KEYS, a list of client:key. The repository is nil when the keys are
+// only configured.
+func NewKeys(config configuration.AuthConfig, repository KeyRepository) (*Keys, error) {
+	keys := &Keys{
+		configured: map[string]models.APIKey{},
+		repository: repository,
+		ttl:        config.KeyCacheTTL,
+		cached:     map[string]cachedKey{},
+	}
+	for i, entry := range config.APIKeys {
+		client, key, found := strings.Cut(strings.TrimSpace(entry), ":")
+		if !found || client == "" || key == "" {
+			return nil, fmt.Errorf("API_KEYS entry %d should be in format: client:key", i+1)
+		}
+		keys.configured[Hash(key)] = models.APIKey{Client: client, KeyHash: Hash(key), Active: true}
+	}
+	return keys, nil
+}
+
+// Lookup returns the client of a key, utils.ErrUnauthorized when the key is unknown or inactive
+func (keys *Keys) Lookup(ctx context.Context, key string) (*models.APIKey, error) {
+	keyHash := Hash(key)
+	if apiKey, found := keys.configured[keyHash]; found {
+		return &apiKey, nil
+	}
+	if keys.repository == nil {
+		return nil, utils.ErrUnauthorized
+	}
+
+	keys.mutex.Lock()
+	cached, found := keys.cached[keyHash]
+	keys.mutex.Unlock()
+	if !found || time.Now().After(cached.expires) {
+		apiKey, err := keys.repository.GetAPIKey(ctx, keyHash)
+		if err != nil && !errors.Is(err, utils.ErrNotFound) {
+			return nil, err
+		}
+		cached = cachedKey{key: apiKey, expires: time.Now().Add(keys.ttl)}
+		keys.mutex.Lock()
+		if len(keys.cached) >= maximumCachedKeys {
+			keys.cached = map[string]cachedKey{}
+		}
+		keys.cached[keyHash] = cached
+		keys.mutex.Unlock()
+	}
+
+	if cached.key == nil || !cached.key.Active {
+		return nil, utils.ErrUnauthorized
+	}
+	return cached.key, nil
+}
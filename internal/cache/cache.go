@@ -0,0 +1,117 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+)
+
+// entry is a cached response, the element of the LRU list keeps the key to evict it from the map
+type entry struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time
+}
+
+// call is a load in progress, the requests with the same key wait for it instead of loading again
+type call struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+// Cache is an in-memory LRU cache with a TTL per entry. The concurrent loads of the same key are
+// coalesced into one.
+type Cache struct {
+	mutex      sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	lru        *list.List
+	calls      map[string]*call
+	hits       metrics.Counter
+	misses     metrics.Counter
+}
+
+// New returns a cache of up to maxEntries responses, the least recently used ones are evicted first
+func New(maxEntries int, hits metrics.Counter, misses metrics.Counter) *Cache {
+	return &Cache{
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		lru:        list.New(),
+		calls:      map[string]*call{},
+		hits:       hits,
+		misses:     misses,
+	}
+}
+
+func (cache *Cache) get(key string, now time.Time) (interface{}, bool) {
+	element, ok := cache.entries[key]
+	if !ok {
+		return nil, false
+	}
+	cached := element.Value.(*entry)
+	if now.After(cached.expiresAt) {
+		cache.lru.Remove(element)
+		delete(cache.entries, key)
+		return nil, false
+	}
+	cache.lru.MoveToFront(element)
+	return cached.value, true
+}
+
+func (cache *Cache) set(key string, value interface{}, ttl time.Duration, now time.Time) {
+	if element, ok := cache.entries[key]; ok {
+		cached := element.Value.(*entry)
+		cached.value, cached.expiresAt = value, now.Add(ttl)
+		cache.lru.MoveToFront(element)
+		return
+	}
+	cache.entries[key] = cache.lru.PushFront(&entry{key: key, value: value, expiresAt: now.Add(ttl)})
+	for cache.maxEntries > 0 && cache.lru.Len() > cache.maxEntries {
+		oldest := cache.lru.Back()
+		cache.lru.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*entry).key)
+	}
+}
+
+// Len returns the number of entries cached, including the expired ones not evicted yet
+func (cache *Cache) Len() int {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	return cache.lru.Len()
+}
+
+// Do returns the cached value of the key or loads it. Only one load of a key runs at the same time, the
+// other callers get its result. The value is cached for ttl when cacheable returns true.
+func (cache *Cache) Do(name string, key string, ttl time.Duration, load func() (interface{}, error), cacheable func(interface{}) bool) (interface{}, error) {
+	cache.mutex.Lock()
+	if value, ok := cache.get(key, time.Now()); ok {
+		cache.mutex.Unlock()
+		cache.hits.With("route", name).Add(1)
+		return value, nil
+	}
+	if inProgress, ok := cache.calls[key]; ok {
+		cache.mutex.Unlock()
+		cache.hits.With("route", name).Add(1)
+		<-inProgress.done
+		return inProgress.value, inProgress.err
+	}
+	current := &call{done: make(chan struct{})}
+	cache.calls[key] = current
+	cache.mutex.Unlock()
+	cache.misses.With("route", name).Add(1)
+
+	current.value, current.err = load()
+
+	cache.mutex.Lock()
+	if current.err == nil && cacheable(current.value) {
+		cache.set(key, current.value, ttl, time.Now())
+	}
+	delete(cache.calls, key)
+	cache.mutex.Unlock()
+	close(current.done)
+
+	return current.value, current.err
+}
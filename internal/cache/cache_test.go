@@ -0,0 +1,162 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/metrics/discard"
+)
+
+type rangeRequest struct {
+	DateFrom time.Time
+	DateTo   time.Time
+	Series   []string
+}
+
+func cacheAll(interface{}) bool {
+	return true
+}
+
+// countingEndpoint answers the number of times it was called
+func countingEndpoint(calls *int32) func(context.Context, interface{}) (interface{}, error) {
+	return func(context.Context, interface{}) (interface{}, error) {
+		return atomic.AddInt32(calls, 1), nil
+	}
+}
+
+func TestMiddlewareCachesByRequest(t *testing.T) {
+	var calls int32
+	cache := New(10, discard.NewCounter(), discard.NewCounter())
+	endpoint := Middleware(context.Background(), cache, "series", time.Minute, cacheAll)(countingEndpoint(&calls))
+	ctx := context.Background()
+	morning := rangeRequest{DateFrom: time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC), DateTo: time.Date(2024, 1, 31, 8, 0, 0, 0, time.UTC), Series: []string{"tbp"}}
+	afternoon := morning
+	afternoon.DateTo = time.Date(2024, 1, 31, 17, 0, 0, 0, time.UTC)
+	other := morning
+	other.Series = []string{"tbp", "prime"}
+
+	for _, request := range []rangeRequest{morning, afternoon, other, other} {
+		if _, err := endpoint(ctx, request); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the hours of the dates are not part of the key, the series are
+	if calls != 2 {
+		t.Errorf("the endpoint was called %d times, expected 2", calls)
+	}
+}
+
+func TestMiddlewareWithoutTTLDoesNotCache(t *testing.T) {
+	var calls int32
+	cache := New(10, discard.NewCounter(), discard.NewCounter())
+	endpoint := Middleware(context.Background(), cache, "series", 0, cacheAll)(countingEndpoint(&calls))
+
+	endpoint(context.Background(), rangeRequest{})
+	endpoint(context.Background(), rangeRequest{})
+	if calls != 2 || cache.Len() != 0 {
+		t.Errorf("the endpoint was called %d times with %d entries cached", calls, cache.Len())
+	}
+}
+
+func TestCacheDoesNotKeepErrorsNorUncacheableValues(t *testing.T) {
+	cache := New(10, discard.NewCounter(), discard.NewCounter())
+	failure := errors.New("the BCCR is down")
+
+	if _, err := cache.Do("series", "a", time.Minute, func() (interface{}, error) { return nil, failure }, cacheAll); err != failure {
+		t.Errorf("the error was %v", err)
+	}
+	cache.Do("series", "b", time.Minute, func() (interface{}, error) { return "empty", nil }, func(interface{}) bool { return false })
+	if cache.Len() != 0 {
+		t.Errorf("%d entries were cached", cache.Len())
+	}
+}
+
+func TestCacheExpiresAndEvictsTheLeastRecentlyUsed(t *testing.T) {
+	cache := New(2, discard.NewCounter(), discard.NewCounter())
+	now := time.Now()
+	cache.set("a", 1, time.Minute, now)
+	cache.set("b", 2, time.Minute, now)
+	cache.get("a", now)
+	cache.set("c", 3, time.Minute, now)
+
+	if _, ok := cache.get("b", now); ok {
+		t.Error("the least recently used entry was not evicted")
+	}
+	if value, ok := cache.get("a", now); !ok || value != 1 {
+		t.Errorf("the entry used recently is %v", value)
+	}
+	if _, ok := cache.get("c", now.Add(2*time.Minute)); ok {
+		t.Error("an expired entry was returned")
+	}
+	if cache.Len() != 1 {
+		t.Errorf("%d entries, the expired one was not removed", cache.Len())
+	}
+}
+
+func TestCacheCoalescesTheConcurrentLoads(t *testing.T) {
+	cache := New(10, discard.NewCounter(), discard.NewCounter())
+	var loads int32
+	release := make(chan struct{})
+	load := func() (interface{}, error) {
+		atomic.AddInt32(&loads, 1)
+		<-release
+		return "rates", nil
+	}
+
+	var group sync.WaitGroup
+	values := make([]interface{}, 5)
+	for i := range values {
+		group.Add(1)
+		go func(i int) {
+			defer group.Done()
+			values[i], _ = cache.Do("series", "a", time.Minute, load, cacheAll)
+		}(i)
+	}
+	// the load does not finish until the other callers had time to wait for it, the late ones hit the cache
+	for {
+		cache.mutex.Lock()
+		_, loading := cache.calls["a"]
+		cache.mutex.Unlock()
+		if loading {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	group.Wait()
+
+	if loads != 1 {
+		t.Errorf("%d loads, expected 1", loads)
+	}
+	for _, value := range values {
+		if value != "rates" {
+			t.Errorf("a caller got %v", value)
+		}
+	}
+}
+
+func TestMiddlewareLoadsWithTheBaseContext(t *testing.T) {
+	type key struct{}
+	base, stop := context.WithCancel(context.Background())
+	defer stop()
+	cache := New(10, discard.NewCounter(), discard.NewCounter())
+	endpoint := Middleware(base, cache, "series", time.Minute, cacheAll)(func(ctx context.Context, _ interface{}) (interface{}, error) {
+		return []interface{}{ctx.Err(), ctx.Value(key{})}, nil
+	})
+
+	// the request was cancelled, the load keeps its values but not its cancellation
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "request-id"))
+	cancel()
+	response, err := endpoint(ctx, rangeRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values := response.([]interface{}); values[0] != nil || values[1] != "request-id" {
+		t.Errorf("the load saw the error %v and the value %v", values[0], values[1])
+	}
+}
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Key identifies a request of an endpoint. The dates only keep the day, the data is published daily at
+// most and the default ranges end now.
+func Key(name string, request interface{}) string {
+	builder := &strings.Builder{}
+	builder.WriteString(name)
+	writeKey(builder, reflect.ValueOf(request))
+	return builder.String()
+}
+
+func writeKey(builder *strings.Builder, value reflect.Value) {
+	if !value.IsValid() {
+		builder.WriteString("|nil")
+		return
+	}
+	if value.Type() == timeType {
+		builder.WriteString("|" + value.Interface().(time.Time).Format("2006-01-02"))
+		return
+	}
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			builder.WriteString("|nil")
+			return
+		}
+		writeKey(builder, value.Elem())
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if value.Type().Field(i).PkgPath != "" {
+				continue
+			}
+			writeKey(builder, value.Field(i))
+		}
+	case reflect.Slice, reflect.Array:
+		builder.WriteString("|[")
+		for i := 0; i < value.Len(); i++ {
+			writeKey(builder, value.Index(i))
+		}
+		builder.WriteString("]")
+	default:
+		builder.WriteString(fmt.Sprintf("|%v", value.Interface()))
+	}
+}
+
+// detachedContext has the values of the request that started a load (its id, its span) but the
+// cancellation of the base context
+type detachedContext struct {
+	context.Context
+	values context.Context
+}
+
+func (ctx detachedContext) Value(key interface{}) interface{} {
+	return ctx.values.Value(key)
+}
+
+// Middleware caches the responses of an endpoint for ttl. The responses are shared between requests, the
+// endpoints must not modify them after they are returned. The loads run with the base context, they do
+// not stop when the request that started them is cancelled, only when the service stops.
+func Middleware(base context.Context, cache *Cache, name string, ttl time.Duration, cacheable func(interface{}) bool) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		if ttl <= 0 {
+			return next
+		}
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			return cache.Do(name, Key(name, request), ttl, func() (interface{}, error) {
+				return next(detachedContext{Context: base, values: ctx}, request)
+			}, cacheable)
+		}
+	}
+}
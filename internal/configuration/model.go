@@ -1,16 +1,49 @@
 package configuration
 
-import "github.com/caarlos0/env"
+import (
+	"time"
+
+	"github.com/caarlos0/env"
+)
 
 type ServerConfig struct {
 	Address  AddressConfig
+	CORS     CORSConfig
 	Scrapper ScrapperConfig
 	Database DatabaseConfig
+	Cache    CacheConfig
+	Health   HealthConfig
+	Tracing  TracingConfig
+	Log      LogConfig
+	Auth     AuthConfig
+	Limits   RateLimitConfig
+	GraphQL  GraphQLConfig
+	Stream   StreamConfig
+	Webhooks WebhookConfig
+	Alerts   AlertConfig
+	Timezone string `env:"TIMEZONE" envDefault:"America/Costa_Rica"`
 }
 
 type AddressConfig struct {
-	Port           string   `env:"PORT" envDefault:"8081"`
-	AllowedOrigins []string `env:"ALLOWED_ORIGINS"`
+	Port              string        `env:"PORT" envDefault:"8081"`
+	GRPCPort          string        `env:"GRPC_PORT"`
+	GRPCMaxRangeYears int           `env:"GRPC_MAX_RANGE_YEARS" envDefault:"5"`
+	ReadTimeout       time.Duration `env:"HTTP_READ_TIMEOUT" envDefault:"10s"`
+	ReadHeaderTimeout time.Duration `env:"HTTP_READ_HEADER_TIMEOUT" envDefault:"5s"`
+	WriteTimeout      time.Duration `env:"HTTP_WRITE_TIMEOUT" envDefault:"120s"`
+	IdleTimeout       time.Duration `env:"HTTP_IDLE_TIMEOUT" envDefault:"120s"`
+	ShutdownTimeout   time.Duration `env:"SHUTDOWN_TIMEOUT" envDefault:"30s"`
+	LegacyDeprecated  string        `env:"LEGACY_ROUTES_DEPRECATED"`
+	LegacySunset      string        `env:"LEGACY_ROUTES_SUNSET"`
+}
+
+type CORSConfig struct {
+	AllowedOrigins   []string      `env:"ALLOWED_ORIGINS"`
+	AllowedMethods   []string      `env:"CORS_ALLOWED_METHODS" envDefault:"GET,POST,DELETE,OPTIONS"`
+	AllowedHeaders   []string      `env:"CORS_ALLOWED_HEADERS" envDefault:"Accept,Accept-Language,Content-Type,Authorization,X-API-Key,X-Request-ID,X-Requested-With,If-None-Match,If-Modified-Since"`
+	ExposedHeaders   []string      `env:"CORS_EXPOSED_HEADERS" envDefault:"Content-Disposition,ETag,Last-Modified,Retry-After,X-Request-ID"`
+	MaxAge           time.Duration `env:"CORS_MAX_AGE" envDefault:"10m"`
+	AllowCredentials bool          `env:"CORS_ALLOW_CREDENTIALS" envDefault:"false"`
 }
 
 type ScrapperConfig struct {
@@ -21,6 +54,101 @@ type ScrapperConfig struct {
 	InflationCostaRicaUrl string `env:"INFLATION_COSTA_RICA_URL"`
 	InflationUSAUrl       string `env:"INFLATION_USA_URL"`
 	TreasuryRateUSAUrl    string `env:"TREASURY_RATE_USA_URL"`
+	TreasuryYieldCurveUrl string `env:"TREASURY_YIELD_CURVE_URL"`
+	MonexExchangeRateUrl  string `env:"MONEX_URL"`
+	EuroExchangeRateUrl   string `env:"EURO_EXCHANGE_RATE_URL"`
+	ReferenceRateUrl      string `env:"TRI_URL"`
+}
+
+type CacheConfig struct {
+	Enabled    bool          `env:"CACHE_ENABLED" envDefault:"true"`
+	MaxEntries int           `env:"CACHE_MAX_ENTRIES" envDefault:"1000"`
+	DailyTTL   time.Duration `env:"CACHE_TTL_DAILY" envDefault:"15m"`
+	WeeklyTTL  time.Duration `env:"CACHE_TTL_WEEKLY" envDefault:"1h"`
+	MonthlyTTL time.Duration `env:"CACHE_TTL_MONTHLY" envDefault:"6h"`
+}
+
+type HealthConfig struct {
+	Timeout   time.Duration `env:"HEALTH_TIMEOUT" envDefault:"3s"`
+	CheckTTL  time.Duration `env:"HEALTH_CHECK_TTL" envDefault:"30s"`
+	ProbeBCCR bool          `env:"HEALTH_PROBE_BCCR" envDefault:"false"`
+	BCCRUrl   string        `env:"HEALTH_BCCR_URL" envDefault:"https://gee.bccr.fi.cr/indicadoreseconomicos/"`
+}
+
+type TracingConfig struct {
+	Enabled     bool    `env:"TRACING_ENABLED" envDefault:"false"`
+	Endpoint    string  `env:"OTEL_EXPORTER_OTLP_ENDPOINT" envDefault:"localhost:4318"`
+	Insecure    bool    `env:"OTEL_EXPORTER_OTLP_INSECURE" envDefault:"true"`
+	ServiceName string  `env:"OTEL_SERVICE_NAME" envDefault:"libertadfinanciera-backend"`
+	SampleRatio float64 `env:"TRACING_SAMPLE_RATIO" envDefault:"1"`
+}
+
+type LogConfig struct {
+	Level              string        `env:"LOG_LEVEL" envDefault:"info"`
+	Format             string        `env:"LOG_FORMAT" envDefault:"json"`
+	SamplingFirst      int           `env:"LOG_SAMPLING_FIRST" envDefault:"10"`
+	SamplingThereafter int           `env:"LOG_SAMPLING_THEREAFTER" envDefault:"100"`
+	SamplingInterval   time.Duration `env:"LOG_SAMPLING_INTERVAL" envDefault:"1m"`
+	File               string        `env:"LOG_FILE"`
+	FileMaxSizeMB      int           `env:"LOG_FILE_MAX_SIZE_MB" envDefault:"100"`
+	FileMaxBackups     int           `env:"LOG_FILE_MAX_BACKUPS" envDefault:"5"`
+}
+
+type AuthConfig struct {
+	Required           bool          `env:"AUTH_REQUIRED" envDefault:"false"`
+	APIKeys            []string      `env:"API_KEYS"`
+	KeysFromRepository bool          `env:"AUTH_KEYS_FROM_REPOSITORY" envDefault:"false"`
+	KeyCacheTTL        time.Duration `env:"AUTH_KEY_CACHE_TTL" envDefault:"5m"`
+}
+
+type RateLimitConfig struct {
+	Enabled        bool    `env:"RATE_LIMIT_ENABLED" envDefault:"true"`
+	IPPerMinute    float64 `env:"RATE_LIMIT_IP_PER_MINUTE" envDefault:"60"`
+	IPBurst        float64 `env:"RATE_LIMIT_IP_BURST" envDefault:"60"`
+	KeyPerMinute   float64 `env:"RATE_LIMIT_KEY_PER_MINUTE" envDefault:"600"`
+	KeyBurst       float64 `env:"RATE_LIMIT_KEY_BURST" envDefault:"600"`
+	DaysPerToken   int     `env:"RATE_LIMIT_DAYS_PER_TOKEN" envDefault:"90"`
+	TrustedProxies int     `env:"TRUSTED_PROXIES" envDefault:"0"`
+}
+
+type GraphQLConfig struct {
+	MaxComplexity int `env:"GRAPHQL_MAX_COMPLEXITY" envDefault:"200"`
+	MaxDepth      int `env:"GRAPHQL_MAX_DEPTH" envDefault:"6"`
+}
+
+type StreamConfig struct {
+	PollInterval            time.Duration `env:"STREAM_POLL_INTERVAL" envDefault:"1m"`
+	History                 int           `env:"STREAM_HISTORY" envDefault:"1000"`
+	Heartbeat               time.Duration `env:"STREAM_HEARTBEAT" envDefault:"15s"`
+	MaxSubscribers          int           `env:"STREAM_MAX_SUBSCRIBERS" envDefault:"1000"`
+	MaxSubscribersPerClient int           `env:"STREAM_MAX_SUBSCRIBERS_PER_CLIENT" envDefault:"5"`
+}
+
+type WebhookConfig struct {
+	Timeout             time.Duration `env:"WEBHOOK_TIMEOUT" envDefault:"10s"`
+	MaxAttempts         int           `env:"WEBHOOK_MAX_ATTEMPTS" envDefault:"5"`
+	RetryBackoff        time.Duration `env:"WEBHOOK_RETRY_BACKOFF" envDefault:"10s"`
+	Workers             int           `env:"WEBHOOK_WORKERS" envDefault:"4"`
+	QueueSize           int           `env:"WEBHOOK_QUEUE_SIZE" envDefault:"1000"`
+	MaxPerClient        int           `env:"WEBHOOK_MAX_PER_CLIENT" envDefault:"20"`
+	DeadLetters         int           `env:"WEBHOOK_DEAD_LETTERS" envDefault:"1000"`
+	AllowPrivateTargets bool          `env:"WEBHOOK_ALLOW_PRIVATE_TARGETS" envDefault:"false"`
+}
+
+// AlertConfig enables the alerts when the file of rules is set. The notifiers are log, smtp and telegram.
+type AlertConfig struct {
+	RulesFile        string        `env:"ALERT_RULES_FILE"`
+	Notifiers        []string      `env:"ALERT_NOTIFIERS" envDefault:"log"`
+	NotifyTimeout    time.Duration `env:"ALERT_NOTIFY_TIMEOUT" envDefault:"10s"`
+	SMTPHost         string        `env:"SMTP_HOST"`
+	SMTPPort         string        `env:"SMTP_PORT" envDefault:"587"`
+	SMTPUsername     string        `env:"SMTP_USERNAME"`
+	SMTPPassword     string        `env:"SMTP_PASSWORD"`
+	SMTPFrom         string        `env:"SMTP_FROM"`
+	SMTPTo           []string      `env:"SMTP_TO"`
+	TelegramBotToken string        `env:"TELEGRAM_BOT_TOKEN"`
+	TelegramChatID   string        `env:"TELEGRAM_CHAT_ID"`
+	TelegramAPIURL   string        `env:"TELEGRAM_API_URL" envDefault:"https://api.telegram.org"`
 }
 
 type DatabaseConfig struct {
@@ -36,11 +164,44 @@ func Read() (*ServerConfig, error) {
 	if err := env.Parse(&config.Address); err != nil {
 		return nil, err
 	}
+	if err := env.Parse(&config.CORS); err != nil {
+		return nil, err
+	}
 	if err := env.Parse(&config.Scrapper); err != nil {
 		return nil, err
 	}
 	if err := env.Parse(&config.Database); err != nil {
 		return nil, err
 	}
+	if err := env.Parse(&config.Cache); err != nil {
+		return nil, err
+	}
+	if err := env.Parse(&config.Health); err != nil {
+		return nil, err
+	}
+	if err := env.Parse(&config.Tracing); err != nil {
+		return nil, err
+	}
+	if err := env.Parse(&config.Log); err != nil {
+		return nil, err
+	}
+	if err := env.Parse(&config.Auth); err != nil {
+		return nil, err
+	}
+	if err := env.Parse(&config.Limits); err != nil {
+		return nil, err
+	}
+	if err := env.Parse(&config.GraphQL); err != nil {
+		return nil, err
+	}
+	if err := env.Parse(&config.Stream); err != nil {
+		return nil, err
+	}
+	if err := env.Parse(&config.Webhooks); err != nil {
+		return nil, err
+	}
+	if err := env.Parse(&config.Alerts); err != nil {
+		return nil, err
+	}
 	return &config, nil
 }
@@ -0,0 +1,24 @@
+package export
+
+import "github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+
+// comparisonTable has a column per series, so the comparison can be exported like any other series
+func comparisonTable(comparison models.SeriesComparison) Table {
+	table := Table{
+		Columns:  append([]string{"date"}, comparison.Series...),
+		DateFrom: comparison.DateFrom,
+		DateTo:   comparison.DateTo,
+	}
+	for _, comparisonRow := range comparison.Rows {
+		row := []interface{}{comparisonRow.Date}
+		for _, name := range comparison.Series {
+			if value := comparisonRow.Values[name]; value != nil {
+				row = append(row, *value)
+			} else {
+				row = append(row, "")
+			}
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table
+}
@@ -0,0 +1,49 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// utf8BOM lets spreadsheet applications detect the accents of the spanish headers
+const utf8BOM = "\xef\xbb\xbf"
+
+func formatCell(value interface{}) string {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format("2006-01-02")
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case string:
+		return v
+	default:
+		return ""
+	}
+}
+
+// WriteCSV writes the table with the headers in the language requested
+func WriteCSV(w io.Writer, table Table, language string) error {
+	if _, err := io.WriteString(w, utf8BOM); err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(table.Headers(language)); err != nil {
+		return err
+	}
+	for _, row := range table.Rows {
+		record := make([]string, 0, len(row))
+		for _, value := range row {
+			record = append(record, formatCell(value))
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,69 @@
+package export
+
+import "strings"
+
+const (
+	LanguageSpanish = "es"
+	LanguageEnglish = "en"
+)
+
+// headers has the column names of every field of the series, by language
+var headers = map[string]map[string]string{
+	"date":                 {LanguageSpanish: "Fecha", LanguageEnglish: "Date"},
+	"value":                {LanguageSpanish: "Valor", LanguageEnglish: "Value"},
+	"sale":                 {LanguageSpanish: "Venta", LanguageEnglish: "Sale"},
+	"buy":                  {LanguageSpanish: "Compra", LanguageEnglish: "Buy"},
+	"maturity":             {LanguageSpanish: "Plazo", LanguageEnglish: "Maturity"},
+	"inverted":             {LanguageSpanish: "Invertida", LanguageEnglish: "Inverted"},
+	"currency":             {LanguageSpanish: "Moneda", LanguageEnglish: "Currency"},
+	"amount":               {LanguageSpanish: "Monto", LanguageEnglish: "Amount"},
+	"adjusted_amount":      {LanguageSpanish: "Monto ajustado", LanguageEnglish: "Adjusted amount"},
+	"cumulative_inflation": {LanguageSpanish: "Inflación acumulada", LanguageEnglish: "Cumulative inflation"},
+	"months":               {LanguageSpanish: "Meses", LanguageEnglish: "Months"},
+	"date_from":            {LanguageSpanish: "Fecha inicial", LanguageEnglish: "Date from"},
+	"date_to":              {LanguageSpanish: "Fecha final", LanguageEnglish: "Date to"},
+	"number":               {LanguageSpanish: "Número", LanguageEnglish: "Number"},
+	"rate":                 {LanguageSpanish: "Tasa", LanguageEnglish: "Rate"},
+	"payment":              {LanguageSpanish: "Cuota", LanguageEnglish: "Payment"},
+	"interest":             {LanguageSpanish: "Intereses", LanguageEnglish: "Interest"},
+	"principal":            {LanguageSpanish: "Amortización", LanguageEnglish: "Principal"},
+	"balance":              {LanguageSpanish: "Saldo", LanguageEnglish: "Balance"},
+	"exchange_rate":        {LanguageSpanish: "Tipo de cambio", LanguageEnglish: "Exchange rate"},
+	"monex":                {LanguageSpanish: "MONEX", LanguageEnglish: "MONEX"},
+	"euro":                 {LanguageSpanish: "Euro", LanguageEnglish: "Euro"},
+	"tbp":                  {LanguageSpanish: "Tasa básica pasiva", LanguageEnglish: "Basic passive rate"},
+	"tri":                  {LanguageSpanish: "Tasa de referencia", LanguageEnglish: "Reference deposit rate"},
+	"mpr":                  {LanguageSpanish: "Tasa de política monetaria", LanguageEnglish: "Monetary policy rate"},
+	"prime":                {LanguageSpanish: "Tasa prime", LanguageEnglish: "Prime rate"},
+	"treasury_usa":         {LanguageSpanish: "Bonos del Tesoro EEUU", LanguageEnglish: "USA treasury rate"},
+	"inflation_cr":         {LanguageSpanish: "Inflación Costa Rica", LanguageEnglish: "Costa Rica inflation"},
+	"inflation_usa":        {LanguageSpanish: "Inflación EEUU", LanguageEnglish: "USA inflation"},
+}
+
+// Language picks the language of the headers from a lang param or an Accept-Language header,
+// spanish by default
+func Language(acceptLanguage string) string {
+	for _, language := range strings.Split(acceptLanguage, ",") {
+		language = strings.ToLower(strings.TrimSpace(strings.Split(language, ";")[0]))
+		if strings.HasPrefix(language, LanguageEnglish) {
+			return LanguageEnglish
+		}
+		if strings.HasPrefix(language, LanguageSpanish) {
+			return LanguageSpanish
+		}
+	}
+	return LanguageSpanish
+}
+
+// Headers translates the columns of a table, the unknown ones keep their JSON name
+func (table Table) Headers(language string) []string {
+	result := make([]string, 0, len(table.Columns))
+	for _, column := range table.Columns {
+		header, ok := headers[column][language]
+		if !ok {
+			header = column
+		}
+		result = append(result, header)
+	}
+	return result
+}
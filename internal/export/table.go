@@ -0,0 +1,202 @@
+package export
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+)
+
+// Table is the tabular representation of a series response
+type Table struct {
+	Columns  []string
+	Rows     [][]interface{}
+	DateFrom time.Time
+	DateTo   time.Time
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// jsonName returns the name a struct field has in the JSON responses
+func jsonName(field reflect.StructField) (string, bool) {
+	if field.PkgPath != "" {
+		return "", false
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	name := strings.Split(tag, ",")[0]
+	if name == "" {
+		name = field.Name
+	}
+	return name, true
+}
+
+func indirect(value reflect.Value) reflect.Value {
+	for value.IsValid() && (value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface) {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+		value = value.Elem()
+	}
+	return value
+}
+
+func isStructSlice(value reflect.Value) bool {
+	if value.Kind() != reflect.Slice {
+		return false
+	}
+	elem := value.Type().Elem()
+	for elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	return elem.Kind() == reflect.Struct && elem != timeType
+}
+
+// FromResponse builds the table of the "data" field of a response. A list of structs is a row per item,
+// a single struct is one row unless it wraps a list of structs (e.g. the payments of a schedule or the
+// yields of a curve), in that case the list is the table and the date of the struct is repeated per row.
+// The comparisons of series have a column per series.
+func FromResponse(response interface{}) (Table, bool) {
+	value := indirect(reflect.ValueOf(response))
+	if !value.IsValid() || value.Kind() != reflect.Struct {
+		return Table{}, false
+	}
+
+	var data reflect.Value
+	for i := 0; i < value.NumField(); i++ {
+		if name, ok := jsonName(value.Type().Field(i)); ok && name == "data" {
+			data = indirect(value.Field(i))
+		}
+	}
+	if !data.IsValid() {
+		return Table{}, false
+	}
+	if comparison, ok := data.Interface().(models.SeriesComparison); ok {
+		return comparisonTable(comparison), true
+	}
+
+	table := Table{}
+	switch {
+	case isStructSlice(data):
+		for i := 0; i < data.Len(); i++ {
+			table.addRow(indirect(data.Index(i)), nil)
+		}
+	case data.Kind() == reflect.Struct && data.Type() != timeType:
+		nested, parent := splitNestedList(data)
+		if !nested.IsValid() {
+			table.addRow(data, nil)
+			break
+		}
+		for i := 0; i < nested.Len(); i++ {
+			table.addRow(indirect(nested.Index(i)), parent)
+		}
+	default:
+		return Table{}, false
+	}
+
+	return table, true
+}
+
+type cell struct {
+	column string
+	value  interface{}
+}
+
+// splitNestedList returns the only list of structs of a struct and its date, if there is one
+func splitNestedList(value reflect.Value) (reflect.Value, []cell) {
+	var nested reflect.Value
+	parent := []cell{}
+	for i := 0; i < value.NumField(); i++ {
+		name, ok := jsonName(value.Type().Field(i))
+		if !ok {
+			continue
+		}
+		field := indirect(value.Field(i))
+		if isStructSlice(field) {
+			if nested.IsValid() {
+				return reflect.Value{}, nil
+			}
+			nested = field
+			continue
+		}
+		if field.IsValid() && field.Type() == timeType && name == "date" {
+			parent = append(parent, cell{column: name, value: field.Interface()})
+		}
+	}
+	return nested, parent
+}
+
+func (table *Table) addRow(value reflect.Value, parent []cell) {
+	if !value.IsValid() || value.Kind() != reflect.Struct {
+		return
+	}
+
+	// the date goes first, like in any spreadsheet of a time series
+	cells := append([]cell{}, parent...)
+	others := []cell{}
+	for i := 0; i < value.NumField(); i++ {
+		name, ok := jsonName(value.Type().Field(i))
+		if !ok {
+			continue
+		}
+		if name == "date" {
+			cells = append(cells, cell{column: name, value: toCell(value.Field(i))})
+		} else {
+			others = append(others, cell{column: name, value: toCell(value.Field(i))})
+		}
+	}
+	cells = append(cells, others...)
+
+	if len(table.Rows) == 0 {
+		for _, cell := range cells {
+			table.Columns = append(table.Columns, cell.column)
+		}
+	}
+
+	row := make([]interface{}, 0, len(cells))
+	for _, cell := range cells {
+		row = append(row, cell.value)
+		if date, ok := cell.value.(time.Time); ok && cell.column == "date" {
+			if table.DateFrom.IsZero() || date.Before(table.DateFrom) {
+				table.DateFrom = date
+			}
+			if date.After(table.DateTo) {
+				table.DateTo = date
+			}
+		}
+	}
+	table.Rows = append(table.Rows, row)
+}
+
+// toCell keeps numbers, booleans and dates typed so the workbooks can format them
+func toCell(value reflect.Value) interface{} {
+	value = indirect(value)
+	if !value.IsValid() {
+		return ""
+	}
+	if value.Type() == timeType {
+		return value.Interface()
+	}
+	switch value.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return value.Float()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint())
+	case reflect.Bool:
+		return value.Bool()
+	case reflect.String:
+		return value.String()
+	default:
+		encoded, err := json.Marshal(value.Interface())
+		if err != nil {
+			return ""
+		}
+		return string(encoded)
+	}
+}
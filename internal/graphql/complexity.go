@@ -0,0 +1,141 @@
+package graphql
+
+import (
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/ratelimit"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/services"
+)
+
+// complexity measures a query in the tokens of the rate limits before it is executed. Every field costs a
+// token and the ranges cost like the HTTP routes, one more token per daysPerToken days. The fields of
+// indicators are charged once per indicator asked for.
+type complexity struct {
+	document     *ast.Document
+	variables    map[string]interface{}
+	daysPerToken int
+	cost         float64
+	depth        int
+}
+
+// measure returns the cost and the depth of the operation, the document must be valid already
+func measure(document *ast.Document, operationName string, variables map[string]interface{}, daysPerToken int) (float64, int) {
+	operation := operationOf(document, operationName)
+	if operation == nil {
+		return 0, 0
+	}
+
+	values := map[string]interface{}{}
+	for _, definition := range operation.VariableDefinitions {
+		if definition.DefaultValue != nil {
+			values[definition.Variable.Name.Value] = valueOf(definition.DefaultValue, nil)
+		}
+	}
+	for name, value := range variables {
+		values[name] = value
+	}
+
+	measured := &complexity{document: document, variables: values, daysPerToken: daysPerToken}
+	measured.selectionSet(operation.SelectionSet, 1, 1)
+	return measured.cost, measured.depth
+}
+
+func operationOf(document *ast.Document, operationName string) *ast.OperationDefinition {
+	var found *ast.OperationDefinition
+	for _, definition := range document.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if operationName == "" && found != nil {
+			// several operations need a name, the executor explains it
+			return nil
+		}
+		if operationName == "" || (operation.Name != nil && operation.Name.Value == operationName) {
+			found = operation
+		}
+	}
+	return found
+}
+
+func (measured *complexity) selectionSet(selectionSet *ast.SelectionSet, multiplier float64, depth int) {
+	if selectionSet == nil {
+		return
+	}
+	for _, selection := range selectionSet.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			measured.field(selection, multiplier, depth)
+		case *ast.InlineFragment:
+			measured.selectionSet(selection.SelectionSet, multiplier, depth)
+		case *ast.FragmentSpread:
+			if fragment := measured.fragment(selection.Name.Value); fragment != nil {
+				measured.selectionSet(fragment.SelectionSet, multiplier, depth)
+			}
+		}
+	}
+}
+
+func (measured *complexity) field(field *ast.Field, multiplier float64, depth int) {
+	name := field.Name.Value
+	// the introspection of the schema does not scrape anything
+	if strings.HasPrefix(name, "__") {
+		return
+	}
+	if depth > measured.depth {
+		measured.depth = depth
+	}
+
+	arguments := map[string]interface{}{}
+	for _, argument := range field.Arguments {
+		arguments[argument.Name.Value] = valueOf(argument.Value, measured.variables)
+	}
+
+	cost := 1.0
+	switch name {
+	case "observations", "stats":
+		// an invalid range costs a token, the resolver rejects it
+		if dateFrom, dateTo, err := argumentsRange(arguments); err == nil {
+			cost = ratelimit.RangeCost(dateFrom, dateTo, measured.daysPerToken)
+		}
+	case "indicators":
+		names, ok := arguments["names"].([]interface{})
+		if !ok {
+			names = make([]interface{}, len(services.ComparableSeries()))
+		}
+		measured.cost += multiplier * cost
+		measured.selectionSet(field.SelectionSet, multiplier*float64(len(names)), depth+1)
+		return
+	}
+	measured.cost += multiplier * cost
+	measured.selectionSet(field.SelectionSet, multiplier, depth+1)
+}
+
+func (measured *complexity) fragment(name string) *ast.FragmentDefinition {
+	for _, definition := range measured.document.Definitions {
+		if fragment, ok := definition.(*ast.FragmentDefinition); ok && fragment.Name.Value == name {
+			return fragment
+		}
+	}
+	return nil
+}
+
+// valueOf reads the arguments that change the cost, the rest are validated by the executor
+func valueOf(value ast.Value, variables map[string]interface{}) interface{} {
+	switch value := value.(type) {
+	case *ast.Variable:
+		return variables[value.Name.Value]
+	case *ast.StringValue:
+		return value.Value
+	case *ast.EnumValue:
+		return value.Value
+	case *ast.ListValue:
+		values := make([]interface{}, 0, len(value.Values))
+		for _, item := range value.Values {
+			values = append(values, valueOf(item, variables))
+		}
+		return values
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/configuration"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/services"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+)
+
+// Request is a query parsed and validated, its cost is known before it is executed. A request with
+// errors is answered with them and not executed.
+type Request struct {
+	document      *ast.Document
+	operationName string
+	variables     map[string]interface{}
+	complexity    float64
+	errors        []gqlerrors.FormattedError
+}
+
+// Cost is the complexity of the query, it was measured with the same days per token of the rate limits
+func (request Request) Cost(int) float64 {
+	return request.complexity
+}
+
+// Executor parses, limits and executes the queries
+type Executor struct {
+	schema       graphql.Schema
+	config       configuration.GraphQLConfig
+	daysPerToken int
+}
+
+func NewExecutor(service *services.ServiceAPI, config configuration.GraphQLConfig, daysPerToken int) (*Executor, error) {
+	schema, err := NewSchema(service)
+	if err != nil {
+		return nil, err
+	}
+	return &Executor{schema: schema, config: config, daysPerToken: daysPerToken}, nil
+}
+
+// Parse rejects the queries that are not valid, too deep or too complex
+func (executor *Executor) Parse(query string, operationName string, variables map[string]interface{}) Request {
+	document, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{
+		Body: []byte(query),
+		Name: "GraphQL request",
+	})})
+	if err != nil {
+		return Request{errors: gqlerrors.FormatErrors(err)}
+	}
+
+	validation := graphql.ValidateDocument(&executor.schema, document, nil)
+	if !validation.IsValid {
+		return Request{errors: validation.Errors}
+	}
+
+	complexity, depth := measure(document, operationName, variables, executor.daysPerToken)
+	if depth > executor.config.MaxDepth {
+		return Request{errors: gqlerrors.FormatErrors(fmt.Errorf("%w: the depth %d is more than the maximum %d",
+			utils.ErrQueryComplexity, depth, executor.config.MaxDepth))}
+	}
+	if complexity > float64(executor.config.MaxComplexity) {
+		return Request{errors: gqlerrors.FormatErrors(fmt.Errorf("%w: the complexity %.0f is more than the maximum %d. Ask for fewer indicators or shorter ranges",
+			utils.ErrQueryComplexity, complexity, executor.config.MaxComplexity))}
+	}
+
+	return Request{
+		document:      document,
+		operationName: operationName,
+		variables:     variables,
+		complexity:    complexity,
+	}
+}
+
+// Endpoint executes the requests. The result has the errors of the fields, like GraphQL expects
+func (executor *Executor) Endpoint() endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(Request)
+		if len(req.errors) > 0 {
+			return &graphql.Result{Errors: req.errors}, nil
+		}
+		return graphql.Execute(graphql.ExecuteParams{
+			Schema:        executor.schema,
+			AST:           req.document,
+			OperationName: req.operationName,
+			Args:          req.variables,
+			Context:       withLoader(ctx),
+		}), nil
+	}
+}
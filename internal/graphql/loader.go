@@ -0,0 +1,58 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+)
+
+type loaderKey struct{}
+
+// loader scrapes every series of a query concurrently and only once, the fields that need the same
+// range wait for the same call
+type loader struct {
+	ctx   context.Context
+	mutex sync.Mutex
+	calls map[string]*call
+}
+
+type call struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+func withLoader(ctx context.Context) context.Context {
+	return context.WithValue(ctx, loaderKey{}, &loader{ctx: ctx, calls: map[string]*call{}})
+}
+
+func loaderFrom(ctx context.Context) *loader {
+	if loader, ok := ctx.Value(loaderKey{}).(*loader); ok {
+		return loader
+	}
+	return withLoader(ctx).Value(loaderKey{}).(*loader)
+}
+
+// load starts the fetch in the background and returns a thunk, the executor resolves the thunks once
+// every field of the level has started its own
+func (loader *loader) load(key string, fetch func(ctx context.Context) (interface{}, error)) func() (interface{}, error) {
+	loader.mutex.Lock()
+	current, found := loader.calls[key]
+	if !found {
+		current = &call{done: make(chan struct{})}
+		loader.calls[key] = current
+		go func() {
+			defer close(current.done)
+			current.value, current.err = fetch(loader.ctx)
+		}()
+	}
+	loader.mutex.Unlock()
+
+	return func() (interface{}, error) {
+		select {
+		case <-current.done:
+			return current.value, current.err
+		case <-loader.ctx.Done():
+			return nil, loader.ctx.Err()
+		}
+	}
+}
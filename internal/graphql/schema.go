@@ -0,0 +1,373 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/services"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+)
+
+// indicator is the source of the Indicator type, its fields are resolved when they are asked for
+type indicator struct {
+	Name string `json:"name"`
+}
+
+// Stats are derived from the daily observations of a range
+type Stats struct {
+	Count         int       `json:"count"`
+	DateFrom      time.Time `json:"dateFrom"`
+	DateTo        time.Time `json:"dateTo"`
+	First         float64   `json:"first"`
+	Last          float64   `json:"last"`
+	Min           float64   `json:"min"`
+	Max           float64   `json:"max"`
+	Mean          float64   `json:"mean"`
+	Change        float64   `json:"change"`
+	ChangePercent *float64  `json:"changePercent"`
+}
+
+var frequencyEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "Frequency",
+	Values: graphql.EnumValueConfigMap{
+		"DAILY":   {Value: models.FrequencyDaily, Description: "Every observation published"},
+		"WEEKLY":  {Value: models.FrequencyWeekly, Description: "The last value of every week, weeks end on sunday"},
+		"MONTHLY": {Value: models.FrequencyMonthly, Description: "The last value of every month"},
+	},
+})
+
+var currencyEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "Currency",
+	Values: graphql.EnumValueConfigMap{
+		models.CurrencyColones: {Value: models.CurrencyColones},
+		models.CurrencyDollars: {Value: models.CurrencyDollars},
+		models.CurrencyEuros:   {Value: models.CurrencyEuros},
+	},
+})
+
+var observationType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Observation",
+	Fields: graphql.Fields{
+		"series": {Type: graphql.NewNonNull(graphql.String)},
+		"date":   {Type: graphql.NewNonNull(graphql.DateTime)},
+		"value":  {Type: graphql.NewNonNull(graphql.Float)},
+	},
+})
+
+var statsType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Stats",
+	Fields: graphql.Fields{
+		"count":         {Type: graphql.NewNonNull(graphql.Int)},
+		"dateFrom":      {Type: graphql.NewNonNull(graphql.DateTime)},
+		"dateTo":        {Type: graphql.NewNonNull(graphql.DateTime)},
+		"first":         {Type: graphql.NewNonNull(graphql.Float)},
+		"last":          {Type: graphql.NewNonNull(graphql.Float)},
+		"min":           {Type: graphql.NewNonNull(graphql.Float)},
+		"max":           {Type: graphql.NewNonNull(graphql.Float)},
+		"mean":          {Type: graphql.NewNonNull(graphql.Float)},
+		"change":        {Type: graphql.NewNonNull(graphql.Float), Description: "last - first"},
+		"changePercent": {Type: graphql.Float, Description: "Change against the first value, null when it is 0"},
+	},
+})
+
+var conversionType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Conversion",
+	Fields: graphql.Fields{
+		"amount": {Type: graphql.NewNonNull(graphql.Float)},
+		"from":   {Type: graphql.NewNonNull(graphql.String)},
+		"to":     {Type: graphql.NewNonNull(graphql.String)},
+		"result": {Type: graphql.NewNonNull(graphql.Float)},
+		"rate":   {Type: graphql.NewNonNull(graphql.Float), Description: "Units of to a unit of from is worth"},
+		"date":   {Type: graphql.NewNonNull(graphql.DateTime)},
+	},
+})
+
+// rangeArguments are the same date params of the HTTP routes
+var rangeArguments = graphql.FieldConfigArgument{
+	"from": {Type: graphql.String, Description: "Format 2023-01-01. By default, 30 days before to"},
+	"to":   {Type: graphql.String, Description: "Format 2023-01-01. By default, today"},
+	"last": {Type: graphql.String, Description: "90d, 12w, 6m, 2y or ytd. Can not be combined with from and to"},
+}
+
+// NewSchema resolves the queries with the service, the indicators are the series that can be compared
+func NewSchema(service *services.ServiceAPI) (graphql.Schema, error) {
+	observationsArguments := graphql.FieldConfigArgument{
+		"frequency": {Type: frequencyEnum, DefaultValue: models.FrequencyDaily},
+	}
+	for name, argument := range rangeArguments {
+		observationsArguments[name] = argument
+	}
+
+	indicatorType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Indicator",
+		Fields: graphql.Fields{
+			"name": {Type: graphql.NewNonNull(graphql.String)},
+			"latest": {
+				Type:        observationType,
+				Description: "The last observation published, null when there is none recently",
+				Resolve:     resolveLatest(service),
+			},
+			"observations": {
+				Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(observationType))),
+				Args:    observationsArguments,
+				Resolve: resolveObservations(service),
+			},
+			"stats": {
+				Type:        statsType,
+				Description: "Stats of the daily observations of the range, null when there are none",
+				Args:        rangeArguments,
+				Resolve:     resolveStats(service),
+			},
+		},
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"indicators": {
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(indicatorType))),
+				Args: graphql.FieldConfigArgument{
+					"names": {Type: graphql.NewList(graphql.NewNonNull(graphql.String)), Description: "By default, every indicator"},
+				},
+				Resolve: resolveIndicators,
+			},
+			"indicator": {
+				Type: indicatorType,
+				Args: graphql.FieldConfigArgument{
+					"name": {Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					name, err := services.ParseSeries(p.Args["name"].(string))
+					if err != nil {
+						return nil, err
+					}
+					return indicator{Name: name}, nil
+				},
+			},
+			"convert": {
+				Type: conversionType,
+				Args: graphql.FieldConfigArgument{
+					"amount": {Type: graphql.NewNonNull(graphql.Float)},
+					"from":   {Type: graphql.NewNonNull(currencyEnum)},
+					"to":     {Type: graphql.NewNonNull(currencyEnum)},
+					"date":   {Type: graphql.String, Description: "By default, the latest exchange rates"},
+				},
+				Resolve: resolveConvert(service),
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
+
+func stringArgument(args map[string]interface{}, name string) string {
+	value, _ := args[name].(string)
+	return value
+}
+
+// dateRange reads the from, to and last arguments like the HTTP routes read their params
+func dateRange(from string, to string, last string) (time.Time, time.Time, error) {
+	if last != "" {
+		if from != "" || to != "" {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: last can not be combined with from or to", utils.ErrInvalidDateRange)
+		}
+		return utils.ParseRelativeRange(last)
+	}
+
+	var err error
+	dateTo := utils.Now()
+	if to != "" {
+		dateTo, err = utils.ParseDate(to)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("to: %w", err)
+		}
+	}
+	dateFrom := dateTo.AddDate(0, 0, -utils.DEFAULT_DAYS_TO_GO_BACK)
+	if from != "" {
+		dateFrom, err = utils.ParseDate(from)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("from: %w", err)
+		}
+	}
+
+	if err := utils.ValidateDateRange(dateFrom, dateTo); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return dateFrom, dateTo, nil
+}
+
+func argumentsRange(args map[string]interface{}) (time.Time, time.Time, error) {
+	return dateRange(stringArgument(args, "from"), stringArgument(args, "to"), stringArgument(args, "last"))
+}
+
+func resolveIndicators(p graphql.ResolveParams) (interface{}, error) {
+	names := services.ComparableSeries()
+	if values, ok := p.Args["names"].([]interface{}); ok {
+		names = make([]string, 0, len(values))
+		for _, value := range values {
+			name, err := services.ParseSeries(strings.ToLower(value.(string)))
+			if err != nil {
+				return nil, err
+			}
+			names = append(names, name)
+		}
+	}
+
+	indicators := make([]indicator, 0, len(names))
+	for _, name := range names {
+		indicators = append(indicators, indicator{Name: name})
+	}
+	return indicators, nil
+}
+
+func resolveLatest(service *services.ServiceAPI) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		name := p.Source.(indicator).Name
+		return loaderFrom(p.Context).load("latest:"+name, func(ctx context.Context) (interface{}, error) {
+			response := service.GetLatestObservation(ctx, services.GetLatestObservationRequest{Series: name})
+			if errors.Is(response.Err, utils.ErrNotFound) {
+				return nil, nil
+			}
+			if response.Err != nil {
+				return nil, response.Err
+			}
+			return response.Observation, nil
+		}), nil
+	}
+}
+
+// dailyObservations is shared by the observations and the stats of the same range, it is scraped once
+func dailyObservations(ctx context.Context, service *services.ServiceAPI, name string, dateFrom time.Time, dateTo time.Time) func() (interface{}, error) {
+	key := fmt.Sprintf("series:%s:%s:%s", name, dateFrom.Format(utils.DATE_FORMAT), dateTo.Format(utils.DATE_FORMAT))
+	return loaderFrom(ctx).load(key, func(ctx context.Context) (interface{}, error) {
+		response := service.GetSeries(ctx, services.GetSeriesRequest{Series: name, DateFrom: dateFrom, DateTo: dateTo})
+		if response.Err != nil {
+			return nil, response.Err
+		}
+		return response.Observations, nil
+	})
+}
+
+func resolveObservations(service *services.ServiceAPI) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		name := p.Source.(indicator).Name
+		dateFrom, dateTo, err := argumentsRange(p.Args)
+		if err != nil {
+			return nil, err
+		}
+
+		frequency := stringArgument(p.Args, "frequency")
+		if frequency == "" || frequency == models.FrequencyDaily {
+			return dailyObservations(p.Context, service, name, dateFrom, dateTo), nil
+		}
+
+		// the weekly and monthly values are the ones of the comparisons, sampled at the end of every period
+		key := fmt.Sprintf("compare:%s:%s:%s:%s", name, frequency, dateFrom.Format(utils.DATE_FORMAT), dateTo.Format(utils.DATE_FORMAT))
+		return loaderFrom(p.Context).load(key, func(ctx context.Context) (interface{}, error) {
+			response := service.CompareSeries(ctx, services.GetCompareSeriesRequest{
+				Series:    []string{name},
+				Frequency: frequency,
+				DateFrom:  dateFrom,
+				DateTo:    dateTo,
+			})
+			if response.Err != nil {
+				return nil, response.Err
+			}
+			observations := []models.Observation{}
+			for _, row := range response.Comparison.Rows {
+				if value := row.Values[name]; value != nil {
+					observations = append(observations, models.Observation{Series: name, Date: row.Date, Value: *value})
+				}
+			}
+			return observations, nil
+		}), nil
+	}
+}
+
+func resolveStats(service *services.ServiceAPI) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		name := p.Source.(indicator).Name
+		dateFrom, dateTo, err := argumentsRange(p.Args)
+		if err != nil {
+			return nil, err
+		}
+
+		observations := dailyObservations(p.Context, service, name, dateFrom, dateTo)
+		return func() (interface{}, error) {
+			result, err := observations()
+			if err != nil {
+				return nil, err
+			}
+			return statsOf(result.([]models.Observation)), nil
+		}, nil
+	}
+}
+
+// statsOf expects the observations oldest first, like the service returns them
+func statsOf(observations []models.Observation) *Stats {
+	if len(observations) == 0 {
+		return nil
+	}
+
+	first, last := observations[0], observations[len(observations)-1]
+	stats := &Stats{
+		Count:    len(observations),
+		DateFrom: first.Date,
+		DateTo:   last.Date,
+		First:    first.Value,
+		Last:     last.Value,
+		Min:      math.Inf(1),
+		Max:      math.Inf(-1),
+		Change:   last.Value - first.Value,
+	}
+	sum := 0.0
+	for _, observation := range observations {
+		stats.Min = math.Min(stats.Min, observation.Value)
+		stats.Max = math.Max(stats.Max, observation.Value)
+		sum += observation.Value
+	}
+	stats.Mean = sum / float64(len(observations))
+	if first.Value != 0 {
+		changePercent := stats.Change / first.Value * 100
+		stats.ChangePercent = &changePercent
+	}
+	return stats
+}
+
+func resolveConvert(service *services.ServiceAPI) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		amount := p.Args["amount"].(float64)
+		if amount <= 0 {
+			return nil, utils.ErrInvalidAmount
+		}
+
+		date := time.Time{}
+		if value := stringArgument(p.Args, "date"); value != "" {
+			var err error
+			date, err = utils.ParseDate(value)
+			if err != nil {
+				return nil, fmt.Errorf("date: %w", err)
+			}
+			if err := utils.ValidateDateRange(date, date); err != nil {
+				return nil, err
+			}
+		}
+
+		response := service.Convert(p.Context, services.GetConvertRequest{
+			Amount: amount,
+			From:   p.Args["from"].(string),
+			To:     p.Args["to"].(string),
+			Date:   date,
+		})
+		if response.Err != nil {
+			return nil, response.Err
+		}
+		return response.Conversion, nil
+	}
+}
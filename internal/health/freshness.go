@@ -0,0 +1,80 @@
+package health
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	FreshnessFresh   = "fresh"
+	FreshnessStale   = "stale"
+	FreshnessUnknown = "unknown"
+)
+
+// maximumAges is how old the latest observation of each indicator can be before it is stale. The daily
+// indicators are not published on weekends and holidays, the monthly ones are published weeks after the
+// month ends.
+var maximumAges = map[string]time.Duration{
+	"exchange_rate":            4 * 24 * time.Hour,
+	"monex_exchange_rate":      4 * 24 * time.Hour,
+	"euro_exchange_rate":       4 * 24 * time.Hour,
+	"monetary_policy_rate":     4 * 24 * time.Hour,
+	"prime_rate":               4 * 24 * time.Hour,
+	"treasury_rate_usa":        5 * 24 * time.Hour,
+	"treasury_yield_curve":     5 * 24 * time.Hour,
+	"basic_passive_rate":       10 * 24 * time.Hour,
+	"reference_deposit_rate":   10 * 24 * time.Hour,
+	"inflation_cr":             70 * 24 * time.Hour,
+	"consumer_price_index_usa": 70 * 24 * time.Hour,
+}
+
+type IndicatorFreshness struct {
+	Status            string     `json:"status"`
+	LatestObservation *time.Time `json:"latest_observation,omitempty"`
+	MaximumAge        string     `json:"maximum_age"`
+}
+
+// Freshness keeps the latest observation scraped of every indicator. The indicators are scraped on
+// demand, so the ones nobody asked for since the service started are unknown.
+type Freshness struct {
+	mutex  sync.RWMutex
+	latest map[string]time.Time
+}
+
+func NewFreshness() *Freshness {
+	return &Freshness{
+		latest: map[string]time.Time{},
+	}
+}
+
+// Observe records the date of the latest observation of a scrape, older ones are ignored
+func (freshness *Freshness) Observe(indicator string, date time.Time) {
+	freshness.mutex.Lock()
+	defer freshness.mutex.Unlock()
+	if date.After(freshness.latest[indicator]) {
+		freshness.latest[indicator] = date
+	}
+}
+
+// Report compares the latest observation of every indicator with its maximum age
+func (freshness *Freshness) Report(now time.Time) map[string]IndicatorFreshness {
+	freshness.mutex.RLock()
+	defer freshness.mutex.RUnlock()
+
+	report := map[string]IndicatorFreshness{}
+	for indicator, maximumAge := range maximumAges {
+		indicatorFreshness := IndicatorFreshness{
+			Status:     FreshnessUnknown,
+			MaximumAge: maximumAge.String(),
+		}
+		if latest, ok := freshness.latest[indicator]; ok {
+			indicatorFreshness.LatestObservation = &latest
+			indicatorFreshness.Status = FreshnessFresh
+			if now.Sub(latest) > maximumAge {
+				indicatorFreshness.Status = FreshnessStale
+			}
+		}
+		report[indicator] = indicatorFreshness
+	}
+	return report
+}
@@ -0,0 +1,131 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/configuration"
+)
+
+const (
+	StatusOK       = "ok"
+	StatusError    = "error"
+	StatusReady    = "ready"
+	StatusNotReady = "not_ready"
+)
+
+// Pinger is a dependency the service needs to answer requests, like the repository
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+type Check struct {
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+type Readiness struct {
+	Status    string                        `json:"status"`
+	Checks    map[string]Check              `json:"checks"`
+	Freshness map[string]IndicatorFreshness `json:"freshness"`
+}
+
+// cachedCheck runs a check at most once per ttl, the orchestrators ask for the readiness every few seconds
+type cachedCheck struct {
+	mutex sync.Mutex
+	ttl   time.Duration
+	check func(ctx context.Context) error
+	last  *Check
+}
+
+func (cached *cachedCheck) run(ctx context.Context) Check {
+	cached.mutex.Lock()
+	defer cached.mutex.Unlock()
+	if cached.last != nil && time.Since(cached.last.CheckedAt) < cached.ttl {
+		return *cached.last
+	}
+
+	result := Check{Status: StatusOK, CheckedAt: time.Now()}
+	if err := cached.check(ctx); err != nil {
+		result.Status = StatusError
+		result.Error = err.Error()
+	}
+	cached.last = &result
+	return result
+}
+
+// Checker answers whether the service is ready to receive requests
+type Checker struct {
+	timeout   time.Duration
+	checks    map[string]*cachedCheck
+	freshness *Freshness
+}
+
+func NewChecker(config configuration.HealthConfig, repository Pinger, freshness *Freshness) *Checker {
+	checker := &Checker{
+		timeout:   config.Timeout,
+		checks:    map[string]*cachedCheck{},
+		freshness: freshness,
+	}
+	checker.checks["repository"] = &cachedCheck{ttl: config.CheckTTL, check: repository.Ping}
+	if config.ProbeBCCR {
+		client := &http.Client{Timeout: config.Timeout}
+		checker.checks["bccr"] = &cachedCheck{ttl: config.CheckTTL, check: func(ctx context.Context) error {
+			return probe(ctx, client, config.BCCRUrl)
+		}}
+	}
+	return checker
+}
+
+// probe only checks the website answers, the scrapes tell whether the tables changed
+func probe(ctx context.Context, client *http.Client, url string) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return err
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("unexpected status: %s", response.Status)
+	}
+	return nil
+}
+
+// Readiness runs the checks concurrently. The freshness is reported but it does not make the service
+// unready, the data is scraped when it is requested.
+func (checker *Checker) Readiness(ctx context.Context) Readiness {
+	ctx, cancel := context.WithTimeout(ctx, checker.timeout)
+	defer cancel()
+
+	readiness := Readiness{
+		Status:    StatusReady,
+		Checks:    map[string]Check{},
+		Freshness: checker.freshness.Report(time.Now()),
+	}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checker.checks {
+		wg.Add(1)
+		go func(name string, check *cachedCheck) {
+			defer wg.Done()
+			result := check.run(ctx)
+			mutex.Lock()
+			readiness.Checks[name] = result
+			if result.Status != StatusOK {
+				readiness.Status = StatusNotReady
+			}
+			mutex.Unlock()
+		}(name, check)
+	}
+	wg.Wait()
+
+	return readiness
+}
@@ -0,0 +1,147 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	kitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "libertadfinanciera"
+
+// Metrics has every metric of the service, the zero value is not usable, use New or NewDiscard
+type Metrics struct {
+	RequestCount   kitmetrics.Counter
+	RequestLatency kitmetrics.Histogram
+	ScrapeDuration kitmetrics.Histogram
+	ScrapeFailures kitmetrics.Counter
+	LastSuccess    kitmetrics.Gauge
+	CacheHits      kitmetrics.Counter
+	CacheMisses    kitmetrics.Counter
+	FanOut         kitmetrics.Histogram
+	Subscribers    kitmetrics.Gauge
+	Events         kitmetrics.Counter
+	Deliveries     kitmetrics.Counter
+	Notifications  kitmetrics.Counter
+}
+
+// New registers the metrics in the default prometheus registry
+func New() *Metrics {
+	return &Metrics{
+		RequestCount: kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Number of requests received, by route and whether they failed.",
+		}, []string{"route", "error"}),
+		RequestLatency: kitprometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Time spent answering the requests, by route.",
+			Buckets:   []float64{0.005, 0.025, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		}, []string{"route"}),
+		ScrapeDuration: kitprometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "scrapper",
+			Name:      "scrape_duration_seconds",
+			Help:      "Time spent scraping a table, by indicator.",
+			Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+		}, []string{"indicator"}),
+		ScrapeFailures: kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "scrapper",
+			Name:      "scrape_failures_total",
+			Help:      "Number of scrapes that failed or returned nothing, by indicator.",
+		}, []string{"indicator"}),
+		LastSuccess: kitprometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "scrapper",
+			Name:      "last_success_timestamp_seconds",
+			Help:      "Unix time of the last scrape that returned data, by indicator.",
+		}, []string{"indicator"}),
+		CacheHits: kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "cache",
+			Name:      "hits_total",
+			Help:      "Number of responses served from the cache, by route.",
+		}, []string{"route"}),
+		CacheMisses: kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "cache",
+			Name:      "misses_total",
+			Help:      "Number of responses not found in the cache, by route.",
+		}, []string{"route"}),
+		FanOut: kitprometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "service",
+			Name:      "fan_out_goroutines",
+			Help:      "Number of concurrent scrapes started by a request, by operation.",
+			Buckets:   []float64{1, 2, 4, 8, 16, 32, 64, 128, 256, 512},
+		}, []string{"operation"}),
+		Subscribers: kitprometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "stream",
+			Name:      "subscribers",
+			Help:      "Number of clients subscribed to the events, by transport.",
+		}, []string{"transport"}),
+		Events: kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "stream",
+			Name:      "events_total",
+			Help:      "Number of new and revised observations detected, by series and type.",
+		}, []string{"series", "type"}),
+		Deliveries: kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "webhooks",
+			Name:      "deliveries_total",
+			Help:      "Number of webhook delivery attempts, by result: delivered, retried or dead_letter.",
+		}, []string{"result"}),
+		Notifications: kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "alerts",
+			Name:      "notifications_total",
+			Help:      "Number of alerts notified, by rule, notifier and result: sent or failed.",
+		}, []string{"rule", "notifier", "result"}),
+	}
+}
+
+// NewDiscard returns metrics that are not recorded
+func NewDiscard() *Metrics {
+	return &Metrics{
+		RequestCount:   discard.NewCounter(),
+		RequestLatency: discard.NewHistogram(),
+		ScrapeDuration: discard.NewHistogram(),
+		ScrapeFailures: discard.NewCounter(),
+		LastSuccess:    discard.NewGauge(),
+		CacheHits:      discard.NewCounter(),
+		CacheMisses:    discard.NewCounter(),
+		FanOut:         discard.NewHistogram(),
+		Subscribers:    discard.NewGauge(),
+		Events:         discard.NewCounter(),
+		Deliveries:     discard.NewCounter(),
+		Notifications:  discard.NewCounter(),
+	}
+}
+
+// EndpointMiddleware counts the requests of a route and measures how long they take. failed tells the
+// responses with errors apart, the services return them inside the response instead of as an error.
+func EndpointMiddleware(metrics *Metrics, route string, failed func(interface{}) bool) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+			defer func(begin time.Time) {
+				isError := "false"
+				if err != nil || failed(response) {
+					isError = "true"
+				}
+				metrics.RequestCount.With("route", route, "error", isError).Add(1)
+				metrics.RequestLatency.With("route", route).Observe(time.Since(begin).Seconds())
+			}(time.Now())
+			return next(ctx, request)
+		}
+	}
+}
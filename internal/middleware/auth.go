@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+)
+
+const HeaderAPIKey = "X-API-Key"
+
+// publicPaths never need a key, the probes, the metrics and the documentation
+var publicPaths = map[string]bool{
+	"/healthz":                  true,
+	"/readyz":                   true,
+	"/metrics":                  true,
+	"/openapi.json":             true,
+	"/docs":                     true,
+	"/docs/redoc.standalone.js": true,
+}
+
+// KeyLookup returns the client of an API key
+type KeyLookup interface {
+	Lookup(ctx context.Context, key string) (*models.APIKey, error)
+}
+
+// apiKey is sent in the X-API-Key header or as a bearer token
+func apiKey(r *http.Request) string {
+	if key := r.Header.Get(HeaderAPIKey); key != "" {
+		return key
+	}
+	if authorization := r.Header.Get("Authorization"); strings.HasPrefix(authorization, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(authorization, "Bearer "))
+	}
+	return ""
+}
+
+// ForwardedClientIP returns the address of the client in the X-Forwarded-For values. Every proxy appends
+// the address it received the request from, so only the last trustedProxies entries were not sent by the
+// client. It returns an empty address without trusted proxies or entries.
+func ForwardedClientIP(values []string, trustedProxies int) string {
+	if trustedProxies <= 0 {
+		return ""
+	}
+	entries := []string{}
+	for _, value := range values {
+		for _, entry := range strings.Split(value, ",") {
+			if entry = strings.TrimSpace(entry); entry != "" {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	if len(entries) == 0 {
+		return ""
+	}
+	// with fewer entries than proxies, every entry was appended by a trusted one
+	if len(entries) < trustedProxies {
+		return entries[0]
+	}
+	return entries[len(entries)-trustedProxies]
+}
+
+// clientIP is the address of the connection, or the address in X-Forwarded-For when the service runs
+// behind trusted proxies
+func clientIP(r *http.Request, trustedProxies int) string {
+	if forwarded := ForwardedClientIP(r.Header.Values("X-Forwarded-For"), trustedProxies); forwarded != "" {
+		return forwarded
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func writeUnauthorized(rw http.ResponseWriter, err error) {
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	rw.Header().Set("WWW-Authenticate", "Bearer")
+	status := http.StatusUnauthorized
+	if !errors.Is(err, utils.ErrUnauthorized) {
+		status = http.StatusInternalServerError
+	}
+	rw.WriteHeader(status)
+	json.NewEncoder(rw).Encode(map[string]interface{}{
+		"error": err.Error(),
+	})
+}
+
+// Authenticate puts the client of every request in the context for the rate limits. A wrong key is
+// always rejected, a missing one only when the keys are required. The public paths and the preflight
+// requests never need a key.
+func Authenticate(keys KeyLookup, required bool, trustedProxies int) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			client := utils.Client{IP: clientIP(r, trustedProxies)}
+			if !publicPaths[r.URL.Path] && r.Method != http.MethodOptions {
+				var err error
+				client, err = Identify(r.Context(), keys, required, apiKey(r), client.IP)
+				if err != nil {
+					writeUnauthorized(rw, err)
+					return
+				}
+			}
+			h.ServeHTTP(rw, r.WithContext(utils.WithClient(r.Context(), client)))
+		})
+	}
+}
+
+// Identify returns the client of an API key, the HTTP and gRPC transports share it. A wrong key is
+// always rejected, a missing one only when the keys are required.
+func Identify(ctx context.Context, keys KeyLookup, required bool, key string, ip string) (utils.Client, error) {
+	client := utils.Client{IP: ip}
+	switch {
+	case key != "":
+		found, err := keys.Lookup(ctx, key)
+		if err != nil {
+			return client, err
+		}
+		client.ID = found.Client
+		client.RequestsPerMinute = found.RequestsPerMinute
+	case required:
+		return client, utils.ErrUnauthorized
+	}
+	return client, nil
+}
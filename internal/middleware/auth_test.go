@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name           string
+		forwarded      []string
+		trustedProxies int
+		expected       string
+	}{
+		{"without proxies the header is ignored", []string{"1.1.1.1"}, 0, "10.0.0.2"},
+		{"without the header", nil, 1, "10.0.0.2"},
+		{"a proxy", []string{"203.0.113.7"}, 1, "203.0.113.7"},
+		// the client sent the first entry, the proxy appended the address it received the request from
+		{"a spoofed entry", []string{"1.1.1.1, 203.0.113.7"}, 1, "203.0.113.7"},
+		{"a spoofed header line", []string{"1.1.1.1", "203.0.113.7"}, 1, "203.0.113.7"},
+		{"two proxies", []string{"1.1.1.1, 203.0.113.7, 10.0.0.9"}, 2, "203.0.113.7"},
+		{"fewer entries than proxies", []string{"203.0.113.7"}, 2, "203.0.113.7"},
+		{"empty entries", []string{" , "}, 1, "10.0.0.2"},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("GET", "/exchange_rates", nil)
+		r.RemoteAddr = "10.0.0.2:51234"
+		for _, value := range test.forwarded {
+			r.Header.Add("X-Forwarded-For", value)
+		}
+		if ip := clientIP(r, test.trustedProxies); ip != test.expected {
+			t.Errorf("%s: the ip is %q, expected %q", test.name, ip, test.expected)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/configuration"
+)
+
+// Header Constants
+const (
+	HeaderOrigin           = "Origin"
+	HeaderVary             = "Vary"
+	HeaderAllowOrigin      = "Access-Control-Allow-Origin"
+	HeaderAllowMethods     = "Access-Control-Allow-Methods"
+	HeaderAllowHeaders     = "Access-Control-Allow-Headers"
+	HeaderAllowCredentials = "Access-Control-Allow-Credentials"
+	HeaderExposeHeaders    = "Access-Control-Expose-Headers"
+	HeaderMaxAge           = "Access-Control-Max-Age"
+	HeaderRequestMethod    = "Access-Control-Request-Method"
+	HeaderRequestHeaders   = "Access-Control-Request-Headers"
+	anyOrigin              = "*"
+	wildcardSubdomain      = "*."
+)
+
+// allowedOrigin is an entry of ALLOWED_ORIGINS. Without scheme it matches both http and https, the host
+// *.example.com matches the subdomains of example.com but not example.com itself.
+type allowedOrigin struct {
+	scheme string
+	host   string
+	suffix string
+}
+
+func parseAllowedOrigin(entry string) (allowedOrigin, bool) {
+	entry = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(entry), "/"))
+	origin := allowedOrigin{host: entry}
+	if scheme, host, found := strings.Cut(entry, "://"); found {
+		origin.scheme = scheme
+		origin.host = host
+	}
+	if strings.HasPrefix(origin.host, wildcardSubdomain) {
+		origin.suffix = strings.TrimPrefix(origin.host, "*")
+		origin.host = ""
+	}
+	name := origin.host + strings.TrimPrefix(origin.suffix, ".")
+	return origin, name != "" && !strings.ContainsAny(name, "*/")
+}
+
+func (allowed allowedOrigin) matches(scheme string, host string) bool {
+	if allowed.scheme != "" && allowed.scheme != scheme {
+		return false
+	}
+	if allowed.suffix != "" {
+		return strings.HasSuffix(host, allowed.suffix)
+	}
+	return allowed.host == host
+}
+
+type cors struct {
+	anyOrigin        bool
+	origins          []allowedOrigin
+	methods          string
+	methodSet        map[string]bool
+	headers          string
+	headerSet        map[string]bool
+	exposedHeaders   string
+	maxAge           string
+	allowCredentials bool
+}
+
+// isAllowed compares the scheme and the host of the origin, a part of them is never enough
+func (c *cors) isAllowed(origin string) bool {
+	if c.anyOrigin {
+		return true
+	}
+	parsed, err := url.Parse(strings.ToLower(origin))
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return false
+	}
+	for _, allowed := range c.origins {
+		if allowed.matches(parsed.Scheme, parsed.Host) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsHeaders checks every header of a preflight, they are a comma separated list
+func (c *cors) allowsHeaders(requested string) bool {
+	for _, header := range strings.Split(requested, ",") {
+		header = strings.TrimSpace(header)
+		if header != "" && !c.headerSet[http.CanonicalHeaderKey(header)] {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *cors) setOrigin(rw http.ResponseWriter, origin string) {
+	if c.anyOrigin && !c.allowCredentials {
+		rw.Header().Set(HeaderAllowOrigin, anyOrigin)
+	} else {
+		rw.Header().Set(HeaderAllowOrigin, origin)
+	}
+	if c.allowCredentials {
+		rw.Header().Set(HeaderAllowCredentials, "true")
+	}
+}
+
+func (c *cors) preflight(rw http.ResponseWriter, r *http.Request, origin string) {
+	rw.Header().Add(HeaderVary, HeaderRequestMethod)
+	rw.Header().Add(HeaderVary, HeaderRequestHeaders)
+	if !c.methodSet[strings.ToUpper(r.Header.Get(HeaderRequestMethod))] || !c.allowsHeaders(r.Header.Get(HeaderRequestHeaders)) {
+		rw.WriteHeader(http.StatusForbidden)
+		return
+	}
+	c.setOrigin(rw, origin)
+	rw.Header().Set(HeaderAllowMethods, c.methods)
+	if c.headers != "" {
+		rw.Header().Set(HeaderAllowHeaders, c.headers)
+	}
+	if c.maxAge != "" {
+		rw.Header().Set(HeaderMaxAge, c.maxAge)
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// newCORS validates the origins, an invalid entry stops the start
+func newCORS(config configuration.CORSConfig) (*cors, error) {
+	c := &cors{
+		methods:          strings.ToUpper(strings.Join(config.AllowedMethods, ", ")),
+		methodSet:        map[string]bool{},
+		headers:          strings.Join(config.AllowedHeaders, ", "),
+		headerSet:        map[string]bool{},
+		exposedHeaders:   strings.Join(config.ExposedHeaders, ", "),
+		allowCredentials: config.AllowCredentials,
+	}
+	for _, method := range config.AllowedMethods {
+		c.methodSet[strings.ToUpper(strings.TrimSpace(method))] = true
+	}
+	for _, header := range config.AllowedHeaders {
+		c.headerSet[http.CanonicalHeaderKey(strings.TrimSpace(header))] = true
+	}
+	if seconds := int(config.MaxAge.Seconds()); seconds > 0 {
+		c.maxAge = strconv.Itoa(seconds)
+	}
+	for i, entry := range config.AllowedOrigins {
+		if strings.TrimSpace(entry) == anyOrigin {
+			c.anyOrigin = true
+			continue
+		}
+		origin, valid := parseAllowedOrigin(entry)
+		if !valid {
+			return nil, fmt.Errorf("ALLOWED_ORIGINS entry %d is not an origin: %q", i+1, entry)
+		}
+		c.origins = append(c.origins, origin)
+	}
+	if c.anyOrigin && c.allowCredentials {
+		return nil, errors.New("CORS credentials can not be allowed for any origin, list the origins in ALLOWED_ORIGINS")
+	}
+	return c, nil
+}
+
+// CORS answers the preflights of the allowed origins and adds the CORS headers to their requests. It
+// wraps the whole handler, the router has no OPTIONS routes and would answer the preflights 405. The
+// requests of other origins are served without CORS headers, the browsers block them.
+func CORS(config configuration.CORSConfig) (mux.MiddlewareFunc, error) {
+	c, err := newCORS(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.Header().Add(HeaderVary, HeaderOrigin)
+			origin := r.Header.Get(HeaderOrigin)
+			if origin == "" || !c.isAllowed(origin) {
+				if origin != "" && r.Method == http.MethodOptions && r.Header.Get(HeaderRequestMethod) != "" {
+					rw.WriteHeader(http.StatusForbidden)
+					return
+				}
+				h.ServeHTTP(rw, r)
+				return
+			}
+
+			if r.Method == http.MethodOptions && r.Header.Get(HeaderRequestMethod) != "" {
+				c.preflight(rw, r, origin)
+				return
+			}
+			c.setOrigin(rw, origin)
+			if c.exposedHeaders != "" {
+				rw.Header().Set(HeaderExposeHeaders, c.exposedHeaders)
+			}
+			h.ServeHTTP(rw, r)
+		})
+	}, nil
+}
+
+// CheckOrigin tells if a WebSocket can be opened from the origin of the request. The browsers do not
+// apply CORS to the WebSockets, so the handshake checks ALLOWED_ORIGINS itself. The clients that are not
+// browsers do not send an origin, and a page can always connect to its own host.
+func CheckOrigin(config configuration.CORSConfig) (func(r *http.Request) bool, error) {
+	c, err := newCORS(config)
+	if err != nil {
+		return nil, err
+	}
+	return func(r *http.Request) bool {
+		origin := r.Header.Get(HeaderOrigin)
+		if origin == "" {
+			return true
+		}
+		if parsed, err := url.Parse(origin); err == nil && strings.EqualFold(parsed.Host, r.Host) {
+			return true
+		}
+		return c.isAllowed(origin)
+	}, nil
+}
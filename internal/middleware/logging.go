@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/gorilla/mux"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+)
+
+const HeaderRequestID = "X-Request-ID"
+
+// requestIDRegexp only accepts the ids of the clients that are safe to log
+var requestIDRegexp = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+func newRequestID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(id)
+}
+
+// RequestID keeps the X-Request-ID of the request or assigns a new one. It is returned in the response
+// and carried in the context, so the logs of the scrapes can be tied to the request.
+func RequestID() mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			requestID := r.Header.Get(HeaderRequestID)
+			if !requestIDRegexp.MatchString(requestID) {
+				requestID = newRequestID()
+			}
+			rw.Header().Set(HeaderRequestID, requestID)
+			h.ServeHTTP(rw, r.WithContext(utils.WithRequestID(r.Context(), requestID)))
+		})
+	}
+}
+
+// statusRecorder keeps the status and the size of a response to log them
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (recorder *statusRecorder) WriteHeader(status int) {
+	if recorder.status == 0 {
+		recorder.status = status
+	}
+	recorder.ResponseWriter.WriteHeader(status)
+}
+
+func (recorder *statusRecorder) Write(body []byte) (int, error) {
+	if recorder.status == 0 {
+		recorder.status = http.StatusOK
+	}
+	written, err := recorder.ResponseWriter.Write(body)
+	recorder.bytes += written
+	return written, err
+}
+
+// Flush lets the streams send every event as soon as it is written
+func (recorder *statusRecorder) Flush() {
+	if flusher, ok := recorder.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack hands the connection to the WebSockets, the upgrade is logged as 101
+func (recorder *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := recorder.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("the connection can not be hijacked")
+	}
+	if recorder.status == 0 {
+		recorder.status = http.StatusSwitchingProtocols
+	}
+	return hijacker.Hijack()
+}
+
+// quietPaths are polled by the orchestrators and Prometheus, they are only logged in debug
+var quietPaths = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+// AccessLog logs every request with its status and duration
+func AccessLog(logger log.Logger) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			begin := time.Now()
+			recorder := &statusRecorder{ResponseWriter: rw}
+			h.ServeHTTP(recorder, r)
+			if recorder.status == 0 {
+				recorder.status = http.StatusOK
+			}
+
+			logLevel := level.Info
+			switch {
+			case recorder.status >= http.StatusInternalServerError:
+				logLevel = level.Error
+			case quietPaths[r.URL.Path]:
+				logLevel = level.Debug
+			}
+			_ = logLevel(logger).Log(
+				"msg", "request",
+				"request_id", utils.RequestID(r.Context()),
+				"method", r.Method,
+				"path", r.URL.Path,
+				"query", r.URL.RawQuery,
+				"status", recorder.status,
+				"bytes", recorder.bytes,
+				"duration", time.Since(begin),
+			)
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package models
+
+// APIKey is a key given to a client of the API. Only the SHA-256 of the key is stored, a zero
+// RequestsPerMinute uses the default limit of the keys.
+type APIKey struct {
+	Client            string  `json:"client"`
+	KeyHash           string  `json:"key_hash"`
+	RequestsPerMinute float64 `json:"requests_per_minute"`
+	Active            bool    `json:"active"`
+}
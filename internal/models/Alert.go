@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Alert is a rule that fired. The reference is the value the observation was compared with.
+type Alert struct {
+	Rule          string     `json:"rule"`
+	Series        string     `json:"series"`
+	Date          time.Time  `json:"date"`
+	Value         float64    `json:"value"`
+	Reference     *float64   `json:"reference,omitempty"`
+	ReferenceDate *time.Time `json:"reference_date,omitempty"`
+	Message       string     `json:"message"`
+	TriggeredAt   time.Time  `json:"triggered_at"`
+}
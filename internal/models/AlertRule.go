@@ -0,0 +1,22 @@
+package models
+
+const (
+	RuleChange      = "change"
+	RuleAbove       = "above"
+	RuleBelow       = "below"
+	RuleOutsideBand = "outside_band"
+)
+
+// AlertRule is evaluated with every new observation of its series. A change compares the value with the
+// one a window before, like 1d or 1w, or with the previous observation without window. The thresholds and
+// the bands fire when the value crosses them, not while it stays on the other side.
+type AlertRule struct {
+	Name      string  `json:"name"`
+	Series    string  `json:"series"`
+	Type      string  `json:"type"`
+	Window    string  `json:"window,omitempty"`
+	Percent   float64 `json:"percent,omitempty"`
+	Threshold float64 `json:"threshold,omitempty"`
+	Min       float64 `json:"min,omitempty"`
+	Max       float64 `json:"max,omitempty"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+const (
+	ReferenceRateBasicPassive = "tbp"
+	ReferenceRatePrime        = "prime"
+)
+
+const (
+	CalculatorLoan    = "loan"
+	CalculatorSavings = "savings"
+)
+
+type AmortizationPayment struct {
+	Number    int       `json:"number"`
+	Date      time.Time `json:"date"`
+	Rate      float64   `json:"rate"`
+	Payment   float64   `json:"payment"`
+	Interest  float64   `json:"interest"`
+	Principal float64   `json:"principal"`
+	Balance   float64   `json:"balance"`
+}
+
+type AmortizationSchedule struct {
+	Type          string                `json:"type"`
+	ReferenceRate string                `json:"reference_rate"`
+	Principal     float64               `json:"principal"`
+	Margin        float64               `json:"margin"`
+	Term          int                   `json:"term"`
+	Frequency     string                `json:"frequency"`
+	BackTest      bool                  `json:"back_test"`
+	TotalPaid     float64               `json:"total_paid"`
+	TotalInterest float64               `json:"total_interest"`
+	Payments      []AmortizationPayment `json:"payments"`
+}
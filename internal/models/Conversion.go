@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Conversion is an amount converted between currencies with the reference exchange rates of a date.
+// Rate is how many units of To a unit of From is worth.
+type Conversion struct {
+	Amount float64   `json:"amount"`
+	From   string    `json:"from"`
+	To     string    `json:"to"`
+	Result float64   `json:"result"`
+	Rate   float64   `json:"rate"`
+	Date   time.Time `json:"date"`
+}
@@ -0,0 +1,7 @@
+package models
+
+const (
+	CurrencyColones = "CRC"
+	CurrencyDollars = "USD"
+	CurrencyEuros   = "EUR"
+)
@@ -0,0 +1,7 @@
+package models
+
+// DatedValueHTML is a single value of the BCCR tables that publish one indicator per column
+type DatedValueHTML struct {
+	Date  string
+	Value string
+}
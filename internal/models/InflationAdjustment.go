@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+type InflationAdjustment struct {
+	Currency            string    `json:"currency"`
+	Amount              float64   `json:"amount"`
+	AdjustedAmount      float64   `json:"adjusted_amount"`
+	CumulativeInflation float64   `json:"cumulative_inflation"`
+	Months              int       `json:"months"`
+	DateFrom            time.Time `json:"date_from"`
+	DateTo              time.Time `json:"date_to"`
+}
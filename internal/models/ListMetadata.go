@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+const (
+	OrderAscending  = "asc"
+	OrderDescending = "desc"
+)
+
+// ListMetadata describes the whole result of a list endpoint, the dates are the range actually covered
+// by the data published, which can be shorter than the range requested
+type ListMetadata struct {
+	Total      int        `json:"total"`
+	Count      int        `json:"count"`
+	Limit      int        `json:"limit,omitempty"`
+	Offset     int        `json:"offset"`
+	Order      string     `json:"order"`
+	DateFrom   *time.Time `json:"date_from,omitempty"`
+	DateTo     *time.Time `json:"date_to,omitempty"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
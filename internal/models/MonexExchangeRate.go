@@ -0,0 +1,8 @@
+package models
+
+import "time"
+
+type MonexExchangeRate struct {
+	Value float64   `json:"value"`
+	Date  time.Time `json:"date"`
+}
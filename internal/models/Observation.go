@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// Observation is a value of a series published on a date, every indicator can be read as a series
+type Observation struct {
+	Series string    `json:"series"`
+	Date   time.Time `json:"date"`
+	Value  float64   `json:"value"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+const (
+	EventObservation = "observation"
+	EventRevision    = "revision"
+)
+
+// ObservationEvent is a value published or revised since the previous poll. Previous is the value before
+// it: the one revised, or the one of the last observation before a new one, when it is known. The ids keep
+// growing across restarts, they are used to resume a stream.
+type ObservationEvent struct {
+	ID         uint64    `json:"id"`
+	Type       string    `json:"type"`
+	Series     string    `json:"series"`
+	Date       time.Time `json:"date"`
+	Value      float64   `json:"value"`
+	Previous   *float64  `json:"previous,omitempty"`
+	DetectedAt time.Time `json:"detected_at"`
+}
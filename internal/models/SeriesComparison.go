@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+const (
+	FrequencyDaily   = "daily"
+	FrequencyWeekly  = "weekly"
+	FrequencyMonthly = "monthly"
+)
+
+// SeriesComparisonRow has the value of every series at the end of a period. A value is null when the
+// series had not published anything yet
+type SeriesComparisonRow struct {
+	Date   time.Time           `json:"date"`
+	Values map[string]*float64 `json:"values"`
+}
+
+type SeriesComparison struct {
+	Series    []string              `json:"series"`
+	Frequency string                `json:"frequency"`
+	DateFrom  time.Time             `json:"date_from"`
+	DateTo    time.Time             `json:"date_to"`
+	Rows      []SeriesComparisonRow `json:"rows"`
+}
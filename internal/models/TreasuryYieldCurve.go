@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+type TreasuryYieldHTML struct {
+	Maturity string
+	Value    string
+}
+
+type TreasuryYield struct {
+	Maturity string  `json:"maturity"`
+	Value    float64 `json:"value"`
+}
+
+type TreasuryYieldCurve struct {
+	Date     time.Time       `json:"date"`
+	Yields   []TreasuryYield `json:"yields"`
+	Inverted bool            `json:"inverted"`
+}
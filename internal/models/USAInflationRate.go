@@ -2,11 +2,16 @@ package models
 
 import "time"
 
-type USAInflationRateHTML struct {
+type USAConsumerPriceIndexHTML struct {
 	Date  string
 	Value string
 }
 
+type USAConsumerPriceIndex struct {
+	Value float64   `json:"value"`
+	Date  time.Time `json:"date"`
+}
+
 type USAInflationRate struct {
 	Value float64   `json:"value"`
 	Date  time.Time `json:"date"`
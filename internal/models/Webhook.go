@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+const (
+	ConditionCrossesAbove     = "crosses_above"
+	ConditionCrossesBelow     = "crosses_below"
+	ConditionChanges          = "changes"
+	ConditionChangesByPercent = "changes_by_percent"
+)
+
+// WebhookCondition is evaluated with every new value of the series against the one before it. The
+// threshold is a value for the crossings and a percentage for changes_by_percent.
+type WebhookCondition struct {
+	Type      string  `json:"type"`
+	Threshold float64 `json:"threshold,omitempty"`
+}
+
+// Webhook is a subscription of a client to a condition of a series. The secret signs the payloads, it is
+// only returned when the webhook is created. An ephemeral webhook is lost when the service restarts, the
+// client has to create it again.
+type Webhook struct {
+	ID        string           `json:"id"`
+	Owner     string           `json:"-"`
+	Series    string           `json:"series"`
+	Condition WebhookCondition `json:"condition"`
+	URL       string           `json:"url"`
+	Secret    string           `json:"-"`
+	Ephemeral bool             `json:"ephemeral"`
+	CreatedAt time.Time        `json:"created_at"`
+}
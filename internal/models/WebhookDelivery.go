@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+const (
+	WebhookAlert = "alert"
+	WebhookPing  = "ping"
+)
+
+// WebhookPayload is the body sent to the url of a webhook. Every retry of a delivery has the same id, the
+// receivers can use it to ignore the duplicates.
+type WebhookPayload struct {
+	ID        string            `json:"id"`
+	Type      string            `json:"type"`
+	WebhookID string            `json:"webhook_id"`
+	Series    string            `json:"series"`
+	Condition WebhookCondition  `json:"condition"`
+	Event     *ObservationEvent `json:"event,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// DeadLetter is a delivery that failed every attempt
+type DeadLetter struct {
+	Payload    WebhookPayload `json:"payload"`
+	Owner      string         `json:"-"`
+	URL        string         `json:"url"`
+	Attempts   int            `json:"attempts"`
+	LastStatus int            `json:"last_status,omitempty"`
+	LastError  string         `json:"last_error"`
+	FailedAt   time.Time      `json:"failed_at"`
+}
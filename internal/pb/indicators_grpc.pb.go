@@ -0,0 +1,249 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: indicators.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// IndicatorsClient is the client API for Indicators service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type IndicatorsClient interface {
+	// GetSeries returns the observations of a series in a range of dates, the last 30 days by default
+	GetSeries(ctx context.Context, in *GetSeriesRequest, opts ...grpc.CallOption) (*GetSeriesResponse, error)
+	// StreamSeries sends the observations of long ranges a year at a time, oldest first, as they are scraped
+	StreamSeries(ctx context.Context, in *GetSeriesRequest, opts ...grpc.CallOption) (Indicators_StreamSeriesClient, error)
+	// GetLatest returns the last observation published of a series
+	GetLatest(ctx context.Context, in *GetLatestRequest, opts ...grpc.CallOption) (*Observation, error)
+	// Convert converts an amount between CRC, USD and EUR with the exchange rates of a date
+	Convert(ctx context.Context, in *ConvertRequest, opts ...grpc.CallOption) (*ConvertResponse, error)
+}
+
+type indicatorsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewIndicatorsClient(cc grpc.ClientConnInterface) IndicatorsClient {
+	return &indicatorsClient{cc}
+}
+
+func (c *indicatorsClient) GetSeries(ctx context.Context, in *GetSeriesRequest, opts ...grpc.CallOption) (*GetSeriesResponse, error) {
+	out := new(GetSeriesResponse)
+	err := c.cc.Invoke(ctx, "/libertadfinanciera.v1.Indicators/GetSeries", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *indicatorsClient) StreamSeries(ctx context.Context, in *GetSeriesRequest, opts ...grpc.CallOption) (Indicators_StreamSeriesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Indicators_ServiceDesc.Streams[0], "/libertadfinanciera.v1.Indicators/StreamSeries", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &indicatorsStreamSeriesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Indicators_StreamSeriesClient interface {
+	Recv() (*Observation, error)
+	grpc.ClientStream
+}
+
+type indicatorsStreamSeriesClient struct {
+	grpc.ClientStream
+}
+
+func (x *indicatorsStreamSeriesClient) Recv() (*Observation, error) {
+	m := new(Observation)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *indicatorsClient) GetLatest(ctx context.Context, in *GetLatestRequest, opts ...grpc.CallOption) (*Observation, error) {
+	out := new(Observation)
+	err := c.cc.Invoke(ctx, "/libertadfinanciera.v1.Indicators/GetLatest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *indicatorsClient) Convert(ctx context.Context, in *ConvertRequest, opts ...grpc.CallOption) (*ConvertResponse, error) {
+	out := new(ConvertResponse)
+	err := c.cc.Invoke(ctx, "/libertadfinanciera.v1.Indicators/Convert", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// IndicatorsServer is the server API for Indicators service.
+// All implementations must embed UnimplementedIndicatorsServer
+// for forward compatibility
+type IndicatorsServer interface {
+	// GetSeries returns the observations of a series in a range of dates, the last 30 days by default
+	GetSeries(context.Context, *GetSeriesRequest) (*GetSeriesResponse, error)
+	// StreamSeries sends the observations of long ranges a year at a time, oldest first, as they are scraped
+	StreamSeries(*GetSeriesRequest, Indicators_StreamSeriesServer) error
+	// GetLatest returns the last observation published of a series
+	GetLatest(context.Context, *GetLatestRequest) (*Observation, error)
+	// Convert converts an amount between CRC, USD and EUR with the exchange rates of a date
+	Convert(context.Context, *ConvertRequest) (*ConvertResponse, error)
+	mustEmbedUnimplementedIndicatorsServer()
+}
+
+// UnimplementedIndicatorsServer must be embedded to have forward compatible implementations.
+type UnimplementedIndicatorsServer struct {
+}
+
+func (UnimplementedIndicatorsServer) GetSeries(context.Context, *GetSeriesRequest) (*GetSeriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSeries not implemented")
+}
+func (UnimplementedIndicatorsServer) StreamSeries(*GetSeriesRequest, Indicators_StreamSeriesServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamSeries not implemented")
+}
+func (UnimplementedIndicatorsServer) GetLatest(context.Context, *GetLatestRequest) (*Observation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLatest not implemented")
+}
+func (UnimplementedIndicatorsServer) Convert(context.Context, *ConvertRequest) (*ConvertResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Convert not implemented")
+}
+func (UnimplementedIndicatorsServer) mustEmbedUnimplementedIndicatorsServer() {}
+
+// UnsafeIndicatorsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to IndicatorsServer will
+// result in compilation errors.
+type UnsafeIndicatorsServer interface {
+	mustEmbedUnimplementedIndicatorsServer()
+}
+
+func RegisterIndicatorsServer(s grpc.ServiceRegistrar, srv IndicatorsServer) {
+	s.RegisterService(&Indicators_ServiceDesc, srv)
+}
+
+func _Indicators_GetSeries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSeriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IndicatorsServer).GetSeries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/libertadfinanciera.v1.Indicators/GetSeries",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IndicatorsServer).GetSeries(ctx, req.(*GetSeriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Indicators_StreamSeries_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetSeriesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(IndicatorsServer).StreamSeries(m, &indicatorsStreamSeriesServer{stream})
+}
+
+type Indicators_StreamSeriesServer interface {
+	Send(*Observation) error
+	grpc.ServerStream
+}
+
+type indicatorsStreamSeriesServer struct {
+	grpc.ServerStream
+}
+
+func (x *indicatorsStreamSeriesServer) Send(m *Observation) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Indicators_GetLatest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLatestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IndicatorsServer).GetLatest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/libertadfinanciera.v1.Indicators/GetLatest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IndicatorsServer).GetLatest(ctx, req.(*GetLatestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Indicators_Convert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConvertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IndicatorsServer).Convert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/libertadfinanciera.v1.Indicators/Convert",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IndicatorsServer).Convert(ctx, req.(*ConvertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Indicators_ServiceDesc is the grpc.ServiceDesc for Indicators service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Indicators_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "libertadfinanciera.v1.Indicators",
+	HandlerType: (*IndicatorsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetSeries",
+			Handler:    _Indicators_GetSeries_Handler,
+		},
+		{
+			MethodName: "GetLatest",
+			Handler:    _Indicators_GetLatest_Handler,
+		},
+		{
+			MethodName: "Convert",
+			Handler:    _Indicators_Convert_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamSeries",
+			Handler:       _Indicators_StreamSeries_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "indicators.proto",
+}
@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the buckets that are full again are forgotten
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens float64
+	rate   float64
+	burst  float64
+	last   time.Time
+}
+
+// refill adds the tokens earned since the last request, up to the burst
+func (b *bucket) refill(now time.Time) {
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+}
+
+// Limiter has a token bucket per client. The buckets start full, a client can spend its whole burst at
+// once and then it earns tokens at its rate.
+type Limiter struct {
+	mutex     sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func NewLimiter() *Limiter {
+	return &Limiter{
+		buckets:   map[string]*bucket{},
+		lastSweep: time.Now(),
+	}
+}
+
+// Take spends cost tokens of the bucket of a client. When there are not enough it spends nothing and
+// returns how long until there will be.
+func (limiter *Limiter) Take(key string, cost float64, perMinute float64, burst float64) (time.Duration, bool) {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	now := time.Now()
+	limiter.sweep(now)
+
+	rate := perMinute / 60
+	b, found := limiter.buckets[key]
+	if !found {
+		b = &bucket{tokens: burst, last: now}
+		limiter.buckets[key] = b
+	}
+	b.rate = rate
+	b.burst = burst
+	b.refill(now)
+
+	if b.tokens >= cost {
+		b.tokens -= cost
+		return 0, true
+	}
+	if rate <= 0 {
+		return time.Duration(math.MaxInt64), false
+	}
+	return time.Duration((cost - b.tokens) / rate * float64(time.Second)), false
+}
+
+// sweep forgets the buckets that are full, they are the same as a new one
+func (limiter *Limiter) sweep(now time.Time) {
+	if now.Sub(limiter.lastSweep) < sweepInterval {
+		return
+	}
+	limiter.lastSweep = now
+	for key, b := range limiter.buckets {
+		b.refill(now)
+		if b.tokens >= b.burst {
+			delete(limiter.buckets, key)
+		}
+	}
+}
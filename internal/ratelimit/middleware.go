@@ -0,0 +1,115 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/configuration"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+)
+
+// LimitError is returned when a client has spent its tokens, it tells when to retry
+type LimitError struct {
+	RetryAfter time.Duration
+}
+
+func (err *LimitError) Error() string {
+	return fmt.Sprintf("%s. Retry in %d seconds", utils.ErrRateLimited, err.Seconds())
+}
+
+func (err *LimitError) Unwrap() error {
+	return utils.ErrRateLimited
+}
+
+// Seconds is the Retry-After of the error, rounded up
+func (err *LimitError) Seconds() int {
+	return int(math.Ceil(err.RetryAfter.Seconds()))
+}
+
+// Coster is a request that knows its own cost, like a GraphQL query that is measured before it is executed
+// or a periodicity that scrapes the whole history of an indicator without a range
+type Coster interface {
+	Cost(daysPerToken int) float64
+}
+
+// Cost charges a token per request plus one per daysPerToken days of its range, for every series it
+// compares. A lookup of today costs one token, ten years of exchange rates cost about forty: every
+// month or year of the range is another table scraped from the BCCR.
+func Cost(request interface{}, daysPerToken int) float64 {
+	if coster, ok := request.(Coster); ok {
+		return math.Max(coster.Cost(daysPerToken), 1)
+	}
+	value := reflect.ValueOf(request)
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return 1
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct || daysPerToken <= 0 {
+		return 1
+	}
+
+	dateFrom, _ := fieldOf(value, "DateFrom").(time.Time)
+	if dateFrom.IsZero() {
+		return 1
+	}
+	dateTo, _ := fieldOf(value, "DateTo").(time.Time)
+	cost := RangeCost(dateFrom, dateTo, daysPerToken)
+
+	if series := value.FieldByName("Series"); series.IsValid() && series.Kind() == reflect.Slice && series.Len() > 1 {
+		cost *= float64(series.Len())
+	}
+	return math.Max(cost, 1)
+}
+
+func fieldOf(value reflect.Value, name string) interface{} {
+	field := value.FieldByName(name)
+	if !field.IsValid() || !field.CanInterface() {
+		return nil
+	}
+	return field.Interface()
+}
+
+// EndpointMiddleware charges the cost of every request to the bucket of its key, or of its IP when it
+// has no key. A request that costs more than the burst is charged the whole burst, otherwise it could
+// never be answered.
+func EndpointMiddleware(limiter *Limiter, config configuration.RateLimitConfig) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			client, found := utils.ClientFrom(ctx)
+			if !found {
+				return next(ctx, request)
+			}
+
+			key, perMinute, burst := "ip:"+client.IP, config.IPPerMinute, config.IPBurst
+			if client.ID != "" {
+				key, perMinute, burst = "key:"+client.ID, config.KeyPerMinute, config.KeyBurst
+				if client.RequestsPerMinute > 0 {
+					perMinute, burst = client.RequestsPerMinute, client.RequestsPerMinute
+				}
+			}
+
+			cost := math.Min(Cost(request, config.DaysPerToken), burst)
+			if wait, allowed := limiter.Take(key, cost, perMinute, burst); !allowed {
+				return nil, &LimitError{RetryAfter: wait}
+			}
+			return next(ctx, request)
+		}
+	}
+}
+
+// RangeCost charges a token plus one per daysPerToken days of the range, a range without end ends today
+func RangeCost(dateFrom time.Time, dateTo time.Time, daysPerToken int) float64 {
+	if dateFrom.IsZero() || daysPerToken <= 0 {
+		return 1
+	}
+	if dateTo.IsZero() {
+		dateTo = utils.Now()
+	}
+	return math.Max(1+math.Floor(dateTo.Sub(dateFrom).Hours()/24/float64(daysPerToken)), 1)
+}
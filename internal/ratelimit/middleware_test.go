@@ -0,0 +1,46 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+type rangeRequest struct {
+	DateFrom time.Time
+	DateTo   time.Time
+	Series   []string
+}
+
+type fixedCost float64
+
+func (cost fixedCost) Cost(int) float64 {
+	return float64(cost)
+}
+
+func TestCost(t *testing.T) {
+	dateTo := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tenYears := dateTo.AddDate(-10, 0, 0)
+	tests := []struct {
+		name     string
+		request  interface{}
+		expected float64
+	}{
+		{"a lookup of a day", struct{}{}, 1},
+		{"a range without start", rangeRequest{DateTo: dateTo}, 1},
+		{"a range of a day", rangeRequest{DateFrom: dateTo, DateTo: dateTo}, 1},
+		{"ten years", rangeRequest{DateFrom: tenYears, DateTo: dateTo}, 41},
+		{"ten years of a pointer", &rangeRequest{DateFrom: tenYears, DateTo: dateTo}, 41},
+		{"ten years of two series", rangeRequest{DateFrom: tenYears, DateTo: dateTo, Series: []string{"tbp", "prime"}}, 82},
+		{"a nil pointer", (*rangeRequest)(nil), 1},
+		{"a coster", fixedCost(12), 12},
+		{"a coster of nothing", fixedCost(0), 1},
+	}
+	for _, test := range tests {
+		if cost := Cost(test.request, 90); cost != test.expected {
+			t.Errorf("%s costs %v, expected %v", test.name, cost, test.expected)
+		}
+	}
+	if cost := Cost(rangeRequest{DateFrom: tenYears, DateTo: dateTo}, 0); cost != 1 {
+		t.Errorf("the ranges cost %v without days per token", cost)
+	}
+}
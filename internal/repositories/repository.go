@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"time"
 
 	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
@@ -10,4 +11,6 @@ type Repository interface {
 	SaveExchangeRate(exchangeRate models.ExchangeRate) (*models.ExchangeRate, error)
 	GetExchangeRates() ([]models.ExchangeRate, error)
 	GetExchangeRateByDate(dateFrom time.Time, dateTo time.Time) (models.ExchangeRate, error)
+	Ping(ctx context.Context) error
+	GetAPIKey(ctx context.Context, keyHash string) (*models.APIKey, error)
 }
@@ -0,0 +1,22 @@
+package supabase
+
+import (
+	"context"
+
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+)
+
+const apiKeysTableName = "api_keys"
+
+func (supa *Supabase) GetAPIKey(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	var results []models.APIKey
+	err := supa.Client.DB.From(apiKeysTableName).Select("*").Eq("key_hash", keyHash).ExecuteWithContext(ctx, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, utils.ErrNotFound
+	}
+	return &results[0], nil
+}
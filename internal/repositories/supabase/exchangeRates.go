@@ -1,6 +1,7 @@
 package supabase
 
 import (
+	"context"
 	"time"
 
 	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
@@ -31,3 +32,9 @@ func (supa *Supabase) GetExchangeRateByDate(dateFrom time.Time, dateTo time.Time
 	result := models.ExchangeRate{}
 	return result, nil
 }
+
+// Ping reads a single exchange rate to check the database is reachable
+func (supa *Supabase) Ping(ctx context.Context) error {
+	var result []models.ExchangeRate
+	return supa.Client.DB.From(tableName).Select("date").Limit(1).ExecuteWithContext(ctx, &result)
+}
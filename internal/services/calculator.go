@@ -0,0 +1,326 @@
+package services
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+)
+
+// MAXIMUM_LOAN_TERM is the longest term of a loan, in months. The schedule has a row per month.
+const MAXIMUM_LOAN_TERM = 600
+
+// datedValue is a single observation of any of the series used by the calculators
+type datedValue struct {
+	date  time.Time
+	value float64
+}
+
+func monthKey(date time.Time) int {
+	return date.Year()*12 + int(date.Month()) - 1
+}
+
+func (service *ServiceAPI) getInflationSeries(ctx context.Context, currency string, dateFrom time.Time, dateTo time.Time) ([]datedValue, error) {
+	req := GetAllDollarColonesChangesRequest{
+		DateFrom: dateFrom,
+		DateTo:   dateTo,
+	}
+
+	series := []datedValue{}
+	switch currency {
+	case models.CurrencyColones:
+		response := service.GetCostaRicaInflationRates(ctx, req)
+		if response.Err != nil {
+			return nil, response.Err
+		}
+		for _, inflationRate := range response.InflationRates {
+			series = append(series, datedValue{date: inflationRate.Date, value: inflationRate.Value})
+		}
+	case models.CurrencyDollars:
+		response := service.GetUSAInflationRates(ctx, req)
+		if response.Err != nil {
+			return nil, response.Err
+		}
+		for _, inflationRate := range response.InflationRates {
+			series = append(series, datedValue{date: inflationRate.Date, value: inflationRate.Value})
+		}
+	default:
+		return nil, utils.ErrCurrency
+	}
+
+	sort.Slice(series, func(i, j int) bool {
+		return series[i].date.Before(series[j].date)
+	})
+	return series, nil
+}
+
+// compoundInterannualInflation chains interannual (year over year) inflation values backwards from the
+// last month available, twelve months at a time, so that every step is an exact price index ratio.
+// The remaining months at the beginning of the period are prorated geometrically.
+func compoundInterannualInflation(series []datedValue, dateFrom time.Time) (float64, int, time.Time, bool) {
+	if len(series) == 0 {
+		return 0, 0, time.Time{}, false
+	}
+
+	byMonth := map[int]float64{}
+	for _, value := range series {
+		byMonth[monthKey(value.date)] = value.value
+	}
+
+	last := series[len(series)-1].date
+	current := monthKey(last)
+	months := current - monthKey(dateFrom)
+	if months < 0 {
+		return 0, 0, last, false
+	}
+
+	factor := 1.0
+	remaining := months
+	for remaining >= 12 {
+		value, ok := byMonth[current]
+		if !ok {
+			return 0, months, last, false
+		}
+		factor *= 1 + value/100
+		current -= 12
+		remaining -= 12
+	}
+
+	if remaining > 0 {
+		value, ok := byMonth[current]
+		if !ok {
+			return 0, months, last, false
+		}
+		factor *= math.Pow(1+value/100, float64(remaining)/12)
+	}
+
+	return factor, months, last, true
+}
+
+func (service *ServiceAPI) GetInflationAdjustedAmount(ctx context.Context, req GetInflationCalculatorRequest) *GetInflationCalculatorResponse {
+	series, err := service.getInflationSeries(ctx, req.Currency, req.DateFrom, req.DateTo)
+	if err != nil {
+		_ = level.Error(service.logger).Log("msg", "error getting inflation series for calculator",
+			"currency", req.Currency, "date_from", req.DateFrom, "date_to", req.DateTo, "error", err)
+		return &GetInflationCalculatorResponse{
+			InflationAdjustment: nil,
+			Err:                 err,
+		}
+	}
+
+	factor, months, lastDate, ok := compoundInterannualInflation(series, req.DateFrom)
+	if !ok {
+		_ = level.Debug(service.logger).Log("msg", "missing inflation data for calculator",
+			"currency", req.Currency, "date_from", req.DateFrom, "date_to", req.DateTo)
+		return &GetInflationCalculatorResponse{
+			InflationAdjustment: nil,
+			Err:                 utils.ErrNotFound,
+		}
+	}
+
+	return &GetInflationCalculatorResponse{
+		InflationAdjustment: &models.InflationAdjustment{
+			Currency:            req.Currency,
+			Amount:              req.Amount,
+			AdjustedAmount:      math.Round(req.Amount*factor*100) / 100,
+			CumulativeInflation: (factor - 1) * 100,
+			Months:              months,
+			DateFrom:            req.DateFrom,
+			DateTo:              lastDate,
+		},
+		Err: nil,
+	}
+}
+
+var periodsPerYear = map[string]int{
+	"monthly":   12,
+	"quarterly": 4,
+	"biannual":  2,
+	"annual":    1,
+}
+
+func (service *ServiceAPI) getReferenceRateSeries(ctx context.Context, referenceRate string, dateFrom time.Time, dateTo time.Time) ([]datedValue, error) {
+	req := GetAllDollarColonesChangesRequest{
+		DateFrom: dateFrom,
+		DateTo:   dateTo,
+	}
+
+	series := []datedValue{}
+	switch referenceRate {
+	case models.ReferenceRateBasicPassive:
+		response := service.GetBasicPassiveRates(ctx, req)
+		if response.Err != nil {
+			return nil, response.Err
+		}
+		for _, rate := range response.BasicPassiveRates {
+			series = append(series, datedValue{date: rate.Date, value: rate.Value})
+		}
+	case models.ReferenceRatePrime:
+		response := service.GetPrimeRates(ctx, req)
+		if response.Err != nil {
+			return nil, response.Err
+		}
+		for _, rate := range response.PrimeRates {
+			series = append(series, datedValue{date: rate.Date, value: rate.Value})
+		}
+	default:
+		return nil, utils.ErrReferenceRate
+	}
+
+	sort.Slice(series, func(i, j int) bool {
+		return series[i].date.Before(series[j].date)
+	})
+	return series, nil
+}
+
+// notFoundIfNil is the error of a response without data, the services can answer none without error
+func notFoundIfNil(err error) error {
+	if err == nil {
+		return utils.ErrNotFound
+	}
+	return err
+}
+
+func (service *ServiceAPI) getLatestReferenceRate(ctx context.Context, referenceRate string) (datedValue, error) {
+	switch referenceRate {
+	case models.ReferenceRateBasicPassive:
+		response := service.GetTodayBasicPassiveRate(ctx, GetTodayExchangeRateRequest{})
+		if response.BasicPassiveRate == nil {
+			return datedValue{}, notFoundIfNil(response.Err)
+		}
+		return datedValue{date: response.BasicPassiveRate.Date, value: response.BasicPassiveRate.Value}, nil
+	case models.ReferenceRatePrime:
+		response := service.GetTodayPrimeRate(ctx, GetTodayExchangeRateRequest{})
+		if response.PrimeRate == nil {
+			return datedValue{}, notFoundIfNil(response.Err)
+		}
+		return datedValue{date: response.PrimeRate.Date, value: response.PrimeRate.Value}, nil
+	default:
+		return datedValue{}, utils.ErrReferenceRate
+	}
+}
+
+// valueAt returns the last observation published on or before the date. Dates before the first
+// observation use the first one available.
+func valueAt(series []datedValue, date time.Time) float64 {
+	index := sort.Search(len(series), func(i int) bool {
+		return series[i].date.After(date)
+	})
+	if index == 0 {
+		return series[0].value
+	}
+	return series[index-1].value
+}
+
+func roundCurrency(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// buildSchedule resets the rate at the beginning of every period. Loans use the french method, so the
+// payment is recalculated with the remaining balance and periods each time the rate changes.
+func buildSchedule(req GetLoanCalculatorRequest, series []datedValue, dateFrom time.Time) *models.AmortizationSchedule {
+	monthsPerPeriod := 12 / periodsPerYear[req.Frequency]
+	periods := int(math.Ceil(float64(req.Term) / float64(monthsPerPeriod)))
+
+	schedule := &models.AmortizationSchedule{
+		Type:          req.Type,
+		ReferenceRate: req.ReferenceRate,
+		Principal:     req.Principal,
+		Margin:        req.Margin,
+		Term:          req.Term,
+		Frequency:     req.Frequency,
+		BackTest:      !req.DateFrom.IsZero(),
+		Payments:      []models.AmortizationPayment{},
+	}
+
+	balance := req.Principal
+	for i := 0; i < periods; i++ {
+		periodStart := dateFrom.AddDate(0, i*monthsPerPeriod, 0)
+		annualRate := valueAt(series, periodStart) + req.Margin
+		rate := annualRate / 100 / float64(periodsPerYear[req.Frequency])
+		interest := balance * rate
+
+		payment := 0.0
+		principal := 0.0
+		if req.Type == models.CalculatorLoan {
+			remaining := float64(periods - i)
+			if rate == 0 {
+				payment = balance / remaining
+			} else {
+				payment = balance * rate / (1 - math.Pow(1+rate, -remaining))
+			}
+			principal = payment - interest
+			balance -= principal
+		} else {
+			balance += interest
+		}
+
+		schedule.TotalPaid += payment
+		schedule.TotalInterest += interest
+		schedule.Payments = append(schedule.Payments, models.AmortizationPayment{
+			Number:    i + 1,
+			Date:      periodStart.AddDate(0, monthsPerPeriod, 0),
+			Rate:      annualRate,
+			Payment:   roundCurrency(payment),
+			Interest:  roundCurrency(interest),
+			Principal: roundCurrency(principal),
+			Balance:   roundCurrency(math.Max(balance, 0)),
+		})
+	}
+
+	schedule.TotalPaid = roundCurrency(schedule.TotalPaid)
+	schedule.TotalInterest = roundCurrency(schedule.TotalInterest)
+	return schedule
+}
+
+func (service *ServiceAPI) GetLoanSchedule(ctx context.Context, req GetLoanCalculatorRequest) *GetLoanCalculatorResponse {
+	today := utils.Now()
+	series := []datedValue{}
+	dateFrom := today
+
+	if !req.DateFrom.IsZero() {
+		// back-test against the published history, rates after today keep the last value published
+		dateFrom = req.DateFrom
+		dateTo := req.DateFrom.AddDate(0, req.Term, 0)
+		if dateTo.After(today) {
+			dateTo = today
+		}
+		result, err := service.getReferenceRateSeries(ctx, req.ReferenceRate, dateFrom, dateTo)
+		if err != nil {
+			_ = level.Error(service.logger).Log("msg", "error getting reference rate series for calculator",
+				"reference_rate", req.ReferenceRate, "date_from", dateFrom, "date_to", dateTo, "error", err)
+			return &GetLoanCalculatorResponse{
+				Schedule: nil,
+				Err:      err,
+			}
+		}
+		series = result
+	} else {
+		latest, err := service.getLatestReferenceRate(ctx, req.ReferenceRate)
+		if err != nil {
+			_ = level.Error(service.logger).Log("msg", "error getting latest reference rate for calculator",
+				"reference_rate", req.ReferenceRate, "error", err)
+			return &GetLoanCalculatorResponse{
+				Schedule: nil,
+				Err:      err,
+			}
+		}
+		series = append(series, latest)
+	}
+
+	if len(series) == 0 {
+		return &GetLoanCalculatorResponse{
+			Schedule: nil,
+			Err:      utils.ErrNotFound,
+		}
+	}
+
+	return &GetLoanCalculatorResponse{
+		Schedule: buildSchedule(req, series, dateFrom),
+		Err:      nil,
+	}
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/log"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/services/scrapper"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+)
+
+// fakeScrapper answers the scrapes of the tests from memory, the methods a test does not set panic
+type fakeScrapper struct {
+	scrapper.Scrapper
+	basicPassiveRates []models.BasicPassiveRate
+}
+
+// GetBasicPassiveDateByDate answers an empty rate for the days without one, like the BCCR tables
+func (fake *fakeScrapper) GetBasicPassiveDateByDate(_ context.Context, date time.Time) (*models.BasicPassiveRate, error) {
+	for _, rate := range fake.basicPassiveRates {
+		if rate.Date.Format(utils.DATE_FORMAT) == date.Format(utils.DATE_FORMAT) {
+			rate := rate
+			return &rate, nil
+		}
+	}
+	return &models.BasicPassiveRate{}, nil
+}
+
+func (fake *fakeScrapper) GetBasicPassiveRateByDates(_ context.Context, dateFrom time.Time, dateTo time.Time) ([]models.BasicPassiveRate, error) {
+	rates := []models.BasicPassiveRate{}
+	for _, rate := range fake.basicPassiveRates {
+		if !rate.Date.Before(dateFrom) && !rate.Date.After(dateTo) {
+			rates = append(rates, rate)
+		}
+	}
+	return rates, nil
+}
+
+func newTestService(fake scrapper.Scrapper) *ServiceAPI {
+	return NewService(log.NewNopLogger(), fake, nil, discard.NewHistogram())
+}
+
+func loanRequest(term int) GetLoanCalculatorRequest {
+	return GetLoanCalculatorRequest{
+		Type:          models.CalculatorLoan,
+		Principal:     1000000,
+		Term:          term,
+		Margin:        2,
+		ReferenceRate: models.ReferenceRateBasicPassive,
+		Frequency:     "monthly",
+	}
+}
+
+func TestLoanScheduleOfTheLongestTerm(t *testing.T) {
+	today := utils.Now()
+	service := newTestService(&fakeScrapper{basicPassiveRates: []models.BasicPassiveRate{
+		{Date: today.AddDate(0, 0, -1), Value: 4},
+	}})
+
+	response := service.GetLoanSchedule(context.Background(), loanRequest(MAXIMUM_LOAN_TERM))
+	if response.Err != nil {
+		t.Fatal(response.Err)
+	}
+	payments := response.Schedule.Payments
+	if len(payments) != MAXIMUM_LOAN_TERM {
+		t.Fatalf("%d payments, expected %d", len(payments), MAXIMUM_LOAN_TERM)
+	}
+	if payments[0].Rate != 6 {
+		t.Errorf("the rate is %v, expected the TBP of yesterday plus the margin", payments[0].Rate)
+	}
+	if last := payments[len(payments)-1]; last.Balance != 0 {
+		t.Errorf("the loan ends with a balance of %v", last.Balance)
+	}
+}
+
+func TestLoanScheduleWithoutReferenceRate(t *testing.T) {
+	service := newTestService(&fakeScrapper{})
+
+	response := service.GetLoanSchedule(context.Background(), loanRequest(12))
+	if response.Err == nil || response.Schedule != nil {
+		t.Errorf("a schedule without a published TBP: %+v", response)
+	}
+}
+
+func TestLoanBackTestWithoutReferenceRates(t *testing.T) {
+	service := newTestService(&fakeScrapper{})
+	req := loanRequest(12)
+	req.DateFrom = time.Date(2020, 1, 1, 0, 0, 0, 0, utils.Location())
+
+	response := service.GetLoanSchedule(context.Background(), req)
+	if !errors.Is(response.Err, utils.ErrNotFound) || response.Schedule != nil {
+		t.Errorf("a back-test without rates answered %+v, expected not found", response)
+	}
+}
+
+func TestLatestReferenceRateIsNotFoundWithoutError(t *testing.T) {
+	if err := notFoundIfNil(nil); !errors.Is(err, utils.ErrNotFound) {
+		t.Errorf("a response without rate nor error is %v, expected not found", err)
+	}
+	scrapeErr := errors.New("the BCCR is down")
+	if err := notFoundIfNil(scrapeErr); err != scrapeErr {
+		t.Errorf("the scrape error was replaced by %v", err)
+	}
+}
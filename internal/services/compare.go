@@ -0,0 +1,230 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+)
+
+const MAXIMUM_COMPARED_SERIES = 6
+
+// comparableSeries is a series that can be compared. The lookback is how far before the range the series
+// is fetched, so the first periods can be forward-filled with the last value published before them.
+type comparableSeries struct {
+	lookbackDays int
+	fetch        func(ctx context.Context, service *ServiceAPI, req GetAllDollarColonesChangesRequest) ([]datedValue, error)
+}
+
+// fetchOf adapts the getter of a series to the comparisons, items returns the list of its response and value
+// the value of every item
+func fetchOf[R any, T any](get func(*ServiceAPI, context.Context, GetAllDollarColonesChangesRequest) *R, items func(*R) ([]T, error), value func(T) datedValue) func(ctx context.Context, service *ServiceAPI, req GetAllDollarColonesChangesRequest) ([]datedValue, error) {
+	return func(ctx context.Context, service *ServiceAPI, req GetAllDollarColonesChangesRequest) ([]datedValue, error) {
+		list, err := items(get(service, ctx, req))
+		series := make([]datedValue, 0, len(list))
+		for _, item := range list {
+			series = append(series, value(item))
+		}
+		return series, err
+	}
+}
+
+var comparableSeriesCatalog = map[string]comparableSeries{
+	"exchange_rate": {lookbackDays: 7, fetch: fetchOf((*ServiceAPI).GetDollarColonesChange,
+		func(response *GetAllDollarColonesChangesResponse) ([]models.ExchangeRate, error) {
+			return response.ExchangesRates, response.Err
+		},
+		func(item models.ExchangeRate) datedValue { return datedValue{date: item.Date, value: item.SalePrice} })},
+	"monex": {lookbackDays: 7, fetch: fetchOf((*ServiceAPI).GetMonexExchangeRates,
+		func(response *GetMonexExchangeRatesResponse) ([]models.MonexExchangeRate, error) {
+			return response.MonexExchangeRates, response.Err
+		},
+		func(item models.MonexExchangeRate) datedValue { return datedValue{date: item.Date, value: item.Value} })},
+	"euro": {lookbackDays: 7, fetch: fetchOf((*ServiceAPI).GetEuroExchangeRates,
+		func(response *GetEuroExchangeRatesResponse) ([]models.EuroExchangeRate, error) {
+			return response.EuroExchangeRates, response.Err
+		},
+		func(item models.EuroExchangeRate) datedValue { return datedValue{date: item.Date, value: item.Value} })},
+	"tbp": {lookbackDays: 14, fetch: fetchOf((*ServiceAPI).GetBasicPassiveRates,
+		func(response *GetBasicPassiveRatesResponse) ([]models.BasicPassiveRate, error) {
+			return response.BasicPassiveRates, response.Err
+		},
+		func(item models.BasicPassiveRate) datedValue { return datedValue{date: item.Date, value: item.Value} })},
+	"tri": {lookbackDays: 14, fetch: fetchOf((*ServiceAPI).GetReferenceDepositRates,
+		func(response *GetReferenceDepositRatesResponse) ([]models.ReferenceDepositRate, error) {
+			return response.ReferenceDepositRates, response.Err
+		},
+		func(item models.ReferenceDepositRate) datedValue {
+			return datedValue{date: item.Date, value: item.Value}
+		})},
+	"mpr": {lookbackDays: 180, fetch: fetchOf((*ServiceAPI).GetMonetaryPolicyRates,
+		func(response *GetMonetaryPolicyRatesResponse) ([]models.MonetaryPolicyRate, error) {
+			return response.MonetaryPolicyRates, response.Err
+		},
+		func(item models.MonetaryPolicyRate) datedValue { return datedValue{date: item.Date, value: item.Value} })},
+	"prime": {lookbackDays: 14, fetch: fetchOf((*ServiceAPI).GetPrimeRates,
+		func(response *GetPrimeRatesResponse) ([]models.PrimeRate, error) {
+			return response.PrimeRates, response.Err
+		},
+		func(item models.PrimeRate) datedValue { return datedValue{date: item.Date, value: item.Value} })},
+	"treasury_usa": {lookbackDays: 7, fetch: fetchOf((*ServiceAPI).GetTreasuryRatesUSA,
+		func(response *GetTreasuryRatesUSAResponse) ([]models.TreasuryRateUSA, error) {
+			return response.TreasuryRatesUSA, response.Err
+		},
+		func(item models.TreasuryRateUSA) datedValue { return datedValue{date: item.Date, value: item.Value} })},
+	"inflation_cr": {lookbackDays: 62, fetch: fetchOf((*ServiceAPI).GetCostaRicaInflationRates,
+		func(response *GetCostaRicaInflationRatesResponse) ([]models.CostaRicaInflationRate, error) {
+			return response.InflationRates, response.Err
+		},
+		func(item models.CostaRicaInflationRate) datedValue {
+			return datedValue{date: item.Date, value: item.Value}
+		})},
+	"inflation_usa": {lookbackDays: 62, fetch: fetchOf((*ServiceAPI).GetUSAInflationRates,
+		func(response *GetUSAInflationRatesResponse) ([]models.USAInflationRate, error) {
+			return response.InflationRates, response.Err
+		},
+		func(item models.USAInflationRate) datedValue { return datedValue{date: item.Date, value: item.Value} })},
+}
+
+// ComparableSeries returns the names of the series that can be compared
+func ComparableSeries() []string {
+	names := make([]string, 0, len(comparableSeriesCatalog))
+	for name := range comparableSeriesCatalog {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseComparableSeries validates a comma separated list of series, the repeated ones are ignored
+func ParseComparableSeries(value string) ([]string, error) {
+	names := []string{}
+	seen := map[string]bool{}
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		if _, ok := comparableSeriesCatalog[name]; !ok {
+			return nil, utils.ErrSeries
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	if len(names) == 0 || len(names) > MAXIMUM_COMPARED_SERIES {
+		return nil, utils.ErrSeries
+	}
+	return names, nil
+}
+
+// periodEnds returns the last day of every period between the dates, the last period ends on dateTo
+func periodEnds(dateFrom time.Time, dateTo time.Time, frequency string) []time.Time {
+	ends := []time.Time{}
+	day := time.Date(dateFrom.Year(), dateFrom.Month(), dateFrom.Day(), 23, 59, 59, 0, dateFrom.Location())
+	last := time.Date(dateTo.Year(), dateTo.Month(), dateTo.Day(), 23, 59, 59, 0, dateTo.Location())
+	for !day.After(last) {
+		end := day
+		switch frequency {
+		case models.FrequencyWeekly:
+			// weeks end on sunday
+			end = day.AddDate(0, 0, (7-int(day.Weekday()))%7)
+		case models.FrequencyMonthly:
+			end = time.Date(day.Year(), day.Month()+1, 0, 23, 59, 59, 0, day.Location())
+		}
+		if end.After(last) {
+			end = last
+		}
+		ends = append(ends, end)
+		day = end.AddDate(0, 0, 1)
+	}
+	return ends
+}
+
+// lastValueAt returns the last observation published on or before the date
+func lastValueAt(series []datedValue, date time.Time) (float64, bool) {
+	index := sort.Search(len(series), func(i int) bool {
+		return series[i].date.After(date)
+	})
+	if index == 0 {
+		return 0, false
+	}
+	return series[index-1].value, true
+}
+
+// alignSeries samples every series at the end of each period. Higher frequency series keep their last
+// value of the period and lower frequency ones are forward-filled until the next observation.
+func alignSeries(names []string, series map[string][]datedValue, ends []time.Time) []models.SeriesComparisonRow {
+	rows := make([]models.SeriesComparisonRow, 0, len(ends))
+	for _, end := range ends {
+		row := models.SeriesComparisonRow{
+			Date:   time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, end.Location()),
+			Values: map[string]*float64{},
+		}
+		for _, name := range names {
+			if value, ok := lastValueAt(series[name], end); ok {
+				row.Values[name] = &value
+			} else {
+				row.Values[name] = nil
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func (service *ServiceAPI) CompareSeries(ctx context.Context, req GetCompareSeriesRequest) *GetCompareSeriesResponse {
+	type result struct {
+		name   string
+		series []datedValue
+		err    error
+	}
+
+	service.fanOut.With("operation", "compare").Observe(float64(len(req.Series)))
+	results := make(chan result, len(req.Series))
+	for _, name := range req.Series {
+		go func(name string, comparable comparableSeries) {
+			series, err := comparable.fetch(ctx, service, GetAllDollarColonesChangesRequest{
+				DateFrom: req.DateFrom.AddDate(0, 0, -comparable.lookbackDays),
+				DateTo:   req.DateTo,
+			})
+			sort.Slice(series, func(i, j int) bool {
+				return series[i].date.Before(series[j].date)
+			})
+			results <- result{name: name, series: series, err: err}
+		}(name, comparableSeriesCatalog[name])
+	}
+
+	series := map[string][]datedValue{}
+	var err error
+	for range req.Series {
+		result := <-results
+		if result.err != nil {
+			_ = level.Error(service.logger).Log("msg", "error getting series to compare", "series", result.name,
+				"date_from", req.DateFrom, "date_to", req.DateTo, "error", result.err)
+			err = result.err
+			continue
+		}
+		series[result.name] = result.series
+	}
+	if err != nil {
+		return &GetCompareSeriesResponse{
+			Comparison: nil,
+			Err:        err,
+		}
+	}
+
+	return &GetCompareSeriesResponse{
+		Comparison: &models.SeriesComparison{
+			Series:    req.Series,
+			Frequency: req.Frequency,
+			DateFrom:  req.DateFrom,
+			DateTo:    req.DateTo,
+			Rows:      alignSeries(req.Series, series, periodEnds(req.DateFrom, req.DateTo, req.Frequency)),
+		},
+		Err: nil,
+	}
+}
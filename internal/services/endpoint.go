@@ -22,8 +22,24 @@ type Endpoints struct {
 	GetCostaRicaInflationRate          endpoint.Endpoint
 	GetTreasuryRatesUSA                endpoint.Endpoint
 	GetTreasuryRateUSA                 endpoint.Endpoint
+	GetTreasuryYieldCurve              endpoint.Endpoint
+	GetTreasuryYieldSeries             endpoint.Endpoint
 	GetUSAInflationRates               endpoint.Endpoint
 	GetUSAInflationRate                endpoint.Endpoint
+	GetUSAMonthlyInflationRates        endpoint.Endpoint
+	GetUSAConsumerPriceIndexes         endpoint.Endpoint
+	GetMonexExchangeRates              endpoint.Endpoint
+	GetTodayMonexExchangeRate          endpoint.Endpoint
+	GetEuroExchangeRates               endpoint.Endpoint
+	GetTodayEuroExchangeRate           endpoint.Endpoint
+	GetReferenceDepositRates           endpoint.Endpoint
+	GetTodayReferenceDepositRate       endpoint.Endpoint
+	GetInflationAdjustedAmount         endpoint.Endpoint
+	GetLoanSchedule                    endpoint.Endpoint
+	CompareSeries                      endpoint.Endpoint
+	GetSeries                          endpoint.Endpoint
+	GetLatestObservation               endpoint.Endpoint
+	Convert                            endpoint.Endpoint
 }
 
 func MakeEndpoints(s *ServiceAPI) Endpoints {
@@ -42,8 +58,24 @@ func MakeEndpoints(s *ServiceAPI) Endpoints {
 		GetCostaRicaInflationRate:          makeGetCostaRicaInflationRateEndpoint(s),
 		GetTreasuryRatesUSA:                makeGetTreasuryRatesUSAEndpoint(s),
 		GetTreasuryRateUSA:                 makeGetTreasuryRateUSAEndpoint(s),
+		GetTreasuryYieldCurve:              makeGetTreasuryYieldCurveEndpoint(s),
+		GetTreasuryYieldSeries:             makeGetTreasuryYieldSeriesEndpoint(s),
 		GetUSAInflationRates:               makeGetUSAInflationRatesEndpoint(s),
 		GetUSAInflationRate:                makeGetUSAInflationRateEndpoint(s),
+		GetUSAMonthlyInflationRates:        makeGetUSAMonthlyInflationRatesEndpoint(s),
+		GetUSAConsumerPriceIndexes:         makeGetUSAConsumerPriceIndexesEndpoint(s),
+		GetMonexExchangeRates:              makeGetMonexExchangeRatesEndpoint(s),
+		GetTodayMonexExchangeRate:          makeGetTodayMonexExchangeRateEndpoint(s),
+		GetEuroExchangeRates:               makeGetEuroExchangeRatesEndpoint(s),
+		GetTodayEuroExchangeRate:           makeGetTodayEuroExchangeRateEndpoint(s),
+		GetReferenceDepositRates:           makeGetReferenceDepositRatesEndpoint(s),
+		GetTodayReferenceDepositRate:       makeGetTodayReferenceDepositRateEndpoint(s),
+		GetInflationAdjustedAmount:         makeGetInflationAdjustedAmountEndpoint(s),
+		GetLoanSchedule:                    makeGetLoanScheduleEndpoint(s),
+		CompareSeries:                      makeCompareSeriesEndpoint(s),
+		GetSeries:                          makeGetSeriesEndpoint(s),
+		GetLatestObservation:               makeGetLatestObservationEndpoint(s),
+		Convert:                            makeConvertEndpoint(s),
 	}
 }
 
@@ -215,6 +247,30 @@ func makeGetTreasuryRateUSAEndpoint(s *ServiceAPI) endpoint.Endpoint {
 	}
 }
 
+func makeGetTreasuryYieldCurveEndpoint(s *ServiceAPI) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(GetTreasuryYieldCurveRequest)
+		if !ok {
+			return nil, utils.ErrDecodeRequest
+		}
+		result := s.GetTreasuryYieldCurve(ctx, req)
+
+		return result, nil
+	}
+}
+
+func makeGetTreasuryYieldSeriesEndpoint(s *ServiceAPI) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(GetTreasuryYieldSeriesRequest)
+		if !ok {
+			return nil, utils.ErrDecodeRequest
+		}
+		result := s.GetTreasuryYieldSeries(ctx, req)
+
+		return result, nil
+	}
+}
+
 func makeGetUSAInflationRatesEndpoint(s *ServiceAPI) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
 		req, ok := request.(GetAllDollarColonesChangesRequest)
@@ -238,3 +294,171 @@ func makeGetUSAInflationRateEndpoint(s *ServiceAPI) endpoint.Endpoint {
 		return result, nil
 	}
 }
+
+func makeGetUSAMonthlyInflationRatesEndpoint(s *ServiceAPI) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(GetAllDollarColonesChangesRequest)
+		if !ok {
+			return nil, utils.ErrDecodeRequest
+		}
+		result := s.GetUSAMonthlyInflationRates(ctx, req)
+
+		return result, nil
+	}
+}
+
+func makeGetUSAConsumerPriceIndexesEndpoint(s *ServiceAPI) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(GetAllDollarColonesChangesRequest)
+		if !ok {
+			return nil, utils.ErrDecodeRequest
+		}
+		result := s.GetUSAConsumerPriceIndexes(ctx, req)
+
+		return result, nil
+	}
+}
+
+func makeGetMonexExchangeRatesEndpoint(s *ServiceAPI) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(GetAllDollarColonesChangesRequest)
+		if !ok {
+			return nil, utils.ErrDecodeRequest
+		}
+		result := s.GetMonexExchangeRates(ctx, req)
+
+		return result, nil
+	}
+}
+
+func makeGetTodayMonexExchangeRateEndpoint(s *ServiceAPI) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(GetTodayExchangeRateRequest)
+		if !ok {
+			return nil, utils.ErrDecodeRequest
+		}
+		result := s.GetTodayMonexExchangeRate(ctx, req)
+
+		return result, nil
+	}
+}
+
+func makeGetEuroExchangeRatesEndpoint(s *ServiceAPI) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(GetAllDollarColonesChangesRequest)
+		if !ok {
+			return nil, utils.ErrDecodeRequest
+		}
+		result := s.GetEuroExchangeRates(ctx, req)
+
+		return result, nil
+	}
+}
+
+func makeGetTodayEuroExchangeRateEndpoint(s *ServiceAPI) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(GetTodayExchangeRateRequest)
+		if !ok {
+			return nil, utils.ErrDecodeRequest
+		}
+		result := s.GetTodayEuroExchangeRate(ctx, req)
+
+		return result, nil
+	}
+}
+
+func makeGetReferenceDepositRatesEndpoint(s *ServiceAPI) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(GetAllDollarColonesChangesRequest)
+		if !ok {
+			return nil, utils.ErrDecodeRequest
+		}
+		result := s.GetReferenceDepositRates(ctx, req)
+
+		return result, nil
+	}
+}
+
+func makeGetTodayReferenceDepositRateEndpoint(s *ServiceAPI) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(GetTodayExchangeRateRequest)
+		if !ok {
+			return nil, utils.ErrDecodeRequest
+		}
+		result := s.GetTodayReferenceDepositRate(ctx, req)
+
+		return result, nil
+	}
+}
+
+func makeGetInflationAdjustedAmountEndpoint(s *ServiceAPI) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(GetInflationCalculatorRequest)
+		if !ok {
+			return nil, utils.ErrDecodeRequest
+		}
+		result := s.GetInflationAdjustedAmount(ctx, req)
+
+		return result, nil
+	}
+}
+
+func makeGetLoanScheduleEndpoint(s *ServiceAPI) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(GetLoanCalculatorRequest)
+		if !ok {
+			return nil, utils.ErrDecodeRequest
+		}
+		result := s.GetLoanSchedule(ctx, req)
+
+		return result, nil
+	}
+}
+
+func makeCompareSeriesEndpoint(s *ServiceAPI) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(GetCompareSeriesRequest)
+		if !ok {
+			return nil, utils.ErrDecodeRequest
+		}
+		result := s.CompareSeries(ctx, req)
+
+		return result, nil
+	}
+}
+
+func makeGetSeriesEndpoint(s *ServiceAPI) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(GetSeriesRequest)
+		if !ok {
+			return nil, utils.ErrDecodeRequest
+		}
+		result := s.GetSeries(ctx, req)
+
+		return result, nil
+	}
+}
+
+func makeGetLatestObservationEndpoint(s *ServiceAPI) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(GetLatestObservationRequest)
+		if !ok {
+			return nil, utils.ErrDecodeRequest
+		}
+		result := s.GetLatestObservation(ctx, req)
+
+		return result, nil
+	}
+}
+
+func makeConvertEndpoint(s *ServiceAPI) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(GetConvertRequest)
+		if !ok {
+			return nil, utils.ErrDecodeRequest
+		}
+		result := s.Convert(ctx, req)
+
+		return result, nil
+	}
+}
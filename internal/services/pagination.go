@@ -0,0 +1,94 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+)
+
+// paginate sorts the observations by date in the order requested and returns the page of them. The cursor
+// takes precedence over the offset, it points to the last observation of the previous page so pages stay
+// consistent while new observations get published. Without a limit, it returns every observation.
+func paginate[T any](items []T, dateOf func(T) time.Time, page Pagination) ([]T, *models.ListMetadata) {
+	order := page.Order
+	if order == "" {
+		order = models.OrderDescending
+	}
+
+	sorted := make([]T, len(items))
+	copy(sorted, items)
+	sortByDate(sorted, dateOf, order)
+
+	meta := &models.ListMetadata{
+		Total:  len(sorted),
+		Limit:  page.Limit,
+		Offset: page.Offset,
+		Order:  order,
+	}
+	if len(sorted) > 0 {
+		first, last := dateOf(sorted[0]), dateOf(sorted[len(sorted)-1])
+		if order == models.OrderDescending {
+			first, last = last, first
+		}
+		meta.DateFrom, meta.DateTo = &first, &last
+	}
+
+	start := page.Offset
+	if page.Cursor != "" {
+		// the cursor is validated when the request is decoded
+		cursorDate, position, _ := utils.DecodeCursor(page.Cursor)
+		start = len(sorted)
+		for index, item := range sorted {
+			date := dateOf(item)
+			if date.Equal(cursorDate) {
+				// the observations of the cursor date that the previous pages did not return yet
+				if position == 0 {
+					start = index
+					break
+				}
+				position--
+				continue
+			}
+			if (order == models.OrderDescending && date.Before(cursorDate)) ||
+				(order == models.OrderAscending && date.After(cursorDate)) {
+				start = index
+				break
+			}
+		}
+		meta.Offset = start
+	}
+	if start > len(sorted) {
+		start = len(sorted)
+	}
+
+	end := len(sorted)
+	if page.Limit > 0 && start+page.Limit < end {
+		end = start + page.Limit
+		lastDate := dateOf(sorted[end-1])
+		meta.NextCursor = utils.EncodeCursor(lastDate, sameDateBefore(sorted[:end], dateOf, lastDate))
+	}
+
+	result := sorted[start:end]
+	meta.Count = len(result)
+	return result, meta
+}
+
+// sameDateBefore counts the observations of the date from the end of the items
+func sameDateBefore[T any](items []T, dateOf func(T) time.Time, date time.Time) int {
+	count := 0
+	for index := len(items) - 1; index >= 0 && dateOf(items[index]).Equal(date); index-- {
+		count++
+	}
+	return count
+}
+
+func sortByDate[T any](items []T, dateOf func(T) time.Time, order string) {
+	sort.SliceStable(items, func(i, j int) bool {
+		if order == models.OrderAscending {
+			return dateOf(items[i]).Before(dateOf(items[j]))
+		}
+		return dateOf(items[i]).After(dateOf(items[j]))
+	})
+}
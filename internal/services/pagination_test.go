@@ -0,0 +1,122 @@
+package services
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+)
+
+type dated struct {
+	date time.Time
+	name string
+}
+
+func dateOfDated(item dated) time.Time {
+	return item.date
+}
+
+func januaryDay(day int) time.Time {
+	return time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC)
+}
+
+// datedItems has several observations on the 2nd and the 3rd, so the pages end in the middle of a date
+func datedItems() []dated {
+	return []dated{
+		{januaryDay(1), "a"},
+		{januaryDay(2), "b"},
+		{januaryDay(2), "c"},
+		{januaryDay(2), "d"},
+		{januaryDay(3), "e"},
+		{januaryDay(3), "f"},
+		{januaryDay(4), "g"},
+	}
+}
+
+func names(items []dated) string {
+	result := ""
+	for _, item := range items {
+		result += item.name
+	}
+	return result
+}
+
+func encodeRawCursor(cursor string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursor))
+}
+
+func TestPaginateCursorWalksEveryObservationOnce(t *testing.T) {
+	tests := []struct {
+		order    string
+		limit    int
+		expected []string
+	}{
+		{models.OrderAscending, 2, []string{"ab", "cd", "ef", "g"}},
+		{models.OrderAscending, 3, []string{"abc", "def", "g"}},
+		{models.OrderDescending, 2, []string{"ge", "fb", "cd", "a"}},
+		{models.OrderDescending, 1, []string{"g", "e", "f", "b", "c", "d", "a"}},
+	}
+	for _, test := range tests {
+		page := Pagination{Limit: test.limit, Order: test.order}
+		pages := []string{}
+		for {
+			result, meta := paginate(datedItems(), dateOfDated, page)
+			pages = append(pages, names(result))
+			if meta.NextCursor == "" {
+				break
+			}
+			if len(pages) > len(datedItems()) {
+				t.Fatalf("%s by %d never ends: %v", test.order, test.limit, pages)
+			}
+			page.Cursor = meta.NextCursor
+		}
+		if len(pages) != len(test.expected) {
+			t.Errorf("%s by %d: pages %v, expected %v", test.order, test.limit, pages, test.expected)
+			continue
+		}
+		for index := range pages {
+			if pages[index] != test.expected[index] {
+				t.Errorf("%s by %d: pages %v, expected %v", test.order, test.limit, pages, test.expected)
+				break
+			}
+		}
+	}
+}
+
+func TestPaginateCursorWithoutPositionSkipsItsDate(t *testing.T) {
+	// the cursor of a date alone points after every observation of that date
+	legacy := encodeRawCursor(januaryDay(2).Format(time.RFC3339Nano))
+	result, meta := paginate(datedItems(), dateOfDated, Pagination{Order: models.OrderAscending, Cursor: legacy})
+	if names(result) != "efg" || meta.Offset != 4 {
+		t.Errorf("the page after the 2nd is %q at offset %d", names(result), meta.Offset)
+	}
+}
+
+func TestPaginateCursorTakesPrecedenceOverOffset(t *testing.T) {
+	first, meta := paginate(datedItems(), dateOfDated, Pagination{Limit: 2, Order: models.OrderAscending})
+	if names(first) != "ab" || meta.Offset != 0 || meta.Total != 7 {
+		t.Fatalf("the first page is %q with %+v", names(first), meta)
+	}
+	second, meta := paginate(datedItems(), dateOfDated, Pagination{Limit: 2, Offset: 5, Order: models.OrderAscending, Cursor: meta.NextCursor})
+	if names(second) != "cd" || meta.Offset != 2 {
+		t.Errorf("the second page is %q at offset %d", names(second), meta.Offset)
+	}
+	last, meta := paginate(datedItems(), dateOfDated, Pagination{Limit: 2, Offset: 6, Order: models.OrderAscending})
+	if names(last) != "g" || meta.NextCursor != "" {
+		t.Errorf("the last page is %q with the cursor %q", names(last), meta.NextCursor)
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	date, position, err := utils.DecodeCursor(utils.EncodeCursor(januaryDay(2), 3))
+	if err != nil || !date.Equal(januaryDay(2)) || position != 3 {
+		t.Errorf("decoded %v and %d: %v", date, position, err)
+	}
+	for _, cursor := range []string{"not base64 !", encodeRawCursor("yesterday"), encodeRawCursor("2024-01-02T00:00:00Z|0"), encodeRawCursor("2024-01-02T00:00:00Z|x")} {
+		if _, _, err := utils.DecodeCursor(cursor); err != utils.ErrInvalidCursor {
+			t.Errorf("the cursor %q was decoded: %v", cursor, err)
+		}
+	}
+}
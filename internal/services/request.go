@@ -1,10 +1,25 @@
 package services
 
-import "time"
+import (
+	"math"
+	"time"
+
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/ratelimit"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+)
 
 type GetAllDollarColonesChangesRequest struct {
-	DateFrom time.Time `json:"date_from"`
-	DateTo   time.Time `json:"date_to"`
+	DateFrom time.Time  `json:"date_from"`
+	DateTo   time.Time  `json:"date_to"`
+	Page     Pagination `json:"page"`
+}
+
+// Pagination of the list endpoints, a zero value returns every observation newest first
+type Pagination struct {
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+	Cursor string `json:"cursor"`
+	Order  string `json:"order"`
 }
 
 type GetTodayExchangeRateRequest struct {
@@ -12,4 +27,72 @@ type GetTodayExchangeRateRequest struct {
 
 type GetDataByFilterRequest struct {
 	Periodicity string `json:"periodicity"`
+	// HistoryFrom is the first date of the indicator, every periodicity scrapes from it until today
+	HistoryFrom time.Time `json:"-"`
+}
+
+// periodicityScrapes is the number of tables of the whole history scraped by each periodicity
+var periodicityScrapes = map[string]float64{
+	"quarterly":    5,
+	"biannual":     2,
+	"annual":       1,
+	"quinquennium": 1,
+}
+
+// Cost charges the whole history of the indicator for every table the periodicity scrapes, the request has
+// no range but the exchange rates since 1983 are not a lookup of a day
+func (req GetDataByFilterRequest) Cost(daysPerToken int) float64 {
+	return math.Max(ratelimit.RangeCost(req.HistoryFrom, utils.Now(), daysPerToken)*periodicityScrapes[req.Periodicity], 1)
+}
+
+type GetInflationCalculatorRequest struct {
+	Amount   float64   `json:"amount"`
+	Currency string    `json:"currency"`
+	DateFrom time.Time `json:"date_from"`
+	DateTo   time.Time `json:"date_to"`
+}
+
+type GetLoanCalculatorRequest struct {
+	Type          string    `json:"type"`
+	Principal     float64   `json:"principal"`
+	Term          int       `json:"term"`
+	Margin        float64   `json:"margin"`
+	ReferenceRate string    `json:"reference_rate"`
+	Frequency     string    `json:"frequency"`
+	DateFrom      time.Time `json:"date_from"`
+}
+
+type GetTreasuryYieldCurveRequest struct {
+	Date time.Time `json:"date"`
+}
+
+type GetTreasuryYieldSeriesRequest struct {
+	Maturity string     `json:"maturity"`
+	DateFrom time.Time  `json:"date_from"`
+	DateTo   time.Time  `json:"date_to"`
+	Page     Pagination `json:"page"`
+}
+
+type GetCompareSeriesRequest struct {
+	Series    []string  `json:"series"`
+	Frequency string    `json:"frequency"`
+	DateFrom  time.Time `json:"date_from"`
+	DateTo    time.Time `json:"date_to"`
+}
+
+type GetSeriesRequest struct {
+	Series   string    `json:"series"`
+	DateFrom time.Time `json:"date_from"`
+	DateTo   time.Time `json:"date_to"`
+}
+
+type GetLatestObservationRequest struct {
+	Series string `json:"series"`
+}
+
+type GetConvertRequest struct {
+	Amount float64   `json:"amount"`
+	From   string    `json:"from"`
+	To     string    `json:"to"`
+	Date   time.Time `json:"date"`
 }
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/ratelimit"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+)
+
+func TestPeriodicityCostsTheWholeHistory(t *testing.T) {
+	history := ratelimit.RangeCost(EXCHANGE_RATES_HISTORY_FROM, utils.Now(), 90)
+	tests := []struct {
+		periodicity string
+		expected    float64
+	}{
+		{"monthly", 1},
+		{"annual", history},
+		{"quinquennium", history},
+		{"biannual", 2 * history},
+		{"quarterly", 5 * history},
+	}
+	for _, test := range tests {
+		request := GetDataByFilterRequest{Periodicity: test.periodicity, HistoryFrom: EXCHANGE_RATES_HISTORY_FROM}
+		if cost := ratelimit.Cost(request, 90); cost != test.expected {
+			t.Errorf("%s costs %v, expected %v", test.periodicity, cost, test.expected)
+		}
+	}
+	if history < 160 {
+		t.Errorf("the exchange rates since 1983 cost %v", history)
+	}
+}
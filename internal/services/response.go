@@ -1,9 +1,12 @@
 package services
 
-import "github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+import (
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+)
 
 type GetAllDollarColonesChangesResponse struct {
 	ExchangesRates []models.ExchangeRate `json:"data"`
+	Meta           *models.ListMetadata  `json:"meta,omitempty"`
 	Err            error                 `json:"error,omitempty"`
 }
 
@@ -18,6 +21,7 @@ func (r GetTodayExchangeRateResponse) error() error { return r.Err }
 
 type GetBasicPassiveRatesResponse struct {
 	BasicPassiveRates []models.BasicPassiveRate `json:"data"`
+	Meta              *models.ListMetadata      `json:"meta,omitempty"`
 	Err               error                     `json:"error,omitempty"`
 }
 
@@ -32,6 +36,7 @@ func (r GetTodayBasicPassiveRateResponse) error() error { return r.Err }
 
 type GetMonetaryPolicyRatesResponse struct {
 	MonetaryPolicyRates []models.MonetaryPolicyRate `json:"data"`
+	Meta                *models.ListMetadata        `json:"meta,omitempty"`
 	Err                 error                       `json:"error,omitempty"`
 }
 
@@ -45,8 +50,9 @@ type GetTodayMonetaryPolicyRateResponse struct {
 func (r GetTodayMonetaryPolicyRateResponse) error() error { return r.Err }
 
 type GetPrimeRatesResponse struct {
-	PrimeRates []models.PrimeRate `json:"data"`
-	Err        error              `json:"error,omitempty"`
+	PrimeRates []models.PrimeRate   `json:"data"`
+	Meta       *models.ListMetadata `json:"meta,omitempty"`
+	Err        error                `json:"error,omitempty"`
 }
 
 func (r GetPrimeRatesResponse) error() error { return r.Err }
@@ -60,6 +66,7 @@ func (r GetTodayPrimeRateResponse) error() error { return r.Err }
 
 type GetCostaRicaInflationRatesResponse struct {
 	InflationRates []models.CostaRicaInflationRate `json:"data"`
+	Meta           *models.ListMetadata            `json:"meta,omitempty"`
 	Err            error                           `json:"error,omitempty"`
 }
 
@@ -74,6 +81,7 @@ func (r GetTodayCostaRicaInflationRateResponse) error() error { return r.Err }
 
 type GetTreasuryRatesUSAResponse struct {
 	TreasuryRatesUSA []models.TreasuryRateUSA `json:"data"`
+	Meta             *models.ListMetadata     `json:"meta,omitempty"`
 	Err              error                    `json:"error,omitempty"`
 }
 
@@ -86,8 +94,25 @@ type GetTodayTreasuryRateUSAResponse struct {
 
 func (r GetTodayTreasuryRateUSAResponse) error() error { return r.Err }
 
+type GetTreasuryYieldCurveResponse struct {
+	YieldCurve *models.TreasuryYieldCurve `json:"data"`
+	Err        error                      `json:"error,omitempty"`
+}
+
+func (r GetTreasuryYieldCurveResponse) error() error { return r.Err }
+
+type GetTreasuryYieldSeriesResponse struct {
+	Maturity string                   `json:"maturity"`
+	Yields   []models.TreasuryRateUSA `json:"data"`
+	Meta     *models.ListMetadata     `json:"meta,omitempty"`
+	Err      error                    `json:"error,omitempty"`
+}
+
+func (r GetTreasuryYieldSeriesResponse) error() error { return r.Err }
+
 type GetUSAInflationRatesResponse struct {
 	InflationRates []models.USAInflationRate `json:"data"`
+	Meta           *models.ListMetadata      `json:"meta,omitempty"`
 	Err            error                     `json:"error,omitempty"`
 }
 
@@ -99,3 +124,99 @@ type GetTodayUSAInflationRateResponse struct {
 }
 
 func (r GetTodayUSAInflationRateResponse) error() error { return r.Err }
+
+type GetUSAConsumerPriceIndexesResponse struct {
+	ConsumerPriceIndexes []models.USAConsumerPriceIndex `json:"data"`
+	Meta                 *models.ListMetadata           `json:"meta,omitempty"`
+	Err                  error                          `json:"error,omitempty"`
+}
+
+func (r GetUSAConsumerPriceIndexesResponse) error() error { return r.Err }
+
+type GetMonexExchangeRatesResponse struct {
+	MonexExchangeRates []models.MonexExchangeRate `json:"data"`
+	Meta               *models.ListMetadata       `json:"meta,omitempty"`
+	Err                error                      `json:"error,omitempty"`
+}
+
+func (r GetMonexExchangeRatesResponse) error() error { return r.Err }
+
+type GetTodayMonexExchangeRateResponse struct {
+	MonexExchangeRate *models.MonexExchangeRate `json:"data"`
+	Err               error                     `json:"error,omitempty"`
+}
+
+func (r GetTodayMonexExchangeRateResponse) error() error { return r.Err }
+
+type GetEuroExchangeRatesResponse struct {
+	EuroExchangeRates []models.EuroExchangeRate `json:"data"`
+	Meta              *models.ListMetadata      `json:"meta,omitempty"`
+	Err               error                     `json:"error,omitempty"`
+}
+
+func (r GetEuroExchangeRatesResponse) error() error { return r.Err }
+
+type GetTodayEuroExchangeRateResponse struct {
+	EuroExchangeRate *models.EuroExchangeRate `json:"data"`
+	Err              error                    `json:"error,omitempty"`
+}
+
+func (r GetTodayEuroExchangeRateResponse) error() error { return r.Err }
+
+type GetReferenceDepositRatesResponse struct {
+	ReferenceDepositRates []models.ReferenceDepositRate `json:"data"`
+	Meta                  *models.ListMetadata          `json:"meta,omitempty"`
+	Err                   error                         `json:"error,omitempty"`
+}
+
+func (r GetReferenceDepositRatesResponse) error() error { return r.Err }
+
+type GetTodayReferenceDepositRateResponse struct {
+	ReferenceDepositRate *models.ReferenceDepositRate `json:"data"`
+	Err                  error                        `json:"error,omitempty"`
+}
+
+func (r GetTodayReferenceDepositRateResponse) error() error { return r.Err }
+
+type GetInflationCalculatorResponse struct {
+	InflationAdjustment *models.InflationAdjustment `json:"data"`
+	Err                 error                       `json:"error,omitempty"`
+}
+
+func (r GetInflationCalculatorResponse) error() error { return r.Err }
+
+type GetLoanCalculatorResponse struct {
+	Schedule *models.AmortizationSchedule `json:"data"`
+	Err      error                        `json:"error,omitempty"`
+}
+
+func (r GetLoanCalculatorResponse) error() error { return r.Err }
+
+type GetCompareSeriesResponse struct {
+	Comparison *models.SeriesComparison `json:"data"`
+	Err        error                    `json:"error,omitempty"`
+}
+
+func (r GetCompareSeriesResponse) error() error { return r.Err }
+
+type GetSeriesResponse struct {
+	Series       string               `json:"series"`
+	Observations []models.Observation `json:"data"`
+	Err          error                `json:"error,omitempty"`
+}
+
+func (r GetSeriesResponse) error() error { return r.Err }
+
+type GetLatestObservationResponse struct {
+	Observation *models.Observation `json:"data"`
+	Err         error               `json:"error,omitempty"`
+}
+
+func (r GetLatestObservationResponse) error() error { return r.Err }
+
+type GetConvertResponse struct {
+	Conversion *models.Conversion `json:"data"`
+	Err        error              `json:"error,omitempty"`
+}
+
+func (r GetConvertResponse) error() error { return r.Err }
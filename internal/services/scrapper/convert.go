@@ -1,6 +1,9 @@
 package scrapper
 
 import (
+	"fmt"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -101,24 +104,106 @@ func toTreasuryRateUSA(treasuryRateUSAHTML models.TreasuryRateUSAHTML) (models.T
 	return treasuryRateUSA, nil
 }
 
-func toUSAInflationRate(inflationRateHTML models.USAInflationRateHTML) (models.USAInflationRate, error) {
-	dateArray := strings.Split(inflationRateHTML.Date, " ") // 0: Year 1: Month
+func toUSAConsumerPriceIndex(consumerPriceIndexHTML models.USAConsumerPriceIndexHTML) (models.USAConsumerPriceIndex, error) {
+	dateArray := strings.Split(consumerPriceIndexHTML.Date, " ") // 0: Year 1: Month
+	if len(dateArray) != 2 {
+		return models.USAConsumerPriceIndex{}, fmt.Errorf("invalid consumer price index date: %q", consumerPriceIndexHTML.Date)
+	}
 	year, err := strconv.Atoi(dateArray[0])
 	if err != nil {
-		return models.USAInflationRate{}, err
+		return models.USAConsumerPriceIndex{}, err
+	}
+	month, ok := prefixMonthsEnglish[dateArray[1]]
+	if !ok {
+		return models.USAConsumerPriceIndex{}, fmt.Errorf("invalid consumer price index month: %q", dateArray[1])
 	}
-	month := prefixMonthsEnglish[dateArray[1]]
 	date := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
 
 	lastDayOfMonth := date.AddDate(0, 1, -1)
-	valueHTML := strings.ReplaceAll(inflationRateHTML.Value, ",", ".")
+	valueHTML := strings.ReplaceAll(consumerPriceIndexHTML.Value, ",", ".")
 	value, error := strconv.ParseFloat(valueHTML, 64)
 	if error != nil {
-		return models.USAInflationRate{}, error
+		return models.USAConsumerPriceIndex{}, error
 	}
-	inflationRate := models.USAInflationRate{
+	consumerPriceIndex := models.USAConsumerPriceIndex{
 		Value: value,
 		Date:  lastDayOfMonth,
 	}
-	return inflationRate, nil
+	return consumerPriceIndex, nil
+}
+
+var maturityRegexp = regexp.MustCompile(`(?i)(\d+)\s*(d[ií]as?|days?|mes(es)?|months?|a[ñn]os?|years?)`)
+
+// toMaturity normalizes the BCCR column headers (e.g. "3 meses", "10 años") to maturities like 3M or 10Y
+func toMaturity(header string) (string, bool) {
+	match := maturityRegexp.FindStringSubmatch(header)
+	if match == nil {
+		return "", false
+	}
+
+	unit := strings.ToLower(match[2])
+	switch {
+	case strings.HasPrefix(unit, "d"):
+		return match[1] + "D", true
+	case strings.HasPrefix(unit, "m"):
+		return match[1] + "M", true
+	default:
+		return match[1] + "Y", true
+	}
+}
+
+// maturityDays returns the approximate length in days of a maturity like 3M or 10Y
+func maturityDays(maturity string) (int, bool) {
+	if len(maturity) < 2 {
+		return 0, false
+	}
+	amount, err := strconv.Atoi(maturity[:len(maturity)-1])
+	if err != nil || amount <= 0 {
+		return 0, false
+	}
+	switch maturity[len(maturity)-1] {
+	case 'D':
+		return amount, true
+	case 'M':
+		return amount * 30, true
+	case 'Y':
+		return amount * 365, true
+	default:
+		return 0, false
+	}
+}
+
+func toTreasuryYieldCurve(dateHTML string, yieldsHTML []models.TreasuryYieldHTML) (models.TreasuryYieldCurve, error) {
+	treasuryRate, err := toTreasuryRateUSA(models.TreasuryRateUSAHTML{Date: dateHTML, Value: "0"})
+	if err != nil {
+		return models.TreasuryYieldCurve{}, err
+	}
+
+	yields := []models.TreasuryYield{}
+	for _, yieldHTML := range yieldsHTML {
+		valueHTML := strings.ReplaceAll(yieldHTML.Value, ",", ".")
+		value, err := strconv.ParseFloat(valueHTML, 64)
+		if err != nil {
+			return models.TreasuryYieldCurve{}, err
+		}
+		yields = append(yields, models.TreasuryYield{
+			Maturity: yieldHTML.Maturity,
+			Value:    value,
+		})
+	}
+
+	sort.Slice(yields, func(i, j int) bool {
+		daysI, _ := maturityDays(yields[i].Maturity)
+		daysJ, _ := maturityDays(yields[j].Maturity)
+		return daysI < daysJ
+	})
+
+	// the curve is inverted when the shortest maturity pays more than the longest one
+	inverted := len(yields) > 1 && yields[0].Value > yields[len(yields)-1].Value
+
+	return models.TreasuryYieldCurve{
+		Date:     treasuryRate.Date,
+		Yields:   yields,
+		Inverted: inverted,
+	}, nil
 }
@@ -0,0 +1,205 @@
+package scrapper
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// FreshnessObserver receives the date of the latest observation of every scrape
+type FreshnessObserver interface {
+	Observe(indicator string, date time.Time)
+}
+
+// InstrumentingScrapper measures the scrapes of another Scrapper, by indicator
+type InstrumentingScrapper struct {
+	next        Scrapper
+	duration    metrics.Histogram
+	failures    metrics.Counter
+	lastSuccess metrics.Gauge
+	freshness   FreshnessObserver
+}
+
+func NewInstrumentingScrapper(next Scrapper, duration metrics.Histogram, failures metrics.Counter, lastSuccess metrics.Gauge, freshness FreshnessObserver) *InstrumentingScrapper {
+	return &InstrumentingScrapper{
+		next:        next,
+		duration:    duration,
+		failures:    failures,
+		lastSuccess: lastSuccess,
+		freshness:   freshness,
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// latestObservation returns the latest Date of a model or a list of models, zero when there is no data
+func latestObservation(value reflect.Value) time.Time {
+	latest := time.Time{}
+	switch value.Kind() {
+	case reflect.Ptr:
+		if !value.IsNil() {
+			return latestObservation(value.Elem())
+		}
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			if date := latestObservation(value.Index(i)); date.After(latest) {
+				latest = date
+			}
+		}
+	case reflect.Struct:
+		if field := value.FieldByName("Date"); field.IsValid() && field.Type() == timeType {
+			latest = field.Interface().(time.Time)
+		}
+	}
+	return latest
+}
+
+// instrument records a scrape. An empty result is not a failure (there are no rates on weekends), but it
+// does not update the time of the last success either.
+func instrument[T any](ctx context.Context, scrapper *InstrumentingScrapper, indicator string, scrap func(ctx context.Context) (T, error)) (T, error) {
+	ctx, span := tracing.StartSpan(ctx, "scrape "+indicator, attribute.String("indicator", indicator))
+	begin := time.Now()
+	result, err := scrap(ctx)
+	scrapper.duration.With("indicator", indicator).Observe(time.Since(begin).Seconds())
+	tracing.EndSpan(span, err)
+	if err != nil {
+		scrapper.failures.With("indicator", indicator).Add(1)
+		return result, err
+	}
+	if latest := latestObservation(reflect.ValueOf(result)); !latest.IsZero() {
+		scrapper.lastSuccess.With("indicator", indicator).Set(float64(time.Now().Unix()))
+		scrapper.freshness.Observe(indicator, latest)
+	}
+	return result, err
+}
+
+func (scrapper *InstrumentingScrapper) GetDollarColonesChangeByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time, filtro int64) ([]models.ExchangeRate, error) {
+	return instrument(ctx, scrapper, "exchange_rate", func(ctx context.Context) ([]models.ExchangeRate, error) {
+		return scrapper.next.GetDollarColonesChangeByDates(ctx, dateFrom, dateTo, filtro)
+	})
+}
+
+func (scrapper *InstrumentingScrapper) GetExchangeRateByDate(ctx context.Context, date time.Time) (*models.ExchangeRate, error) {
+	return instrument(ctx, scrapper, "exchange_rate", func(ctx context.Context) (*models.ExchangeRate, error) {
+		return scrapper.next.GetExchangeRateByDate(ctx, date)
+	})
+}
+
+func (scrapper *InstrumentingScrapper) GetBasicPassiveRateByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time) ([]models.BasicPassiveRate, error) {
+	return instrument(ctx, scrapper, "basic_passive_rate", func(ctx context.Context) ([]models.BasicPassiveRate, error) {
+		return scrapper.next.GetBasicPassiveRateByDates(ctx, dateFrom, dateTo)
+	})
+}
+
+func (scrapper *InstrumentingScrapper) GetBasicPassiveDateByDate(ctx context.Context, date time.Time) (*models.BasicPassiveRate, error) {
+	return instrument(ctx, scrapper, "basic_passive_rate", func(ctx context.Context) (*models.BasicPassiveRate, error) {
+		return scrapper.next.GetBasicPassiveDateByDate(ctx, date)
+	})
+}
+
+func (scrapper *InstrumentingScrapper) GetMonetaryPolicyRateByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time) ([]models.MonetaryPolicyRate, error) {
+	return instrument(ctx, scrapper, "monetary_policy_rate", func(ctx context.Context) ([]models.MonetaryPolicyRate, error) {
+		return scrapper.next.GetMonetaryPolicyRateByDates(ctx, dateFrom, dateTo)
+	})
+}
+
+func (scrapper *InstrumentingScrapper) GetMonetaryPolicyRateByDate(ctx context.Context, date time.Time) (*models.MonetaryPolicyRate, error) {
+	return instrument(ctx, scrapper, "monetary_policy_rate", func(ctx context.Context) (*models.MonetaryPolicyRate, error) {
+		return scrapper.next.GetMonetaryPolicyRateByDate(ctx, date)
+	})
+}
+
+func (scrapper *InstrumentingScrapper) GetPrimeRateByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time) ([]models.PrimeRate, error) {
+	return instrument(ctx, scrapper, "prime_rate", func(ctx context.Context) ([]models.PrimeRate, error) {
+		return scrapper.next.GetPrimeRateByDates(ctx, dateFrom, dateTo)
+	})
+}
+
+func (scrapper *InstrumentingScrapper) GetPrimeRateByDate(ctx context.Context, date time.Time) (*models.PrimeRate, error) {
+	return instrument(ctx, scrapper, "prime_rate", func(ctx context.Context) (*models.PrimeRate, error) {
+		return scrapper.next.GetPrimeRateByDate(ctx, date)
+	})
+}
+
+func (scrapper *InstrumentingScrapper) GetCostaRicaInflationRateByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time, filter int64) ([]models.CostaRicaInflationRate, error) {
+	return instrument(ctx, scrapper, "inflation_cr", func(ctx context.Context) ([]models.CostaRicaInflationRate, error) {
+		return scrapper.next.GetCostaRicaInflationRateByDates(ctx, dateFrom, dateTo, filter)
+	})
+}
+
+func (scrapper *InstrumentingScrapper) GetCostaRicaInflationRateByDate(ctx context.Context, date time.Time) (*models.CostaRicaInflationRate, error) {
+	return instrument(ctx, scrapper, "inflation_cr", func(ctx context.Context) (*models.CostaRicaInflationRate, error) {
+		return scrapper.next.GetCostaRicaInflationRateByDate(ctx, date)
+	})
+}
+
+func (scrapper *InstrumentingScrapper) GetTreasuryRateUSAByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time) ([]models.TreasuryRateUSA, error) {
+	return instrument(ctx, scrapper, "treasury_rate_usa", func(ctx context.Context) ([]models.TreasuryRateUSA, error) {
+		return scrapper.next.GetTreasuryRateUSAByDates(ctx, dateFrom, dateTo)
+	})
+}
+
+func (scrapper *InstrumentingScrapper) GetTreasuryRateUSAByDate(ctx context.Context, date time.Time) (*models.TreasuryRateUSA, error) {
+	return instrument(ctx, scrapper, "treasury_rate_usa", func(ctx context.Context) (*models.TreasuryRateUSA, error) {
+		return scrapper.next.GetTreasuryRateUSAByDate(ctx, date)
+	})
+}
+
+func (scrapper *InstrumentingScrapper) GetTreasuryYieldCurvesByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time) ([]models.TreasuryYieldCurve, error) {
+	return instrument(ctx, scrapper, "treasury_yield_curve", func(ctx context.Context) ([]models.TreasuryYieldCurve, error) {
+		return scrapper.next.GetTreasuryYieldCurvesByDates(ctx, dateFrom, dateTo)
+	})
+}
+
+func (scrapper *InstrumentingScrapper) GetMonexExchangeRateByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time) ([]models.MonexExchangeRate, error) {
+	return instrument(ctx, scrapper, "monex_exchange_rate", func(ctx context.Context) ([]models.MonexExchangeRate, error) {
+		return scrapper.next.GetMonexExchangeRateByDates(ctx, dateFrom, dateTo)
+	})
+}
+
+func (scrapper *InstrumentingScrapper) GetMonexExchangeRateByDate(ctx context.Context, date time.Time) (*models.MonexExchangeRate, error) {
+	return instrument(ctx, scrapper, "monex_exchange_rate", func(ctx context.Context) (*models.MonexExchangeRate, error) {
+		return scrapper.next.GetMonexExchangeRateByDate(ctx, date)
+	})
+}
+
+func (scrapper *InstrumentingScrapper) GetEuroExchangeRateByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time) ([]models.EuroExchangeRate, error) {
+	return instrument(ctx, scrapper, "euro_exchange_rate", func(ctx context.Context) ([]models.EuroExchangeRate, error) {
+		return scrapper.next.GetEuroExchangeRateByDates(ctx, dateFrom, dateTo)
+	})
+}
+
+func (scrapper *InstrumentingScrapper) GetEuroExchangeRateByDate(ctx context.Context, date time.Time) (*models.EuroExchangeRate, error) {
+	return instrument(ctx, scrapper, "euro_exchange_rate", func(ctx context.Context) (*models.EuroExchangeRate, error) {
+		return scrapper.next.GetEuroExchangeRateByDate(ctx, date)
+	})
+}
+
+func (scrapper *InstrumentingScrapper) GetReferenceDepositRateByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time) ([]models.ReferenceDepositRate, error) {
+	return instrument(ctx, scrapper, "reference_deposit_rate", func(ctx context.Context) ([]models.ReferenceDepositRate, error) {
+		return scrapper.next.GetReferenceDepositRateByDates(ctx, dateFrom, dateTo)
+	})
+}
+
+func (scrapper *InstrumentingScrapper) GetReferenceDepositRateByDate(ctx context.Context, date time.Time) (*models.ReferenceDepositRate, error) {
+	return instrument(ctx, scrapper, "reference_deposit_rate", func(ctx context.Context) (*models.ReferenceDepositRate, error) {
+		return scrapper.next.GetReferenceDepositRateByDate(ctx, date)
+	})
+}
+
+func (scrapper *InstrumentingScrapper) GetUSAConsumerPriceIndexByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time) ([]models.USAConsumerPriceIndex, error) {
+	return instrument(ctx, scrapper, "consumer_price_index_usa", func(ctx context.Context) ([]models.USAConsumerPriceIndex, error) {
+		return scrapper.next.GetUSAConsumerPriceIndexByDates(ctx, dateFrom, dateTo)
+	})
+}
+
+func (scrapper *InstrumentingScrapper) GetUSAConsumerPriceIndexByDate(ctx context.Context, date time.Time) (*models.USAConsumerPriceIndex, error) {
+	return instrument(ctx, scrapper, "consumer_price_index_usa", func(ctx context.Context) (*models.USAConsumerPriceIndex, error) {
+		return scrapper.next.GetUSAConsumerPriceIndexByDate(ctx, date)
+	})
+}
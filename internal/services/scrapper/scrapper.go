@@ -1,7 +1,12 @@
 package scrapper
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,20 +19,27 @@ import (
 )
 
 type Scrapper interface {
-	GetDollarColonesChangeByDates(dateFrom time.Time, dateTo time.Time, filtro int64) ([]models.ExchangeRate, error)
-	GetExchangeRateByDate(date time.Time) (*models.ExchangeRate, error)
-	GetBasicPassiveRateByDates(dateFrom time.Time, dateTo time.Time) ([]models.BasicPassiveRate, error)
-	GetBasicPassiveDateByDate(date time.Time) (*models.BasicPassiveRate, error)
-	GetMonetaryPolicyRateByDates(dateFrom time.Time, dateTo time.Time) ([]models.MonetaryPolicyRate, error)
-	GetMonetaryPolicyRateByDate(date time.Time) (*models.MonetaryPolicyRate, error)
-	GetPrimeRateByDates(dateFrom time.Time, dateTo time.Time) ([]models.PrimeRate, error)
-	GetPrimeRateByDate(date time.Time) (*models.PrimeRate, error)
-	GetCostaRicaInflationRateByDates(dateFrom time.Time, dateTo time.Time, filter int64) ([]models.CostaRicaInflationRate, error)
-	GetCostaRicaInflationRateByDate(date time.Time) (*models.CostaRicaInflationRate, error)
-	GetTreasuryRateUSAByDates(dateFrom time.Time, dateTo time.Time) ([]models.TreasuryRateUSA, error)
-	GetTreasuryRateUSAByDate(date time.Time) (*models.TreasuryRateUSA, error)
-	GetUSAInflationRateByDates(dateFrom time.Time, dateTo time.Time) ([]models.USAInflationRate, error)
-	GetUSAInflationRateByDate(date time.Time) (*models.USAInflationRate, error)
+	GetDollarColonesChangeByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time, filtro int64) ([]models.ExchangeRate, error)
+	GetExchangeRateByDate(ctx context.Context, date time.Time) (*models.ExchangeRate, error)
+	GetBasicPassiveRateByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time) ([]models.BasicPassiveRate, error)
+	GetBasicPassiveDateByDate(ctx context.Context, date time.Time) (*models.BasicPassiveRate, error)
+	GetMonetaryPolicyRateByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time) ([]models.MonetaryPolicyRate, error)
+	GetMonetaryPolicyRateByDate(ctx context.Context, date time.Time) (*models.MonetaryPolicyRate, error)
+	GetPrimeRateByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time) ([]models.PrimeRate, error)
+	GetPrimeRateByDate(ctx context.Context, date time.Time) (*models.PrimeRate, error)
+	GetCostaRicaInflationRateByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time, filter int64) ([]models.CostaRicaInflationRate, error)
+	GetCostaRicaInflationRateByDate(ctx context.Context, date time.Time) (*models.CostaRicaInflationRate, error)
+	GetTreasuryRateUSAByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time) ([]models.TreasuryRateUSA, error)
+	GetTreasuryRateUSAByDate(ctx context.Context, date time.Time) (*models.TreasuryRateUSA, error)
+	GetTreasuryYieldCurvesByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time) ([]models.TreasuryYieldCurve, error)
+	GetMonexExchangeRateByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time) ([]models.MonexExchangeRate, error)
+	GetMonexExchangeRateByDate(ctx context.Context, date time.Time) (*models.MonexExchangeRate, error)
+	GetEuroExchangeRateByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time) ([]models.EuroExchangeRate, error)
+	GetEuroExchangeRateByDate(ctx context.Context, date time.Time) (*models.EuroExchangeRate, error)
+	GetReferenceDepositRateByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time) ([]models.ReferenceDepositRate, error)
+	GetReferenceDepositRateByDate(ctx context.Context, date time.Time) (*models.ReferenceDepositRate, error)
+	GetUSAConsumerPriceIndexByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time) ([]models.USAConsumerPriceIndex, error)
+	GetUSAConsumerPriceIndexByDate(ctx context.Context, date time.Time) (*models.USAConsumerPriceIndex, error)
 }
 
 type BCCRScrapper struct {
@@ -42,6 +54,38 @@ func NewBCCRScrapper(logger log.Logger, urls configuration.ScrapperConfig) *BCCR
 	}
 }
 
+// logScrape ties the BCCR urls to the request that needed them
+func (scrapper *BCCRScrapper) logScrape(ctx context.Context, url string) {
+	_ = level.Debug(scrapper.logger).Log("msg", "scraping", "url", url, "request_id", utils.RequestID(ctx))
+}
+
+// contextTransport sends the requests of a collector with the context of the scrape, so the BCCR pages
+// stop loading when the request that needed them is cancelled
+type contextTransport struct {
+	ctx  context.Context
+	next http.RoundTripper
+}
+
+func (transport contextTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	return transport.next.RoundTrip(r.WithContext(transport.ctx))
+}
+
+// visit loads the url unless the context is done. It only returns the error of the context, the other
+// errors of the visit are logged and the tables are empty then.
+func (scrapper *BCCRScrapper) visit(ctx context.Context, collyCollector *colly.Collector, url string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	collyCollector.WithTransport(contextTransport{ctx: ctx, next: http.DefaultTransport})
+	if err := collyCollector.Visit(url); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		_ = level.Debug(scrapper.logger).Log("msg", "error visiting", "url", url, "error", err, "request_id", utils.RequestID(ctx))
+	}
+	return nil
+}
+
 func (scrapper *BCCRScrapper) getScrappingUrlWithFilter(url string, dateFrom time.Time, dateTo time.Time, filter int64) string {
 	return fmt.Sprintf(url, dateFrom.Format(utils.DATE_FORMAT), dateTo.Format(utils.DATE_FORMAT), fmt.Sprintf("%d", filter))
 }
@@ -49,9 +93,9 @@ func (scrapper *BCCRScrapper) getScrappingUrl(url string, dateFrom time.Time, da
 	return fmt.Sprintf(url, dateFrom.Format(utils.DATE_FORMAT), dateTo.Format(utils.DATE_FORMAT))
 }
 
-func (scrapper *BCCRScrapper) GetDollarColonesChangeByDates(dateFrom time.Time, dateTo time.Time, filtro int64) ([]models.ExchangeRate, error) {
+func (scrapper *BCCRScrapper) GetDollarColonesChangeByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time, filtro int64) ([]models.ExchangeRate, error) {
 	url := scrapper.getScrappingUrlWithFilter(scrapper.urls.ExchangeRateUrl, dateFrom, dateTo, filtro)
-	fmt.Println(url)
+	scrapper.logScrape(ctx, url)
 	collyCollector := colly.NewCollector()
 
 	exchangesRates := []models.ExchangeRate{}
@@ -85,14 +129,16 @@ func (scrapper *BCCRScrapper) GetDollarColonesChangeByDates(dateFrom time.Time,
 		}
 	})
 
-	collyCollector.Visit(url)
+	if err := scrapper.visit(ctx, collyCollector, url); err != nil {
+		return nil, err
+	}
 
 	return exchangesRates, nil
 }
 
-func (scrapper *BCCRScrapper) GetExchangeRateByDate(date time.Time) (*models.ExchangeRate, error) {
+func (scrapper *BCCRScrapper) GetExchangeRateByDate(ctx context.Context, date time.Time) (*models.ExchangeRate, error) {
 	url := scrapper.getScrappingUrlWithFilter(scrapper.urls.ExchangeRateUrl, date, date, 0)
-	fmt.Println(url)
+	scrapper.logScrape(ctx, url)
 	collyCollector := colly.NewCollector()
 
 	todayExchangeRate := models.ExchangeRate{}
@@ -122,14 +168,16 @@ func (scrapper *BCCRScrapper) GetExchangeRateByDate(date time.Time) (*models.Exc
 		todayExchangeRate = toExchangeRate
 	})
 
-	collyCollector.Visit(url)
+	if err := scrapper.visit(ctx, collyCollector, url); err != nil {
+		return nil, err
+	}
 
 	return &todayExchangeRate, nil
 }
 
-func (scrapper *BCCRScrapper) GetBasicPassiveRateByDates(dateFrom time.Time, dateTo time.Time) ([]models.BasicPassiveRate, error) {
+func (scrapper *BCCRScrapper) GetBasicPassiveRateByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time) ([]models.BasicPassiveRate, error) {
 	url := scrapper.getScrappingUrl(scrapper.urls.BasicPassiveRateUrl, dateFrom, dateTo)
-	fmt.Println(url)
+	scrapper.logScrape(ctx, url)
 	yearFrom := dateFrom.Year()
 	yearTo := dateTo.Year()
 
@@ -177,14 +225,16 @@ func (scrapper *BCCRScrapper) GetBasicPassiveRateByDates(dateFrom time.Time, dat
 		}
 	})
 
-	collyCollector.Visit(url)
+	if err := scrapper.visit(ctx, collyCollector, url); err != nil {
+		return nil, err
+	}
 
 	return basicPassiveRates, nil
 }
 
-func (scrapper *BCCRScrapper) GetBasicPassiveDateByDate(date time.Time) (*models.BasicPassiveRate, error) {
+func (scrapper *BCCRScrapper) GetBasicPassiveDateByDate(ctx context.Context, date time.Time) (*models.BasicPassiveRate, error) {
 	url := scrapper.getScrappingUrl(scrapper.urls.BasicPassiveRateUrl, date, date)
-	fmt.Println(url)
+	scrapper.logScrape(ctx, url)
 	collyCollector := colly.NewCollector()
 
 	basicPassiveRate := models.BasicPassiveRate{}
@@ -213,15 +263,16 @@ func (scrapper *BCCRScrapper) GetBasicPassiveDateByDate(date time.Time) (*models
 		basicPassiveRate.Date = date
 	})
 
-	collyCollector.Visit(url)
+	if err := scrapper.visit(ctx, collyCollector, url); err != nil {
+		return nil, err
+	}
 
 	return &basicPassiveRate, nil
 }
 
-func (scrapper *BCCRScrapper) GetMonetaryPolicyRateByDates(dateFrom time.Time, dateTo time.Time) ([]models.MonetaryPolicyRate, error) {
+func (scrapper *BCCRScrapper) GetMonetaryPolicyRateByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time) ([]models.MonetaryPolicyRate, error) {
 	url := scrapper.getScrappingUrl(scrapper.urls.MonetaryPolicyRateUrl, dateFrom, dateTo)
-	fmt.Println(url)
-	_ = level.Debug(scrapper.logger).Log("message", "url", url)
+	scrapper.logScrape(ctx, url)
 	yearFrom := dateFrom.Year()
 	yearTo := dateTo.Year()
 
@@ -273,14 +324,16 @@ func (scrapper *BCCRScrapper) GetMonetaryPolicyRateByDates(dateFrom time.Time, d
 		}
 	})
 
-	collyCollector.Visit(url)
+	if err := scrapper.visit(ctx, collyCollector, url); err != nil {
+		return nil, err
+	}
 
 	return monetaryPolicyRates, nil
 }
 
-func (scrapper *BCCRScrapper) GetMonetaryPolicyRateByDate(date time.Time) (*models.MonetaryPolicyRate, error) {
+func (scrapper *BCCRScrapper) GetMonetaryPolicyRateByDate(ctx context.Context, date time.Time) (*models.MonetaryPolicyRate, error) {
 	url := scrapper.getScrappingUrl(scrapper.urls.MonetaryPolicyRateUrl, date, date)
-	fmt.Println(url)
+	scrapper.logScrape(ctx, url)
 	collyCollector := colly.NewCollector()
 
 	monetaryPolicyRate := models.MonetaryPolicyRate{}
@@ -309,14 +362,16 @@ func (scrapper *BCCRScrapper) GetMonetaryPolicyRateByDate(date time.Time) (*mode
 		monetaryPolicyRate.Date = date
 	})
 
-	collyCollector.Visit(url)
+	if err := scrapper.visit(ctx, collyCollector, url); err != nil {
+		return nil, err
+	}
 
 	return &monetaryPolicyRate, nil
 }
 
-func (scrapper *BCCRScrapper) GetPrimeRateByDates(dateFrom time.Time, dateTo time.Time) ([]models.PrimeRate, error) {
+func (scrapper *BCCRScrapper) GetPrimeRateByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time) ([]models.PrimeRate, error) {
 	url := scrapper.getScrappingUrl(scrapper.urls.PrimeRateUrl, dateFrom, dateTo)
-	fmt.Println(url)
+	scrapper.logScrape(ctx, url)
 	yearFrom := dateFrom.Year()
 	yearTo := dateTo.Year()
 
@@ -363,14 +418,16 @@ func (scrapper *BCCRScrapper) GetPrimeRateByDates(dateFrom time.Time, dateTo tim
 		}
 	})
 
-	collyCollector.Visit(url)
+	if err := scrapper.visit(ctx, collyCollector, url); err != nil {
+		return nil, err
+	}
 
 	return primeRates, nil
 }
 
-func (scrapper *BCCRScrapper) GetPrimeRateByDate(date time.Time) (*models.PrimeRate, error) {
+func (scrapper *BCCRScrapper) GetPrimeRateByDate(ctx context.Context, date time.Time) (*models.PrimeRate, error) {
 	url := scrapper.getScrappingUrl(scrapper.urls.PrimeRateUrl, date, date)
-	fmt.Println(url)
+	scrapper.logScrape(ctx, url)
 	collyCollector := colly.NewCollector()
 
 	primeRate := models.PrimeRate{}
@@ -398,14 +455,16 @@ func (scrapper *BCCRScrapper) GetPrimeRateByDate(date time.Time) (*models.PrimeR
 		primeRate.Date = date
 	})
 
-	collyCollector.Visit(url)
+	if err := scrapper.visit(ctx, collyCollector, url); err != nil {
+		return nil, err
+	}
 
 	return &primeRate, nil
 }
 
-func (scrapper *BCCRScrapper) GetCostaRicaInflationRateByDates(dateFrom time.Time, dateTo time.Time, filter int64) ([]models.CostaRicaInflationRate, error) {
+func (scrapper *BCCRScrapper) GetCostaRicaInflationRateByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time, filter int64) ([]models.CostaRicaInflationRate, error) {
 	url := scrapper.getScrappingUrlWithFilter(scrapper.urls.InflationCostaRicaUrl, dateFrom, dateTo, filter)
-	fmt.Println(url)
+	scrapper.logScrape(ctx, url)
 	collyCollector := colly.NewCollector()
 
 	inflationRates := []models.CostaRicaInflationRate{}
@@ -436,16 +495,18 @@ func (scrapper *BCCRScrapper) GetCostaRicaInflationRateByDates(dateFrom time.Tim
 		}
 	})
 
-	collyCollector.Visit(url)
+	if err := scrapper.visit(ctx, collyCollector, url); err != nil {
+		return nil, err
+	}
 
 	return inflationRates, nil
 }
 
-func (scrapper *BCCRScrapper) GetCostaRicaInflationRateByDate(date time.Time) (*models.CostaRicaInflationRate, error) {
+func (scrapper *BCCRScrapper) GetCostaRicaInflationRateByDate(ctx context.Context, date time.Time) (*models.CostaRicaInflationRate, error) {
 	dateFrom := time.Date(date.Year(), date.Month()-1, 1, 0, 0, 0, 0, time.UTC)
 	dateTo := time.Date(date.Year(), date.Month()-1, 31, 0, 0, 0, 0, time.UTC)
 	url := scrapper.getScrappingUrlWithFilter(scrapper.urls.InflationCostaRicaUrl, dateFrom, dateTo, 0)
-	fmt.Println(url)
+	scrapper.logScrape(ctx, url)
 	collyCollector := colly.NewCollector()
 
 	inflationRate := models.CostaRicaInflationRate{}
@@ -474,128 +535,126 @@ func (scrapper *BCCRScrapper) GetCostaRicaInflationRateByDate(date time.Time) (*
 
 	})
 
-	collyCollector.Visit(url)
+	if err := scrapper.visit(ctx, collyCollector, url); err != nil {
+		return nil, err
+	}
 
 	return &inflationRate, nil
 }
 
-func (scrapper *BCCRScrapper) GetUSAInflationRateByDates(dateFrom time.Time, dateTo time.Time) ([]models.USAInflationRate, error) {
-	url := scrapper.urls.InflationUSAUrl
-	collyCollector := colly.NewCollector()
+// getUSAConsumerPriceIndexUrl asks the BLS data viewer only for the years of the range
+func (scrapper *BCCRScrapper) getUSAConsumerPriceIndexUrl(dateFrom time.Time, dateTo time.Time) string {
+	consumerPriceIndexUrl, err := url.Parse(scrapper.urls.InflationUSAUrl)
+	if err != nil {
+		return scrapper.urls.InflationUSAUrl
+	}
+	query := consumerPriceIndexUrl.Query()
+	query.Set("years_option", "specific_years")
+	query.Set("from_year", strconv.Itoa(dateFrom.Year()))
+	query.Set("to_year", strconv.Itoa(dateTo.Year()))
+	consumerPriceIndexUrl.RawQuery = query.Encode()
+	return consumerPriceIndexUrl.String()
+}
 
-	inflationRates := []models.USAInflationRate{}
+// parseUSAConsumerPriceIndexTable reads the BLS table, one row per year with the twelve months and the two
+// half year averages as columns
+func parseUSAConsumerPriceIndexTable(h *colly.HTMLElement) []models.USAConsumerPriceIndexHTML {
+	headersHTML := h.ChildText("#table0 > thead > tr")
+	yearsHTML := h.ChildTexts("#table0 > tbody > tr")
+	valueHTML := h.ChildTexts("#table0 > tbody > tr > td")
 
-	collyCollector.OnHTML("#table0", func(h *colly.HTMLElement) {
-		headersHTML := h.ChildText("#table0 > thead > tr")
-		yearsHTML := h.ChildTexts("#table0 > tbody > tr")
-		valueHTML := h.ChildTexts("#table0 > tbody > tr > td")
+	months := strings.Split(headersHTML, "  ")
 
-		months := strings.Split(headersHTML, "  ")
-
-		years := []string{}
-		for _, year := range yearsHTML {
-			years = append(years, year[:4])
+	years := []string{}
+	for _, year := range yearsHTML {
+		if len(year) < 4 {
+			continue
 		}
+		years = append(years, year[:4])
+	}
 
-		indexYear := 0
-		indexMonth := 0
-
-		inflationRatesHTML := []models.USAInflationRateHTML{}
-		for index, value := range valueHTML {
-			if index%14 == 0 && index != 0 {
-				indexYear++
-			}
-			if index%14 == 0 && index != 0 {
-				indexMonth = 1
-			} else {
-				indexMonth++
-			}
+	indexYear := 0
+	indexMonth := 0
 
-			if value != "" && months[indexMonth] != "" && years[indexYear] != "" && months[indexMonth] != "HALF1" && months[indexMonth] != "HALF2" {
-				inflationRateHTML := models.USAInflationRateHTML{
-					Value: value,
-					Date:  years[indexYear] + " " + months[indexMonth],
-				}
+	consumerPriceIndexesHTML := []models.USAConsumerPriceIndexHTML{}
+	for index, value := range valueHTML {
+		if index%14 == 0 && index != 0 {
+			indexYear++
+			indexMonth = 1
+		} else {
+			indexMonth++
+		}
 
-				inflationRatesHTML = append(inflationRatesHTML, inflationRateHTML)
-			}
+		if indexYear >= len(years) || indexMonth >= len(months) {
+			break
 		}
 
-		for _, inflationRateHTML := range inflationRatesHTML {
-			inflationRate, err := toUSAInflationRate(inflationRateHTML)
-			if err != nil {
-				_ = level.Debug(scrapper.logger).Log("msg", "error converting from USAInflationRateHTML to USAInflationRate models", "error", err)
-				return
+		if value != "" && months[indexMonth] != "" && years[indexYear] != "" && months[indexMonth] != "HALF1" && months[indexMonth] != "HALF2" {
+			consumerPriceIndexHTML := models.USAConsumerPriceIndexHTML{
+				Value: value,
+				Date:  years[indexYear] + " " + months[indexMonth],
 			}
 
-			inflationRates = append(inflationRates, inflationRate)
+			consumerPriceIndexesHTML = append(consumerPriceIndexesHTML, consumerPriceIndexHTML)
 		}
-	})
-
-	collyCollector.Visit(url)
+	}
 
-	return inflationRates, nil
+	return consumerPriceIndexesHTML
 }
 
-func (scrapper *BCCRScrapper) GetUSAInflationRateByDate(date time.Time) (*models.USAInflationRate, error) {
-	url := scrapper.urls.InflationUSAUrl
+func (scrapper *BCCRScrapper) GetUSAConsumerPriceIndexByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time) ([]models.USAConsumerPriceIndex, error) {
+	url := scrapper.getUSAConsumerPriceIndexUrl(dateFrom, dateTo)
+	scrapper.logScrape(ctx, url)
 	collyCollector := colly.NewCollector()
 
-	inflationRate := models.USAInflationRate{}
+	consumerPriceIndexes := []models.USAConsumerPriceIndex{}
 
 	collyCollector.OnHTML("#table0", func(h *colly.HTMLElement) {
-		headersHTML := h.ChildText("#table0 > thead > tr")
-		yearsHTML := h.ChildTexts("#table0 > tbody > tr")
-		valueHTML := h.ChildTexts("#table0 > tbody > tr > td")
-
-		months := strings.Split(headersHTML, "  ")
+		for _, consumerPriceIndexHTML := range parseUSAConsumerPriceIndexTable(h) {
+			consumerPriceIndex, err := toUSAConsumerPriceIndex(consumerPriceIndexHTML)
+			if err != nil {
+				_ = level.Debug(scrapper.logger).Log("msg", "error converting from USAConsumerPriceIndexHTML to USAConsumerPriceIndex models", "error", err)
+				return
+			}
 
-		years := []string{}
-		for _, year := range yearsHTML {
-			years = append(years, year[:4])
+			if consumerPriceIndex.Date.Before(dateFrom) || consumerPriceIndex.Date.After(dateTo) {
+				continue
+			}
+			consumerPriceIndexes = append(consumerPriceIndexes, consumerPriceIndex)
 		}
+	})
 
-		indexYear := 0
-		indexMonth := 0
+	if err := scrapper.visit(ctx, collyCollector, url); err != nil {
+		return nil, err
+	}
 
-		inflationRatesHTML := []models.USAInflationRateHTML{}
-		for index, value := range valueHTML {
-			if index%14 == 0 && index != 0 {
-				indexYear++
-			}
-			if index%14 == 0 && index != 0 {
-				indexMonth = 1
-			} else {
-				indexMonth++
-			}
+	return consumerPriceIndexes, nil
+}
 
-			if value != "" && months[indexMonth] != "" && years[indexYear] != "" && months[indexMonth] != "HALF1" && months[indexMonth] != "HALF2" {
-				inflationRateHTML := models.USAInflationRateHTML{
-					Value: value,
-					Date:  years[indexYear] + " " + months[indexMonth],
-				}
+func (scrapper *BCCRScrapper) GetUSAConsumerPriceIndexByDate(ctx context.Context, date time.Time) (*models.USAConsumerPriceIndex, error) {
+	consumerPriceIndexes, err := scrapper.GetUSAConsumerPriceIndexByDates(ctx, date.AddDate(-1, 0, 0), date)
+	if err != nil {
+		return nil, err
+	}
 
-				inflationRatesHTML = append(inflationRatesHTML, inflationRateHTML)
-			}
-		}
+	if len(consumerPriceIndexes) == 0 {
+		_ = level.Debug(scrapper.logger).Log("msg", "no consumer price index published", "date", date)
+		return nil, utils.ErrNotFound
+	}
 
-		lastInflationRateHTML := inflationRatesHTML[len(inflationRatesHTML)-1]
-		lastInflationRate, err := toUSAInflationRate(lastInflationRateHTML)
-		if err != nil {
-			_ = level.Debug(scrapper.logger).Log("msg", "error converting from USAInflationRateHTML to USAInflationRate models", "error", err)
-			return
+	latest := consumerPriceIndexes[0]
+	for _, consumerPriceIndex := range consumerPriceIndexes {
+		if consumerPriceIndex.Date.After(latest.Date) {
+			latest = consumerPriceIndex
 		}
-		inflationRate = lastInflationRate
-	})
-
-	collyCollector.Visit(url)
+	}
 
-	return &inflationRate, nil
+	return &latest, nil
 }
 
-func (scrapper *BCCRScrapper) GetTreasuryRateUSAByDates(dateFrom time.Time, dateTo time.Time) ([]models.TreasuryRateUSA, error) {
+func (scrapper *BCCRScrapper) GetTreasuryRateUSAByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time) ([]models.TreasuryRateUSA, error) {
 	url := scrapper.getScrappingUrl(scrapper.urls.TreasuryRateUSAUrl, dateFrom, dateTo)
-	fmt.Println(url)
+	scrapper.logScrape(ctx, url)
 	collyCollector := colly.NewCollector()
 
 	treasuryRates := []models.TreasuryRateUSA{}
@@ -626,14 +685,16 @@ func (scrapper *BCCRScrapper) GetTreasuryRateUSAByDates(dateFrom time.Time, date
 		}
 	})
 
-	collyCollector.Visit(url)
+	if err := scrapper.visit(ctx, collyCollector, url); err != nil {
+		return nil, err
+	}
 
 	return treasuryRates, nil
 }
 
-func (scrapper *BCCRScrapper) GetTreasuryRateUSAByDate(date time.Time) (*models.TreasuryRateUSA, error) {
+func (scrapper *BCCRScrapper) GetTreasuryRateUSAByDate(ctx context.Context, date time.Time) (*models.TreasuryRateUSA, error) {
 	url := scrapper.getScrappingUrl(scrapper.urls.TreasuryRateUSAUrl, date, date)
-	fmt.Println(url)
+	scrapper.logScrape(ctx, url)
 	collyCollector := colly.NewCollector()
 
 	treasuryRate := models.TreasuryRateUSA{}
@@ -662,7 +723,84 @@ func (scrapper *BCCRScrapper) GetTreasuryRateUSAByDate(date time.Time) (*models.
 		treasuryRate.Date = date
 	})
 
-	collyCollector.Visit(url)
+	if err := scrapper.visit(ctx, collyCollector, url); err != nil {
+		return nil, err
+	}
 
 	return &treasuryRate, nil
 }
+
+func (scrapper *BCCRScrapper) GetTreasuryYieldCurvesByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time) ([]models.TreasuryYieldCurve, error) {
+	if scrapper.urls.TreasuryYieldCurveUrl == "" {
+		return nil, errors.New("TREASURY_YIELD_CURVE_URL is not configured")
+	}
+	url := scrapper.getScrappingUrl(scrapper.urls.TreasuryYieldCurveUrl, dateFrom, dateTo)
+	scrapper.logScrape(ctx, url)
+	// the table has the id of the indicator of the url, every curve has its own
+	tableId := "#theTable" + getTableId(url)
+	collyCollector := colly.NewCollector()
+
+	yieldCurves := []models.TreasuryYieldCurve{}
+	// a table without every maturity is not the one of the curves, an empty curve would look like no data
+	var tableErr error
+	found := false
+
+	collyCollector.OnHTML(tableId+" > tbody", func(h *colly.HTMLElement) {
+		found = true
+		dates := h.ChildTexts(tableId + " > tbody > tr:nth-child(2) > td:nth-child(1) > table > tbody > tr > td")
+		headers := h.ChildTexts(tableId + " > tbody > tr:nth-child(1) > td")
+		if len(headers) < 2 {
+			tableErr = fmt.Errorf("the treasury yield curve table of %s has no maturity columns", url)
+			return
+		}
+
+		// every column after the dates has the yields of one maturity
+		yieldsByDate := make([][]models.TreasuryYieldHTML, len(dates))
+		h.ForEach(tableId+" > tbody > tr:nth-child(2) > td", func(column int, e *colly.HTMLElement) {
+			if column == 0 || column >= len(headers) || tableErr != nil {
+				return
+			}
+			maturity, ok := toMaturity(headers[column])
+			if !ok {
+				tableErr = fmt.Errorf("the treasury yield curve table of %s has the column %q instead of a maturity", url, headers[column])
+				return
+			}
+			values := e.ChildTexts("table > tbody > tr > td > table > tbody > tr > td > table > tbody > tr > td")
+			for index, value := range values {
+				if index >= len(dates) || value == "" {
+					continue
+				}
+				yieldsByDate[index] = append(yieldsByDate[index], models.TreasuryYieldHTML{
+					Maturity: maturity,
+					Value:    value,
+				})
+			}
+		})
+		if tableErr != nil {
+			return
+		}
+
+		for index, dateHTML := range dates {
+			if dateHTML == "" || len(yieldsByDate[index]) == 0 {
+				continue
+			}
+			yieldCurve, err := toTreasuryYieldCurve(dateHTML, yieldsByDate[index])
+			if err != nil {
+				_ = level.Error(scrapper.logger).Log("msg", "error converting from TreasuryYieldHTML to TreasuryYieldCurve models", "error", err, "date", dateHTML)
+				continue
+			}
+			yieldCurves = append(yieldCurves, yieldCurve)
+		}
+	})
+
+	if err := scrapper.visit(ctx, collyCollector, url); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("the treasury yield curve table was not found in %s", url)
+	}
+	if tableErr != nil {
+		return nil, tableErr
+	}
+	return yieldCurves, nil
+}
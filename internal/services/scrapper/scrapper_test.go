@@ -0,0 +1,114 @@
+package scrapper
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/configuration"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+)
+
+// newFixtureScrapper reads the pages of testdata, the url keeps the query of the BCCR tables
+func newFixtureScrapper(t *testing.T, page string, tableId string) *BCCRScrapper {
+	t.Helper()
+	server := httptest.NewServer(http.FileServer(http.Dir("testdata")))
+	t.Cleanup(server.Close)
+	return NewBCCRScrapper(log.NewNopLogger(), configuration.ScrapperConfig{
+		TreasuryYieldCurveUrl: server.URL + "/" + page + "?Idioma=1&FecInicial=%s&FecFinal=%s&CodCuadro=" + tableId,
+	})
+}
+
+func TestTreasuryYieldCurvesOfEveryMaturity(t *testing.T) {
+	scrapper := newFixtureScrapper(t, "yield_curve.html", "703")
+	dateFrom := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	curves, err := scrapper.GetTreasuryYieldCurvesByDates(context.Background(), dateFrom, dateFrom.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []models.TreasuryYieldCurve{
+		{
+			Date:     time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			Yields:   []models.TreasuryYield{{Maturity: "1M", Value: 5.53}, {Maturity: "3M", Value: 5.40}, {Maturity: "10Y", Value: 3.95}},
+			Inverted: true,
+		},
+		{
+			Date:     time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+			Yields:   []models.TreasuryYield{{Maturity: "1M", Value: 5.54}, {Maturity: "10Y", Value: 3.91}},
+			Inverted: true,
+		},
+	}
+	if len(curves) != len(expected) {
+		t.Fatalf("%d curves, expected %d: %+v", len(curves), len(expected), curves)
+	}
+	for i, curve := range curves {
+		if !curve.Date.Equal(expected[i].Date) || curve.Inverted != expected[i].Inverted || len(curve.Yields) != len(expected[i].Yields) {
+			t.Errorf("curve %d is %+v, expected %+v", i, curve, expected[i])
+			continue
+		}
+		for j, yield := range curve.Yields {
+			if yield != expected[i].Yields[j] {
+				t.Errorf("yield %d of %s is %+v, expected %+v", j, curve.Date.Format("2006-01-02"), yield, expected[i].Yields[j])
+			}
+		}
+	}
+}
+
+func TestTreasuryYieldCurvesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		page    string
+		tableId string
+		err     string
+	}{
+		{"the table of another indicator", "yield_curve.html", "677", "was not found"},
+		{"a table without maturities", "yield_curve_without_maturities.html", "703", "has no maturity columns"},
+		{"the table of a single rate", "yield_curve_single_rate.html", "703", "instead of a maturity"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scrapper := newFixtureScrapper(t, test.page, test.tableId)
+			dateFrom := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+			curves, err := scrapper.GetTreasuryYieldCurvesByDates(context.Background(), dateFrom, dateFrom.AddDate(0, 0, 7))
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("got %+v and %v, expected an error that %s", curves, err, test.err)
+			}
+		})
+	}
+}
+
+func TestTreasuryYieldCurvesWithoutUrl(t *testing.T) {
+	scrapper := NewBCCRScrapper(log.NewNopLogger(), configuration.ScrapperConfig{})
+	if _, err := scrapper.GetTreasuryYieldCurvesByDates(context.Background(), time.Now(), time.Now()); err == nil {
+		t.Error("the curves were scraped without TREASURY_YIELD_CURVE_URL")
+	}
+}
+
+func TestScrapesStopWhenTheRequestIsCancelled(t *testing.T) {
+	visits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		visits++
+	}))
+	defer server.Close()
+	url := server.URL + "/?Idioma=1&FecInicial=%s&FecFinal=%s&CodCuadro=400"
+	scrapper := NewBCCRScrapper(log.NewNopLogger(), configuration.ScrapperConfig{ExchangeRateUrl: url, MonexExchangeRateUrl: url})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := scrapper.GetExchangeRateByDate(ctx, time.Now()); !errors.Is(err, context.Canceled) {
+		t.Errorf("the exchange rate answered %v", err)
+	}
+	if _, err := scrapper.GetMonexExchangeRateByDates(ctx, time.Now(), time.Now()); !errors.Is(err, context.Canceled) {
+		t.Errorf("the monex exchange rates answered %v", err)
+	}
+	if visits != 0 {
+		t.Errorf("the BCCR was visited %d times", visits)
+	}
+}
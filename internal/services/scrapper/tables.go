@@ -0,0 +1,222 @@
+package scrapper
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/gocolly/colly/v2"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+)
+
+// getTableId returns the BCCR table code of a frmVerCatCuadro url, the html table ids are built from it
+func getTableId(scrappingUrl string) string {
+	parsedUrl, err := url.Parse(scrappingUrl)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(parsedUrl.Query().Get("CodCuadro"))
+}
+
+// toDatedValue converts the "07 Ago 2023" dates and "544,82" values of the BCCR tables
+func toDatedValue(datedValueHTML models.DatedValueHTML) (time.Time, float64, error) {
+	dateArray := strings.Fields(datedValueHTML.Date) // 0: Day, 1: Month, 2: Year
+	if len(dateArray) != 3 {
+		return time.Time{}, 0, fmt.Errorf("invalid date: %q", datedValueHTML.Date)
+	}
+	day, err := strconv.Atoi(dateArray[0])
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	month, ok := prefixMonths[dateArray[1]]
+	if !ok {
+		return time.Time{}, 0, fmt.Errorf("invalid month: %q", dateArray[1])
+	}
+	year, err := strconv.Atoi(dateArray[2])
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+
+	valueHTML := strings.ReplaceAll(datedValueHTML.Value, ",", ".")
+	value, err := strconv.ParseFloat(valueHTML, 64)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+
+	return time.Date(year, time.Month(month), day, 12, 0, 0, 0, time.UTC), value, nil
+}
+
+// scrapDailyTable reads the tables with one row per day, like the exchange rates one (#theTable400),
+// where the first column has the dates and the next ones the values of each indicator
+func (scrapper *BCCRScrapper) scrapDailyTable(ctx context.Context, url string, valueColumn int) ([]models.DatedValueHTML, error) {
+	scrapper.logScrape(ctx, url)
+	tableId := "#theTable" + getTableId(url)
+	collyCollector := colly.NewCollector()
+
+	datedValuesHTML := []models.DatedValueHTML{}
+
+	collyCollector.OnHTML(tableId, func(h *colly.HTMLElement) {
+		dates := h.ChildTexts(tableId + " > tbody > tr:nth-child(2) > td:nth-child(1) > table > tbody > tr > td")
+		values := h.ChildTexts(fmt.Sprintf("%s > tbody > tr:nth-child(2) > td:nth-child(%d) > table > tbody > tr > td > table > tbody > tr > td", tableId, valueColumn))
+
+		for index, value := range values {
+			if index >= len(dates) {
+				break
+			}
+			if value != "" && dates[index] != "" {
+				datedValuesHTML = append(datedValuesHTML, models.DatedValueHTML{
+					Date:  dates[index],
+					Value: value,
+				})
+			}
+		}
+	})
+
+	if err := scrapper.visit(ctx, collyCollector, url); err != nil {
+		return nil, err
+	}
+	return datedValuesHTML, nil
+}
+
+// scrapYearsTable reads the tables with one column per year and one row per day of the year, like the
+// basic passive rates one (#Table17)
+func (scrapper *BCCRScrapper) scrapYearsTable(ctx context.Context, url string, dateFrom time.Time, dateTo time.Time) ([]models.DatedValueHTML, error) {
+	scrapper.logScrape(ctx, url)
+	tableId := "#Table" + getTableId(url)
+	yearDifference := (dateTo.Year() - dateFrom.Year()) + 2
+
+	collyCollector := colly.NewCollector()
+
+	datedValuesHTML := []models.DatedValueHTML{}
+
+	collyCollector.OnHTML(tableId+" > tbody", func(h *colly.HTMLElement) {
+		column := h.ChildTexts(tableId + " > tbody > tr > td > span > table > tbody > tr > td")
+		if len(column) < yearDifference {
+			_ = level.Debug(scrapper.logger).Log("msg", "unexpected table size", "url", url, "cells", len(column))
+			return
+		}
+
+		yearsHeader := column[1:yearDifference]
+
+		for i := yearDifference; i+len(yearsHeader)+1 <= len(column); i += (len(yearsHeader) + 1) {
+			row := column[i : i+len(yearsHeader)+1]
+			values := row[1:] // <-- Get rates without first element (the date)
+			for j := 0; j < len(values); j++ {
+				if values[j] != "" && row[0] != "" && len(yearsHeader[j]) >= 4 {
+					datedValuesHTML = append(datedValuesHTML, models.DatedValueHTML{
+						Date:  row[0] + " " + yearsHeader[j][:4],
+						Value: values[j],
+					})
+				}
+			}
+		}
+	})
+
+	if err := scrapper.visit(ctx, collyCollector, url); err != nil {
+		return nil, err
+	}
+	return datedValuesHTML, nil
+}
+
+func (scrapper *BCCRScrapper) toDatedValues(datedValuesHTML []models.DatedValueHTML) ([]time.Time, []float64) {
+	dates := []time.Time{}
+	values := []float64{}
+	for _, datedValueHTML := range datedValuesHTML {
+		date, value, err := toDatedValue(datedValueHTML)
+		if err != nil {
+			_ = level.Debug(scrapper.logger).Log("msg", "error converting from DatedValueHTML", "result", datedValueHTML, "error", err)
+			continue
+		}
+		dates = append(dates, date)
+		values = append(values, value)
+	}
+	return dates, values
+}
+
+func (scrapper *BCCRScrapper) GetMonexExchangeRateByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time) ([]models.MonexExchangeRate, error) {
+	url := scrapper.getScrappingUrl(scrapper.urls.MonexExchangeRateUrl, dateFrom, dateTo)
+	datedValuesHTML, err := scrapper.scrapDailyTable(ctx, url, 2)
+	if err != nil {
+		return nil, err
+	}
+	dates, values := scrapper.toDatedValues(datedValuesHTML)
+
+	monexExchangeRates := []models.MonexExchangeRate{}
+	for index, date := range dates {
+		monexExchangeRates = append(monexExchangeRates, models.MonexExchangeRate{
+			Value: values[index],
+			Date:  date,
+		})
+	}
+
+	return monexExchangeRates, nil
+}
+
+func (scrapper *BCCRScrapper) GetMonexExchangeRateByDate(ctx context.Context, date time.Time) (*models.MonexExchangeRate, error) {
+	monexExchangeRates, err := scrapper.GetMonexExchangeRateByDates(ctx, date, date)
+	if err != nil || len(monexExchangeRates) == 0 {
+		_ = level.Debug(scrapper.logger).Log("msg", "error getting MONEX exchange rate from html", "date", date)
+		return &models.MonexExchangeRate{}, err
+	}
+	return &monexExchangeRates[0], nil
+}
+
+func (scrapper *BCCRScrapper) GetEuroExchangeRateByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time) ([]models.EuroExchangeRate, error) {
+	url := scrapper.getScrappingUrl(scrapper.urls.EuroExchangeRateUrl, dateFrom, dateTo)
+	datedValuesHTML, err := scrapper.scrapDailyTable(ctx, url, 2)
+	if err != nil {
+		return nil, err
+	}
+	dates, values := scrapper.toDatedValues(datedValuesHTML)
+
+	euroExchangeRates := []models.EuroExchangeRate{}
+	for index, date := range dates {
+		euroExchangeRates = append(euroExchangeRates, models.EuroExchangeRate{
+			Value: values[index],
+			Date:  date,
+		})
+	}
+
+	return euroExchangeRates, nil
+}
+
+func (scrapper *BCCRScrapper) GetEuroExchangeRateByDate(ctx context.Context, date time.Time) (*models.EuroExchangeRate, error) {
+	euroExchangeRates, err := scrapper.GetEuroExchangeRateByDates(ctx, date, date)
+	if err != nil || len(euroExchangeRates) == 0 {
+		_ = level.Debug(scrapper.logger).Log("msg", "error getting euro exchange rate from html", "date", date)
+		return &models.EuroExchangeRate{}, err
+	}
+	return &euroExchangeRates[0], nil
+}
+
+func (scrapper *BCCRScrapper) GetReferenceDepositRateByDates(ctx context.Context, dateFrom time.Time, dateTo time.Time) ([]models.ReferenceDepositRate, error) {
+	url := scrapper.getScrappingUrl(scrapper.urls.ReferenceRateUrl, dateFrom, dateTo)
+	datedValuesHTML, err := scrapper.scrapYearsTable(ctx, url, dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+	dates, values := scrapper.toDatedValues(datedValuesHTML)
+
+	referenceDepositRates := []models.ReferenceDepositRate{}
+	for index, date := range dates {
+		referenceDepositRates = append(referenceDepositRates, models.ReferenceDepositRate{
+			Value: values[index],
+			Date:  date,
+		})
+	}
+
+	return referenceDepositRates, nil
+}
+
+func (scrapper *BCCRScrapper) GetReferenceDepositRateByDate(ctx context.Context, date time.Time) (*models.ReferenceDepositRate, error) {
+	referenceDepositRates, err := scrapper.GetReferenceDepositRateByDates(ctx, date, date)
+	if err != nil || len(referenceDepositRates) == 0 {
+		_ = level.Debug(scrapper.logger).Log("msg", "error getting reference deposit rate from html", "date", date)
+		return &models.ReferenceDepositRate{}, err
+	}
+	return &referenceDepositRates[0], nil
+}
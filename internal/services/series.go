@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+)
+
+// ParseSeries validates the name of a single series, the same ones that can be compared
+func ParseSeries(name string) (string, error) {
+	if _, ok := comparableSeriesCatalog[name]; !ok {
+		return "", fmt.Errorf("%w: %s", utils.ErrUnknownSeries, name)
+	}
+	return name, nil
+}
+
+// LookbackDays is how far back the last observation of a series can be, the monthly series publish late
+func LookbackDays(name string) int {
+	return comparableSeriesCatalog[name].lookbackDays
+}
+
+func (service *ServiceAPI) fetchSeries(ctx context.Context, name string, dateFrom time.Time, dateTo time.Time) ([]models.Observation, error) {
+	comparable, ok := comparableSeriesCatalog[name]
+	if !ok {
+		return nil, utils.ErrUnknownSeries
+	}
+	values, err := comparable.fetch(ctx, service, GetAllDollarColonesChangesRequest{DateFrom: dateFrom, DateTo: dateTo})
+	if err != nil {
+		return nil, err
+	}
+
+	observations := make([]models.Observation, 0, len(values))
+	for _, value := range values {
+		observations = append(observations, models.Observation{Series: name, Date: value.date, Value: value.value})
+	}
+	sort.Slice(observations, func(i, j int) bool {
+		return observations[i].Date.Before(observations[j].Date)
+	})
+	return observations, nil
+}
+
+// GetSeries returns the observations of a series in the range, oldest first
+func (service *ServiceAPI) GetSeries(ctx context.Context, req GetSeriesRequest) *GetSeriesResponse {
+	observations, err := service.fetchSeries(ctx, req.Series, req.DateFrom, req.DateTo)
+	if err != nil {
+		_ = level.Error(service.logger).Log("msg", "error getting series", "series", req.Series,
+			"date_from", req.DateFrom, "date_to", req.DateTo, "error", err)
+		return &GetSeriesResponse{
+			Series:       req.Series,
+			Observations: nil,
+			Err:          err,
+		}
+	}
+
+	return &GetSeriesResponse{
+		Series:       req.Series,
+		Observations: observations,
+		Err:          nil,
+	}
+}
+
+// GetLatestObservation looks for the last observation within the lookback of the series, the same window
+// used to forward-fill the comparisons
+func (service *ServiceAPI) GetLatestObservation(ctx context.Context, req GetLatestObservationRequest) *GetLatestObservationResponse {
+	comparable, ok := comparableSeriesCatalog[req.Series]
+	if !ok {
+		return &GetLatestObservationResponse{Observation: nil, Err: utils.ErrUnknownSeries}
+	}
+
+	dateTo := utils.Now()
+	observations, err := service.fetchSeries(ctx, req.Series, dateTo.AddDate(0, 0, -comparable.lookbackDays), dateTo)
+	if err != nil {
+		return &GetLatestObservationResponse{Observation: nil, Err: err}
+	}
+	if len(observations) == 0 {
+		return &GetLatestObservationResponse{Observation: nil, Err: utils.ErrNotFound}
+	}
+
+	return &GetLatestObservationResponse{
+		Observation: &observations[len(observations)-1],
+		Err:         nil,
+	}
+}
+
+// exchangeRateAt returns the last dollar exchange rate published on or before the date, and the date it
+// was published
+func (service *ServiceAPI) exchangeRateAt(ctx context.Context, date time.Time) (*models.ExchangeRate, time.Time, error) {
+	for i := 0; i < MAXIMUM_TRIES; i++ {
+		rate, err := service.Scrapper.GetExchangeRateByDate(ctx, date)
+		if err == nil && rate != nil && rate.BuyPrice != 0 && rate.SalePrice != 0 {
+			return rate, date, nil
+		}
+		date = date.AddDate(0, 0, -1)
+	}
+	return nil, time.Time{}, fmt.Errorf("%w: dollar exchange rate", utils.ErrNotFound)
+}
+
+// euroExchangeRateAt returns the last euro exchange rate published on or before the date, and the date it
+// was published
+func (service *ServiceAPI) euroExchangeRateAt(ctx context.Context, date time.Time) (*models.EuroExchangeRate, time.Time, error) {
+	for i := 0; i < MAXIMUM_TRIES; i++ {
+		rate, err := service.Scrapper.GetEuroExchangeRateByDate(ctx, date)
+		if err == nil && rate != nil && rate.Value != 0 {
+			return rate, date, nil
+		}
+		date = date.AddDate(0, 0, -1)
+	}
+	return nil, time.Time{}, fmt.Errorf("%w: euro exchange rate", utils.ErrNotFound)
+}
+
+// colonesPer returns how many colones a unit of the currency is worth. The dollars are bought at the
+// sale price and sold at the buy price of the BCCR, like a client would at a bank. The date is the one of
+// the rate used, the colones have none and answer the date asked.
+func (service *ServiceAPI) colonesPer(ctx context.Context, currency string, date time.Time, selling bool) (float64, time.Time, error) {
+	switch currency {
+	case models.CurrencyColones:
+		return 1, date, nil
+	case models.CurrencyDollars:
+		rate, published, err := service.exchangeRateAt(ctx, date)
+		if err != nil {
+			return 0, time.Time{}, err
+		}
+		if selling {
+			return rate.BuyPrice, published, nil
+		}
+		return rate.SalePrice, published, nil
+	case models.CurrencyEuros:
+		rate, published, err := service.euroExchangeRateAt(ctx, date)
+		if err != nil {
+			return 0, time.Time{}, err
+		}
+		return rate.Value, published, nil
+	}
+	return 0, time.Time{}, utils.ErrCurrency
+}
+
+// Convert converts an amount between colones, dollars and euros. The conversions between dollars and euros
+// cross through colones. The date of the conversion is the one of the rates used, the oldest of both when
+// the rates of the date asked were not published yet.
+func (service *ServiceAPI) Convert(ctx context.Context, req GetConvertRequest) *GetConvertResponse {
+	date := req.Date
+	if date.IsZero() {
+		date = utils.Now()
+	}
+
+	if req.From == req.To {
+		return &GetConvertResponse{
+			Conversion: &models.Conversion{Amount: req.Amount, From: req.From, To: req.To, Result: req.Amount, Rate: 1, Date: date},
+			Err:        nil,
+		}
+	}
+
+	from, fromDate, err := service.colonesPer(ctx, req.From, date, true)
+	if err != nil {
+		return &GetConvertResponse{Conversion: nil, Err: err}
+	}
+	to, toDate, err := service.colonesPer(ctx, req.To, date, false)
+	if err != nil {
+		return &GetConvertResponse{Conversion: nil, Err: err}
+	}
+	if toDate.Before(fromDate) {
+		fromDate = toDate
+	}
+
+	rate := from / to
+	return &GetConvertResponse{
+		Conversion: &models.Conversion{
+			Amount: req.Amount,
+			From:   req.From,
+			To:     req.To,
+			Result: roundCurrency(req.Amount * rate),
+			Rate:   rate,
+			Date:   fromDate,
+		},
+		Err: nil,
+	}
+}
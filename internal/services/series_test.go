@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+)
+
+// publishedRatesScrapper answers the exchange rates only on the dates they were published
+type publishedRatesScrapper struct {
+	fakeScrapper
+	dollars map[time.Time]models.ExchangeRate
+	euros   map[time.Time]models.EuroExchangeRate
+}
+
+func (fake *publishedRatesScrapper) GetExchangeRateByDate(_ context.Context, date time.Time) (*models.ExchangeRate, error) {
+	if rate, ok := fake.dollars[date]; ok {
+		return &rate, nil
+	}
+	return nil, nil
+}
+
+func (fake *publishedRatesScrapper) GetEuroExchangeRateByDate(_ context.Context, date time.Time) (*models.EuroExchangeRate, error) {
+	if rate, ok := fake.euros[date]; ok {
+		return &rate, nil
+	}
+	return nil, nil
+}
+
+func TestConvertAnswersTheDateOfTheRatesUsed(t *testing.T) {
+	sunday := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	friday := sunday.AddDate(0, 0, -2)
+	saturday := sunday.AddDate(0, 0, -1)
+	service := newTestService(&publishedRatesScrapper{
+		dollars: map[time.Time]models.ExchangeRate{friday: {Date: friday, BuyPrice: 500, SalePrice: 510}},
+		euros:   map[time.Time]models.EuroExchangeRate{saturday: {Date: saturday, Value: 550}},
+	})
+
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		expected time.Time
+	}{
+		{"same currency", models.CurrencyDollars, models.CurrencyDollars, sunday},
+		{"dollars published on friday", models.CurrencyDollars, models.CurrencyColones, friday},
+		{"euros published on saturday", models.CurrencyColones, models.CurrencyEuros, saturday},
+		// the conversion is as old as the oldest of its rates
+		{"dollars to euros", models.CurrencyDollars, models.CurrencyEuros, friday},
+		{"euros to dollars", models.CurrencyEuros, models.CurrencyDollars, friday},
+	}
+	for _, test := range tests {
+		response := service.Convert(context.Background(), GetConvertRequest{Amount: 100, From: test.from, To: test.to, Date: sunday})
+		if response.Err != nil {
+			t.Errorf("%s: %s", test.name, response.Err)
+			continue
+		}
+		if !response.Conversion.Date.Equal(test.expected) {
+			t.Errorf("%s: the date is %s, expected %s", test.name, response.Conversion.Date.Format("2006-01-02"), test.expected.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestConvertWithoutRatesInTheLastDays(t *testing.T) {
+	sunday := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	service := newTestService(&publishedRatesScrapper{
+		dollars: map[time.Time]models.ExchangeRate{sunday.AddDate(0, 0, -MAXIMUM_TRIES): {BuyPrice: 500, SalePrice: 510}},
+	})
+
+	response := service.Convert(context.Background(), GetConvertRequest{Amount: 100, From: models.CurrencyDollars, To: models.CurrencyColones, Date: sunday})
+	if response.Err == nil {
+		t.Errorf("converted with a rate older than %d days: %+v", MAXIMUM_TRIES, response.Conversion)
+	}
+}
@@ -4,13 +4,16 @@ import (
 	"context"
 	"errors"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/go-kit/kit/log/level"
+	"github.com/go-kit/kit/metrics"
 	"github.com/go-kit/log"
 	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
 	"github.com/jrodolforojas/libertadfinanciera-backend/internal/repositories"
 	"github.com/jrodolforojas/libertadfinanciera-backend/internal/services/scrapper"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
 )
 
 type Service interface {
@@ -28,67 +31,48 @@ type Service interface {
 	GetCostaRicaInflationRate(ctx context.Context, req GetTodayExchangeRateRequest) *GetTodayCostaRicaInflationRateResponse
 	GetTreasuryRatesUSA(ctx context.Context, req GetAllDollarColonesChangesRequest) *GetTreasuryRatesUSAResponse
 	GetTodayTreasuryRateUSA(ctx context.Context, req GetTodayExchangeRateRequest) *GetTodayTreasuryRateUSAResponse
+	GetTreasuryYieldCurve(ctx context.Context, req GetTreasuryYieldCurveRequest) *GetTreasuryYieldCurveResponse
+	GetTreasuryYieldSeries(ctx context.Context, req GetTreasuryYieldSeriesRequest) *GetTreasuryYieldSeriesResponse
 	GetUSAInflationRates(ctx context.Context, req GetAllDollarColonesChangesRequest) *GetUSAInflationRatesResponse
 	GetUSAInflationRate(ctx context.Context, req GetTodayExchangeRateRequest) *GetTodayUSAInflationRateResponse
+	GetUSAMonthlyInflationRates(ctx context.Context, req GetAllDollarColonesChangesRequest) *GetUSAInflationRatesResponse
+	GetUSAConsumerPriceIndexes(ctx context.Context, req GetAllDollarColonesChangesRequest) *GetUSAConsumerPriceIndexesResponse
+	GetMonexExchangeRates(ctx context.Context, req GetAllDollarColonesChangesRequest) *GetMonexExchangeRatesResponse
+	GetTodayMonexExchangeRate(ctx context.Context, req GetTodayExchangeRateRequest) *GetTodayMonexExchangeRateResponse
+	GetEuroExchangeRates(ctx context.Context, req GetAllDollarColonesChangesRequest) *GetEuroExchangeRatesResponse
+	GetTodayEuroExchangeRate(ctx context.Context, req GetTodayExchangeRateRequest) *GetTodayEuroExchangeRateResponse
+	GetReferenceDepositRates(ctx context.Context, req GetAllDollarColonesChangesRequest) *GetReferenceDepositRatesResponse
+	GetTodayReferenceDepositRate(ctx context.Context, req GetTodayExchangeRateRequest) *GetTodayReferenceDepositRateResponse
+	GetInflationAdjustedAmount(ctx context.Context, req GetInflationCalculatorRequest) *GetInflationCalculatorResponse
+	GetLoanSchedule(ctx context.Context, req GetLoanCalculatorRequest) *GetLoanCalculatorResponse
+	CompareSeries(ctx context.Context, req GetCompareSeriesRequest) *GetCompareSeriesResponse
 }
 
 type ServiceAPI struct {
 	logger     log.Logger
 	Scrapper   scrapper.Scrapper
 	Repository repositories.Repository
+	fanOut     metrics.Histogram
 }
 
-func NewService(logger log.Logger, scrapperService scrapper.Scrapper, repo repositories.Repository) *ServiceAPI {
+func NewService(logger log.Logger, scrapperService scrapper.Scrapper, repo repositories.Repository, fanOut metrics.Histogram) *ServiceAPI {
 	return &ServiceAPI{
 		logger:     logger,
 		Scrapper:   scrapperService,
 		Repository: repo,
+		fanOut:     fanOut,
 	}
 }
 
 func (service *ServiceAPI) GetDollarColonesChange(ctx context.Context, req GetAllDollarColonesChangesRequest) *GetAllDollarColonesChangesResponse {
-	exchangeRates := []models.ExchangeRate{}
-
-	dateFrom := req.DateFrom
-
-	dateRanges := []models.DateRange{}
-	for {
-		if dateFrom.Month() == req.DateTo.Month() && dateFrom.Year() == req.DateTo.Year() {
-			dateRanges = append(dateRanges, models.DateRange{
-				DateFrom: dateFrom,
-				DateTo:   req.DateTo,
-			})
-			break
-		}
-		dateTo := dateFrom.AddDate(0, 1, 0) // add 1 month to dateFrom
-		dateRanges = append(dateRanges, models.DateRange{
-			DateFrom: dateFrom,
-			DateTo:   dateTo,
+	exchangeRates, err := scrapByMonths(ctx, service, "exchange_rates", req.DateFrom, req.DateTo,
+		func(ctx context.Context, dateFrom time.Time, dateTo time.Time) ([]models.ExchangeRate, error) {
+			return service.Scrapper.GetDollarColonesChangeByDates(ctx, dateFrom, dateTo, 0)
 		})
-		dateFrom = dateTo
-	}
-
-	errc := make(chan error, len(dateRanges))
-	for _, dateRange := range dateRanges {
-		go func(dateFrom time.Time, dateTo time.Time) {
-			result, err := service.Scrapper.GetDollarColonesChangeByDates(dateFrom, dateTo, 0)
-			if err != nil {
-				_ = level.Debug(service.logger).Log("msg", "error scrapping exchange rate by dates",
-					"date_from", dateFrom, "date_to", dateTo)
-				errc <- err
-				return
-			}
-			exchangeRates = append(exchangeRates, result...)
-			errc <- nil
-		}(dateRange.DateFrom, dateRange.DateTo)
-	}
-
-	for i := 0; i < len(dateRanges); i++ {
-		if err := <-errc; err != nil {
-			return &GetAllDollarColonesChangesResponse{
-				ExchangesRates: nil,
-				Err:            err,
-			}
+	if err != nil {
+		return &GetAllDollarColonesChangesResponse{
+			ExchangesRates: nil,
+			Err:            err,
 		}
 	}
 
@@ -96,12 +80,21 @@ func (service *ServiceAPI) GetDollarColonesChange(ctx context.Context, req GetAl
 		return exchangeRates[i].Date.After(exchangeRates[j].Date)
 	})
 
+	exchangeRates, meta := paginate(exchangeRates, func(item models.ExchangeRate) time.Time { return item.Date }, req.Page)
 	return &GetAllDollarColonesChangesResponse{
 		ExchangesRates: exchangeRates,
+		Meta:           meta,
 		Err:            nil,
 	}
 }
 
+// the periodicities of the exchange rates and of the costa rican inflation scrape the whole history from
+// these dates
+var (
+	EXCHANGE_RATES_HISTORY_FROM       = time.Date(1983, 0, 31, 0, 0, 0, 0, time.UTC)
+	COSTA_RICA_INFLATION_HISTORY_FROM = time.Date(1976, 0, 31, 0, 0, 0, 0, time.UTC)
+)
+
 func (service *ServiceAPI) GetExchangeRatesByFilter(ctx context.Context, req GetDataByFilterRequest) *GetAllDollarColonesChangesResponse {
 	filtersArray := []int64{}
 
@@ -117,15 +110,16 @@ func (service *ServiceAPI) GetExchangeRatesByFilter(ctx context.Context, req Get
 
 	exchangeRates := []models.ExchangeRate{}
 
-	minimumDate := time.Date(1983, 0, 31, 0, 0, 0, 0, time.UTC)
+	minimumDate := EXCHANGE_RATES_HISTORY_FROM
 	bridgeDate := time.Date(2003, 0, 1, 0, 0, 0, 0, time.UTC)
-	today := time.Now()
+	today := utils.Now()
 
+	service.fanOut.With("operation", "exchange_rates_filter").Observe(float64(2 * len(filtersArray)))
 	errc := make(chan error, len(filtersArray))
 
 	for _, filter := range filtersArray {
 		go func(filter int64) {
-			result, err := service.Scrapper.GetDollarColonesChangeByDates(minimumDate, bridgeDate, filter)
+			result, err := service.Scrapper.GetDollarColonesChangeByDates(ctx, minimumDate, bridgeDate, filter)
 			if err != nil {
 				_ = level.Debug(service.logger).Log("msg", "error scrapping inflation rate by filter",
 					"date_from", minimumDate, "date_to", bridgeDate, "filter", filter, "error", err)
@@ -141,7 +135,7 @@ func (service *ServiceAPI) GetExchangeRatesByFilter(ctx context.Context, req Get
 	errcBridgeToday := make(chan error, len(filtersArray))
 	for _, filter := range filtersArray {
 		go func(filter int64) {
-			result, err := service.Scrapper.GetDollarColonesChangeByDates(bridgeDate, today, filter)
+			result, err := service.Scrapper.GetDollarColonesChangeByDates(ctx, bridgeDate, today, filter)
 			if err != nil {
 				_ = level.Debug(service.logger).Log("msg", "error scrapping inflation rate by filter",
 					"date_from", bridgeDate, "date_to", today, "filter", filter, "error", err)
@@ -194,9 +188,9 @@ func (service *ServiceAPI) GetExchangeRatesByFilter(ctx context.Context, req Get
 	}
 }
 func (service *ServiceAPI) GetTodayExchangeRate(ctx context.Context, req GetTodayExchangeRateRequest) *GetTodayExchangeRateResponse {
-	date := time.Now()
+	date := utils.Now()
 	for i := 0; i < MAXIMUM_TRIES; i++ {
-		rate, err := service.Scrapper.GetExchangeRateByDate(date)
+		rate, err := service.Scrapper.GetExchangeRateByDate(ctx, date)
 		if rate.BuyPrice != 0 && rate.SalePrice != 0 {
 			return &GetTodayExchangeRateResponse{
 				ExchangesRate: rate,
@@ -225,7 +219,7 @@ func (service *ServiceAPI) GetBasicPassiveRates(ctx context.Context, req GetAllD
 	for {
 		if yearDifference >= MAXIMUM_BASIC_PASSIVE_RATE_YEAR {
 			newDateTo := service.addYears(dateFrom, MAXIMUM_BASIC_PASSIVE_RATE_YEAR)
-			result, err := service.Scrapper.GetBasicPassiveRateByDates(dateFrom, newDateTo)
+			result, err := service.Scrapper.GetBasicPassiveRateByDates(ctx, dateFrom, newDateTo)
 			if err != nil {
 				_ = level.Debug(service.logger).Log("msg", "error scrapping basic passive rates by dates",
 					"date_from", dateFrom, "date_to", newDateTo)
@@ -236,7 +230,7 @@ func (service *ServiceAPI) GetBasicPassiveRates(ctx context.Context, req GetAllD
 			yearDifference = req.DateTo.Year() - dateFrom.Year()
 
 		} else {
-			result, err := service.Scrapper.GetBasicPassiveRateByDates(dateFrom, req.DateTo)
+			result, err := service.Scrapper.GetBasicPassiveRateByDates(ctx, dateFrom, req.DateTo)
 			if err != nil {
 				_ = level.Debug(service.logger).Log("msg", "error scrapping basic passive rates by dates",
 					"date_from", dateFrom, "date_to", req.DateTo)
@@ -250,16 +244,18 @@ func (service *ServiceAPI) GetBasicPassiveRates(ctx context.Context, req GetAllD
 	sort.Slice(basicPassiveRates, func(i, j int) bool {
 		return basicPassiveRates[i].Date.After(basicPassiveRates[j].Date)
 	})
+	basicPassiveRates, meta := paginate(basicPassiveRates, func(item models.BasicPassiveRate) time.Time { return item.Date }, req.Page)
 	return &GetBasicPassiveRatesResponse{
 		BasicPassiveRates: basicPassiveRates,
+		Meta:              meta,
 		Err:               nil,
 	}
 }
 
 func (service *ServiceAPI) GetTodayBasicPassiveRate(ctx context.Context, req GetTodayExchangeRateRequest) *GetTodayBasicPassiveRateResponse {
-	date := time.Now()
+	date := utils.Now()
 	for i := 0; i < MAXIMUM_TRIES; i++ {
-		rate, err := service.Scrapper.GetBasicPassiveDateByDate(date)
+		rate, err := service.Scrapper.GetBasicPassiveDateByDate(ctx, date)
 		if rate.Value != 0 {
 			return &GetTodayBasicPassiveRateResponse{
 				BasicPassiveRate: rate,
@@ -284,7 +280,7 @@ func (service *ServiceAPI) GetMonetaryPolicyRates(ctx context.Context, req GetAl
 	for {
 		if yearDifference >= MAXIMUM_MONETARY_POLICY_RATE_YEAR {
 			newDateTo := service.addYears(dateFrom, MAXIMUM_MONETARY_POLICY_RATE_YEAR)
-			result, err := service.Scrapper.GetMonetaryPolicyRateByDates(dateFrom, newDateTo)
+			result, err := service.Scrapper.GetMonetaryPolicyRateByDates(ctx, dateFrom, newDateTo)
 			if err != nil {
 				_ = level.Debug(service.logger).Log("msg", "error scrapping basic passive rates by dates",
 					"date_from", dateFrom, "date_to", newDateTo)
@@ -295,7 +291,7 @@ func (service *ServiceAPI) GetMonetaryPolicyRates(ctx context.Context, req GetAl
 			yearDifference = req.DateTo.Year() - dateFrom.Year()
 
 		} else {
-			result, err := service.Scrapper.GetMonetaryPolicyRateByDates(dateFrom, req.DateTo)
+			result, err := service.Scrapper.GetMonetaryPolicyRateByDates(ctx, dateFrom, req.DateTo)
 			if err != nil {
 				_ = level.Debug(service.logger).Log("msg", "error scrapping basic passive rates by dates",
 					"date_from", dateFrom, "date_to", req.DateTo)
@@ -309,16 +305,18 @@ func (service *ServiceAPI) GetMonetaryPolicyRates(ctx context.Context, req GetAl
 	sort.Slice(monetaryPolicyRates, func(i, j int) bool {
 		return monetaryPolicyRates[i].Date.After(monetaryPolicyRates[j].Date)
 	})
+	monetaryPolicyRates, meta := paginate(monetaryPolicyRates, func(item models.MonetaryPolicyRate) time.Time { return item.Date }, req.Page)
 	return &GetMonetaryPolicyRatesResponse{
 		MonetaryPolicyRates: monetaryPolicyRates,
+		Meta:                meta,
 		Err:                 nil,
 	}
 }
 
 func (service *ServiceAPI) GetTodayMonetaryPolicyRate(ctx context.Context, req GetTodayExchangeRateRequest) *GetTodayMonetaryPolicyRateResponse {
-	date := time.Now()
+	date := utils.Now()
 	for i := 0; i < MAXIMUM_TRIES; i++ {
-		rate, err := service.Scrapper.GetMonetaryPolicyRateByDate(date)
+		rate, err := service.Scrapper.GetMonetaryPolicyRateByDate(ctx, date)
 		if rate.Value != 0 {
 			return &GetTodayMonetaryPolicyRateResponse{
 				MonetaryPolicyRate: rate,
@@ -343,7 +341,7 @@ func (service *ServiceAPI) GetPrimeRates(ctx context.Context, req GetAllDollarCo
 	for {
 		if yearDifference >= MAXIMUM_PRIME_RATE_YEAR {
 			newDateTo := service.addYears(dateFrom, MAXIMUM_PRIME_RATE_YEAR)
-			result, err := service.Scrapper.GetPrimeRateByDates(dateFrom, newDateTo)
+			result, err := service.Scrapper.GetPrimeRateByDates(ctx, dateFrom, newDateTo)
 			if err != nil {
 				_ = level.Debug(service.logger).Log("msg", "error scrapping basic passive rates by dates",
 					"date_from", dateFrom, "date_to", newDateTo)
@@ -354,7 +352,7 @@ func (service *ServiceAPI) GetPrimeRates(ctx context.Context, req GetAllDollarCo
 			yearDifference = req.DateTo.Year() - dateFrom.Year()
 
 		} else {
-			result, err := service.Scrapper.GetPrimeRateByDates(dateFrom, req.DateTo)
+			result, err := service.Scrapper.GetPrimeRateByDates(ctx, dateFrom, req.DateTo)
 			if err != nil {
 				_ = level.Debug(service.logger).Log("msg", "error scrapping basic passive rates by dates",
 					"date_from", dateFrom, "date_to", req.DateTo)
@@ -368,8 +366,10 @@ func (service *ServiceAPI) GetPrimeRates(ctx context.Context, req GetAllDollarCo
 	sort.Slice(primeRates, func(i, j int) bool {
 		return primeRates[i].Date.After(primeRates[j].Date)
 	})
+	primeRates, meta := paginate(primeRates, func(item models.PrimeRate) time.Time { return item.Date }, req.Page)
 	return &GetPrimeRatesResponse{
 		PrimeRates: primeRates,
+		Meta:       meta,
 		Err:        nil,
 	}
 }
@@ -377,9 +377,9 @@ func (service *ServiceAPI) GetPrimeRates(ctx context.Context, req GetAllDollarCo
 const MAXIMUM_TRIES = 5
 
 func (service *ServiceAPI) GetTodayPrimeRate(ctx context.Context, req GetTodayExchangeRateRequest) *GetTodayPrimeRateResponse {
-	date := time.Now()
+	date := utils.Now()
 	for i := 0; i < MAXIMUM_TRIES; i++ {
-		primeRate, err := service.Scrapper.GetPrimeRateByDate(date)
+		primeRate, err := service.Scrapper.GetPrimeRateByDate(ctx, date)
 		if primeRate.Value != 0 {
 			return &GetTodayPrimeRateResponse{
 				PrimeRate: primeRate,
@@ -424,10 +424,11 @@ func (service *ServiceAPI) GetCostaRicaInflationRates(ctx context.Context, req G
 		}
 	}
 
+	service.fanOut.With("operation", "inflation_rates_cr").Observe(float64(len(dateRanges)))
 	errc := make(chan error, len(dateRanges))
 	for _, dateRange := range dateRanges {
 		go func(dateFrom time.Time, dateTo time.Time) {
-			result, err := service.Scrapper.GetCostaRicaInflationRateByDates(dateFrom, dateTo, 0)
+			result, err := service.Scrapper.GetCostaRicaInflationRateByDates(ctx, dateFrom, dateTo, 0)
 			if err != nil {
 				_ = level.Debug(service.logger).Log("msg", "error scrapping inflation rate by dates",
 					"date_from", dateFrom, "date_to", dateTo)
@@ -451,8 +452,10 @@ func (service *ServiceAPI) GetCostaRicaInflationRates(ctx context.Context, req G
 	sort.Slice(inflationRates, func(i, j int) bool {
 		return inflationRates[i].Date.After(inflationRates[j].Date)
 	})
+	inflationRates, meta := paginate(inflationRates, func(item models.CostaRicaInflationRate) time.Time { return item.Date }, req.Page)
 	return &GetCostaRicaInflationRatesResponse{
 		InflationRates: inflationRates,
+		Meta:           meta,
 		Err:            nil,
 	}
 }
@@ -472,15 +475,16 @@ func (service *ServiceAPI) GetCostaRicaInflationRatesByFilter(ctx context.Contex
 
 	inflationRates := []models.CostaRicaInflationRate{}
 
-	minimumDate := time.Date(1976, 0, 31, 0, 0, 0, 0, time.UTC)
+	minimumDate := COSTA_RICA_INFLATION_HISTORY_FROM
 	bridgeDate := time.Date(1995, 0, 1, 0, 0, 0, 0, time.UTC)
-	today := time.Now()
+	today := utils.Now()
 
+	service.fanOut.With("operation", "inflation_rates_cr_filter").Observe(float64(2 * len(filtersArray)))
 	errc := make(chan error, len(filtersArray))
 
 	for _, filter := range filtersArray {
 		go func(filter int64) {
-			result, err := service.Scrapper.GetCostaRicaInflationRateByDates(minimumDate, bridgeDate, filter)
+			result, err := service.Scrapper.GetCostaRicaInflationRateByDates(ctx, minimumDate, bridgeDate, filter)
 			if err != nil {
 				_ = level.Debug(service.logger).Log("msg", "error scrapping inflation rate by filter",
 					"date_from", minimumDate, "date_to", bridgeDate, "filter", filter, "error", err)
@@ -496,7 +500,7 @@ func (service *ServiceAPI) GetCostaRicaInflationRatesByFilter(ctx context.Contex
 	errcBridgeToday := make(chan error, len(filtersArray))
 	for _, filter := range filtersArray {
 		go func(filter int64) {
-			result, err := service.Scrapper.GetCostaRicaInflationRateByDates(bridgeDate, today, filter)
+			result, err := service.Scrapper.GetCostaRicaInflationRateByDates(ctx, bridgeDate, today, filter)
 			if err != nil {
 				_ = level.Debug(service.logger).Log("msg", "error scrapping inflation rate by filter",
 					"date_from", bridgeDate, "date_to", today, "filter", filter, "error", err)
@@ -550,9 +554,9 @@ func (service *ServiceAPI) GetCostaRicaInflationRatesByFilter(ctx context.Contex
 }
 
 func (service *ServiceAPI) GetCostaRicaInflationRate(ctx context.Context, req GetTodayExchangeRateRequest) *GetTodayCostaRicaInflationRateResponse {
-	date := time.Now()
+	date := utils.Now()
 	for i := 0; i < MAXIMUM_TRIES; i++ {
-		inflationRate, err := service.Scrapper.GetCostaRicaInflationRateByDate(date)
+		inflationRate, err := service.Scrapper.GetCostaRicaInflationRateByDate(ctx, date)
 		if inflationRate.Value != 0 {
 			return &GetTodayCostaRicaInflationRateResponse{
 				InflationRate: inflationRate,
@@ -588,10 +592,11 @@ func (service *ServiceAPI) GetTreasuryRatesUSA(ctx context.Context, req GetAllDo
 		dateFrom = dateTo
 	}
 
+	service.fanOut.With("operation", "treasury_rates_usa").Observe(float64(len(dateRanges)))
 	errc := make(chan error, len(dateRanges))
 	for _, dateRange := range dateRanges {
 		go func(dateFrom time.Time, dateTo time.Time) {
-			result, err := service.Scrapper.GetTreasuryRateUSAByDates(dateFrom, dateTo)
+			result, err := service.Scrapper.GetTreasuryRateUSAByDates(ctx, dateFrom, dateTo)
 			if err != nil {
 				_ = level.Debug(service.logger).Log("msg", "error scrapping USA treasury rate by dates", "error", err)
 				errc <- err
@@ -615,17 +620,19 @@ func (service *ServiceAPI) GetTreasuryRatesUSA(ctx context.Context, req GetAllDo
 		return treasuryRates[i].Date.After(treasuryRates[j].Date)
 	})
 
+	treasuryRates, meta := paginate(treasuryRates, func(item models.TreasuryRateUSA) time.Time { return item.Date }, req.Page)
 	return &GetTreasuryRatesUSAResponse{
 		TreasuryRatesUSA: treasuryRates,
+		Meta:             meta,
 		Err:              nil,
 	}
 }
 
 func (service *ServiceAPI) GetTodayTreasuryRateUSA(ctx context.Context, req GetTodayExchangeRateRequest) *GetTodayTreasuryRateUSAResponse {
-	date := time.Now()
+	date := utils.Now()
 	const treasuryRateMaxiumTries = 30
 	for i := 0; i < treasuryRateMaxiumTries; i++ {
-		todayTreasuryRateUSA, err := service.Scrapper.GetTreasuryRateUSAByDate(date)
+		todayTreasuryRateUSA, err := service.Scrapper.GetTreasuryRateUSAByDate(ctx, date)
 		if todayTreasuryRateUSA.Value != 0 {
 			return &GetTodayTreasuryRateUSAResponse{
 				TreasuryRateUSA: todayTreasuryRateUSA,
@@ -641,68 +648,400 @@ func (service *ServiceAPI) GetTodayTreasuryRateUSA(ctx context.Context, req GetT
 	}
 }
 
-func (service *ServiceAPI) getUSAInflationRateByDate(date time.Time, inflationRates []models.USAInflationRate) (*models.USAInflationRate, error) {
-	for _, inflationRate := range inflationRates {
-		if inflationRate.Date.Month() == date.Month() && inflationRate.Date.Year() == date.Year() {
-			return &inflationRate, nil
+// scrapByMonths splits the range in months and scraps them concurrently, the BCCR daily tables are slow
+// to render long ranges
+func scrapByMonths[T any](ctx context.Context, service *ServiceAPI, operation string, dateFrom time.Time, dateTo time.Time, scrap func(ctx context.Context, dateFrom time.Time, dateTo time.Time) ([]T, error)) ([]T, error) {
+	// the months do not share their limit dates, every date is scrapped once
+	dateRanges := []models.DateRange{}
+	for {
+		monthDateTo := dateFrom.AddDate(0, 1, -1)
+		if !monthDateTo.Before(dateTo) {
+			dateRanges = append(dateRanges, models.DateRange{
+				DateFrom: dateFrom,
+				DateTo:   dateTo,
+			})
+			break
 		}
+		dateRanges = append(dateRanges, models.DateRange{
+			DateFrom: dateFrom,
+			DateTo:   monthDateTo,
+		})
+		dateFrom = monthDateTo.AddDate(0, 0, 1)
 	}
-	return nil, errors.New("no results found")
+
+	service.fanOut.With("operation", operation).Observe(float64(len(dateRanges)))
+	var mutex sync.Mutex
+	results := []T{}
+	errc := make(chan error, len(dateRanges))
+	for _, dateRange := range dateRanges {
+		go func(dateFrom time.Time, dateTo time.Time) {
+			if err := ctx.Err(); err != nil {
+				errc <- err
+				return
+			}
+			result, err := scrap(ctx, dateFrom, dateTo)
+			if err != nil {
+				_ = level.Debug(service.logger).Log("msg", "error scrapping by dates",
+					"date_from", dateFrom, "date_to", dateTo, "error", err)
+				errc <- err
+				return
+			}
+			mutex.Lock()
+			results = append(results, result...)
+			mutex.Unlock()
+			errc <- nil
+		}(dateRange.DateFrom, dateRange.DateTo)
+	}
+
+	// a cancelled request does not wait for the months still loading, errc keeps their results
+	for i := 0; i < len(dateRanges); i++ {
+		select {
+		case err := <-errc:
+			if err != nil {
+				return nil, err
+			}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return results, nil
+}
+
+// scrapByYears splits the range in chunks of maximumYears, the BCCR tables with one column per year
+// only render a limited amount of years
+func scrapByYears[T any](ctx context.Context, service *ServiceAPI, dateFrom time.Time, dateTo time.Time, maximumYears int, scrap func(ctx context.Context, dateFrom time.Time, dateTo time.Time) ([]T, error)) ([]T, error) {
+	results := []T{}
+	for dateTo.Year()-dateFrom.Year() >= maximumYears {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		newDateTo := service.addYears(dateFrom, maximumYears)
+		result, err := scrap(ctx, dateFrom, newDateTo)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result...)
+		dateFrom = newDateTo.AddDate(0, 0, 1)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	result, err := scrap(ctx, dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+	return append(results, result...), nil
 }
 
-func (service *ServiceAPI) calculateInteranualInflationRate(inflationRate models.USAInflationRate, yearAgoInflationRate models.USAInflationRate) float64 {
-	return ((inflationRate.Value - yearAgoInflationRate.Value) / yearAgoInflationRate.Value) * 100
+func (service *ServiceAPI) getTreasuryYieldCurves(ctx context.Context, dateFrom time.Time, dateTo time.Time) ([]models.TreasuryYieldCurve, error) {
+	yieldCurves, err := scrapByMonths(ctx, service, "treasury_yield_curves", dateFrom, dateTo, service.Scrapper.GetTreasuryYieldCurvesByDates)
+	if err != nil {
+		return nil, err
+	}
+
+	// the months are scrapped concurrently, the latest curve goes first
+	sort.Slice(yieldCurves, func(i, j int) bool {
+		return yieldCurves[i].Date.After(yieldCurves[j].Date)
+	})
+	return yieldCurves, nil
 }
 
-func (service *ServiceAPI) GetUSAInflationRates(ctx context.Context, req GetAllDollarColonesChangesRequest) *GetUSAInflationRatesResponse {
-	result, err := service.Scrapper.GetUSAInflationRateByDates(req.DateFrom, req.DateTo)
+// TREASURY_YIELD_CURVE_LOOKBACK_DAYS covers weekends and USA holidays when looking for the curve of a date
+const TREASURY_YIELD_CURVE_LOOKBACK_DAYS = 10
+
+func (service *ServiceAPI) GetTreasuryYieldCurve(ctx context.Context, req GetTreasuryYieldCurveRequest) *GetTreasuryYieldCurveResponse {
+	yieldCurves, err := service.getTreasuryYieldCurves(ctx, req.Date.AddDate(0, 0, -TREASURY_YIELD_CURVE_LOOKBACK_DAYS), req.Date)
 	if err != nil {
-		_ = level.Debug(service.logger).Log("msg", "error scrapping USA inflation rate by dates",
-			"date_from", req.DateFrom, "date_to", req.DateTo)
-		return &GetUSAInflationRatesResponse{
-			InflationRates: nil,
-			Err:            err,
+		return &GetTreasuryYieldCurveResponse{
+			YieldCurve: nil,
+			Err:        err,
+		}
+	}
+
+	if len(yieldCurves) == 0 {
+		_ = level.Error(service.logger).Log("msg", "error scrapping USA treasury yield curve by date", "date", req.Date)
+		return &GetTreasuryYieldCurveResponse{
+			YieldCurve: nil,
+			Err:        utils.ErrNotFound,
+		}
+	}
+
+	return &GetTreasuryYieldCurveResponse{
+		YieldCurve: &yieldCurves[0],
+		Err:        nil,
+	}
+}
+
+func (service *ServiceAPI) GetTreasuryYieldSeries(ctx context.Context, req GetTreasuryYieldSeriesRequest) *GetTreasuryYieldSeriesResponse {
+	yieldCurves, err := service.getTreasuryYieldCurves(ctx, req.DateFrom, req.DateTo)
+	if err != nil {
+		return &GetTreasuryYieldSeriesResponse{
+			Maturity: req.Maturity,
+			Yields:   nil,
+			Err:      err,
+		}
+	}
+
+	yields := []models.TreasuryRateUSA{}
+	for _, yieldCurve := range yieldCurves {
+		for _, yield := range yieldCurve.Yields {
+			if yield.Maturity == req.Maturity {
+				yields = append(yields, models.TreasuryRateUSA{
+					Value: yield.Value,
+					Date:  yieldCurve.Date,
+				})
+			}
+		}
+	}
+
+	yields, meta := paginate(yields, func(item models.TreasuryRateUSA) time.Time { return item.Date }, req.Page)
+	return &GetTreasuryYieldSeriesResponse{
+		Maturity: req.Maturity,
+		Yields:   yields,
+		Meta:     meta,
+		Err:      nil,
+	}
+}
+
+func (service *ServiceAPI) GetMonexExchangeRates(ctx context.Context, req GetAllDollarColonesChangesRequest) *GetMonexExchangeRatesResponse {
+	monexExchangeRates, err := scrapByMonths(ctx, service, "monex_exchange_rates", req.DateFrom, req.DateTo, service.Scrapper.GetMonexExchangeRateByDates)
+	if err != nil {
+		return &GetMonexExchangeRatesResponse{
+			MonexExchangeRates: nil,
+			Err:                err,
 		}
 	}
 
-	interanualInflationRates := []models.USAInflationRate{}
+	sort.Slice(monexExchangeRates, func(i, j int) bool {
+		return monexExchangeRates[i].Date.After(monexExchangeRates[j].Date)
+	})
 
-	for _, inflationRate := range result {
-		yearAgo := inflationRate.Date.AddDate(-1, 0, 0)
-		yearAgoInflationRate, err := service.getUSAInflationRateByDate(yearAgo, result)
-		if yearAgoInflationRate != nil && err == nil {
-			result := service.calculateInteranualInflationRate(inflationRate, *yearAgoInflationRate)
-			interanualInflationRate := models.USAInflationRate{
-				Date:  inflationRate.Date,
-				Value: result,
+	monexExchangeRates, meta := paginate(monexExchangeRates, func(item models.MonexExchangeRate) time.Time { return item.Date }, req.Page)
+	return &GetMonexExchangeRatesResponse{
+		MonexExchangeRates: monexExchangeRates,
+		Meta:               meta,
+		Err:                nil,
+	}
+}
+
+func (service *ServiceAPI) GetTodayMonexExchangeRate(ctx context.Context, req GetTodayExchangeRateRequest) *GetTodayMonexExchangeRateResponse {
+	date := utils.Now()
+	for i := 0; i < MAXIMUM_TRIES; i++ {
+		rate, err := service.Scrapper.GetMonexExchangeRateByDate(ctx, date)
+		if rate.Value != 0 {
+			return &GetTodayMonexExchangeRateResponse{
+				MonexExchangeRate: rate,
+				Err:               err,
 			}
-			interanualInflationRates = append(interanualInflationRates, interanualInflationRate)
+		}
+		date = date.AddDate(0, 0, -1)
+	}
+	_ = level.Error(service.logger).Log("msg", "error scrapping MONEX exchange rate by date", "date", date)
+	return &GetTodayMonexExchangeRateResponse{
+		MonexExchangeRate: nil,
+		Err:               errors.New("no results found"),
+	}
+}
+
+func (service *ServiceAPI) GetEuroExchangeRates(ctx context.Context, req GetAllDollarColonesChangesRequest) *GetEuroExchangeRatesResponse {
+	euroExchangeRates, err := scrapByMonths(ctx, service, "euro_exchange_rates", req.DateFrom, req.DateTo, service.Scrapper.GetEuroExchangeRateByDates)
+	if err != nil {
+		return &GetEuroExchangeRatesResponse{
+			EuroExchangeRates: nil,
+			Err:               err,
 		}
 	}
 
+	sort.Slice(euroExchangeRates, func(i, j int) bool {
+		return euroExchangeRates[i].Date.After(euroExchangeRates[j].Date)
+	})
+
+	euroExchangeRates, meta := paginate(euroExchangeRates, func(item models.EuroExchangeRate) time.Time { return item.Date }, req.Page)
+	return &GetEuroExchangeRatesResponse{
+		EuroExchangeRates: euroExchangeRates,
+		Meta:              meta,
+		Err:               nil,
+	}
+}
+
+func (service *ServiceAPI) GetTodayEuroExchangeRate(ctx context.Context, req GetTodayExchangeRateRequest) *GetTodayEuroExchangeRateResponse {
+	date := utils.Now()
+	for i := 0; i < MAXIMUM_TRIES; i++ {
+		rate, err := service.Scrapper.GetEuroExchangeRateByDate(ctx, date)
+		if rate.Value != 0 {
+			return &GetTodayEuroExchangeRateResponse{
+				EuroExchangeRate: rate,
+				Err:              err,
+			}
+		}
+		date = date.AddDate(0, 0, -1)
+	}
+	_ = level.Error(service.logger).Log("msg", "error scrapping euro exchange rate by date", "date", date)
+	return &GetTodayEuroExchangeRateResponse{
+		EuroExchangeRate: nil,
+		Err:              errors.New("no results found"),
+	}
+}
+
+const MAXIMUM_REFERENCE_DEPOSIT_RATE_YEAR = 12
+
+func (service *ServiceAPI) GetReferenceDepositRates(ctx context.Context, req GetAllDollarColonesChangesRequest) *GetReferenceDepositRatesResponse {
+	referenceDepositRates, err := scrapByYears(ctx, service, req.DateFrom, req.DateTo, MAXIMUM_REFERENCE_DEPOSIT_RATE_YEAR, service.Scrapper.GetReferenceDepositRateByDates)
+	if err != nil {
+		return &GetReferenceDepositRatesResponse{
+			ReferenceDepositRates: nil,
+			Err:                   err,
+		}
+	}
+
+	sort.Slice(referenceDepositRates, func(i, j int) bool {
+		return referenceDepositRates[i].Date.After(referenceDepositRates[j].Date)
+	})
+
+	referenceDepositRates, meta := paginate(referenceDepositRates, func(item models.ReferenceDepositRate) time.Time { return item.Date }, req.Page)
+	return &GetReferenceDepositRatesResponse{
+		ReferenceDepositRates: referenceDepositRates,
+		Meta:                  meta,
+		Err:                   nil,
+	}
+}
+
+func (service *ServiceAPI) GetTodayReferenceDepositRate(ctx context.Context, req GetTodayExchangeRateRequest) *GetTodayReferenceDepositRateResponse {
+	date := utils.Now()
+	for i := 0; i < MAXIMUM_TRIES; i++ {
+		rate, err := service.Scrapper.GetReferenceDepositRateByDate(ctx, date)
+		if rate.Value != 0 {
+			return &GetTodayReferenceDepositRateResponse{
+				ReferenceDepositRate: rate,
+				Err:                  err,
+			}
+		}
+		date = date.AddDate(0, 0, -1)
+	}
+	_ = level.Error(service.logger).Log("msg", "error scrapping reference deposit rate by date", "date", date)
+	return &GetTodayReferenceDepositRateResponse{
+		ReferenceDepositRate: nil,
+		Err:                  errors.New("no results found"),
+	}
+}
+
+// USA_INFLATION_LOOKBACK_MONTHS is how many months of consumer price index are needed before the range
+// to calculate the interannual and monthly variations of its first month
+const USA_INFLATION_LOOKBACK_MONTHS = 13
+
+func (service *ServiceAPI) getUSAConsumerPriceIndexes(ctx context.Context, dateFrom time.Time, dateTo time.Time) (map[int]models.USAConsumerPriceIndex, error) {
+	result, err := service.Scrapper.GetUSAConsumerPriceIndexByDates(ctx, dateFrom.AddDate(0, -USA_INFLATION_LOOKBACK_MONTHS, 0), dateTo)
+	if err != nil {
+		_ = level.Debug(service.logger).Log("msg", "error scrapping USA consumer price index by dates",
+			"date_from", dateFrom, "date_to", dateTo, "error", err)
+		return nil, err
+	}
+
+	consumerPriceIndexes := map[int]models.USAConsumerPriceIndex{}
+	for _, consumerPriceIndex := range result {
+		consumerPriceIndexes[monthKey(consumerPriceIndex.Date)] = consumerPriceIndex
+	}
+	return consumerPriceIndexes, nil
+}
+
+func (service *ServiceAPI) calculateVariation(current models.USAConsumerPriceIndex, previous models.USAConsumerPriceIndex) float64 {
+	return ((current.Value - previous.Value) / previous.Value) * 100
+}
+
+// getUSAInflationRates calculates the variation of every month in the range against the consumer price
+// index published lagMonths before
+func (service *ServiceAPI) getUSAInflationRates(ctx context.Context, req GetAllDollarColonesChangesRequest, lagMonths int) ([]models.USAInflationRate, error) {
+	consumerPriceIndexes, err := service.getUSAConsumerPriceIndexes(ctx, req.DateFrom, req.DateTo)
+	if err != nil {
+		return nil, err
+	}
+
 	inflationRates := []models.USAInflationRate{}
-	// check if date is between dateFrom and dateTo
-	for _, inflationRate := range interanualInflationRates {
-		if (inflationRate.Date.After(req.DateFrom) || inflationRate.Date.Equal(req.DateFrom)) &&
-			(inflationRate.Date.Before(req.DateTo) || inflationRate.Date.Equal(req.DateTo)) {
-			inflationRates = append(inflationRates, inflationRate)
+	for key, consumerPriceIndex := range consumerPriceIndexes {
+		if consumerPriceIndex.Date.Before(req.DateFrom) || consumerPriceIndex.Date.After(req.DateTo) {
+			continue
+		}
+		previous, ok := consumerPriceIndexes[key-lagMonths]
+		if !ok || previous.Value == 0 {
+			continue
 		}
+		inflationRates = append(inflationRates, models.USAInflationRate{
+			Date:  consumerPriceIndex.Date,
+			Value: service.calculateVariation(consumerPriceIndex, previous),
+		})
 	}
 
 	sort.Slice(inflationRates, func(i, j int) bool {
 		return inflationRates[i].Date.After(inflationRates[j].Date)
 	})
+	return inflationRates, nil
+}
+
+func (service *ServiceAPI) GetUSAInflationRates(ctx context.Context, req GetAllDollarColonesChangesRequest) *GetUSAInflationRatesResponse {
+	inflationRates, err := service.getUSAInflationRates(ctx, req, 12)
+	if err != nil {
+		return &GetUSAInflationRatesResponse{
+			InflationRates: nil,
+			Err:            err,
+		}
+	}
 
+	inflationRates, meta := paginate(inflationRates, func(item models.USAInflationRate) time.Time { return item.Date }, req.Page)
 	return &GetUSAInflationRatesResponse{
 		InflationRates: inflationRates,
+		Meta:           meta,
 		Err:            nil,
 	}
 }
 
-func (service *ServiceAPI) GetUSAInflationRate(ctx context.Context, req GetTodayExchangeRateRequest) *GetTodayUSAInflationRateResponse {
-	date := time.Now()
-	result, err := service.Scrapper.GetUSAInflationRateByDates(date, date)
+func (service *ServiceAPI) GetUSAMonthlyInflationRates(ctx context.Context, req GetAllDollarColonesChangesRequest) *GetUSAInflationRatesResponse {
+	inflationRates, err := service.getUSAInflationRates(ctx, req, 1)
+	if err != nil {
+		return &GetUSAInflationRatesResponse{
+			InflationRates: nil,
+			Err:            err,
+		}
+	}
+
+	inflationRates, meta := paginate(inflationRates, func(item models.USAInflationRate) time.Time { return item.Date }, req.Page)
+	return &GetUSAInflationRatesResponse{
+		InflationRates: inflationRates,
+		Meta:           meta,
+		Err:            nil,
+	}
+}
 
+func (service *ServiceAPI) GetUSAConsumerPriceIndexes(ctx context.Context, req GetAllDollarColonesChangesRequest) *GetUSAConsumerPriceIndexesResponse {
+	result, err := service.Scrapper.GetUSAConsumerPriceIndexByDates(ctx, req.DateFrom, req.DateTo)
+	if err != nil {
+		_ = level.Debug(service.logger).Log("msg", "error scrapping USA consumer price index by dates",
+			"date_from", req.DateFrom, "date_to", req.DateTo, "error", err)
+		return &GetUSAConsumerPriceIndexesResponse{
+			ConsumerPriceIndexes: nil,
+			Err:                  err,
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Date.After(result[j].Date)
+	})
+
+	result, meta := paginate(result, func(item models.USAConsumerPriceIndex) time.Time { return item.Date }, req.Page)
+	return &GetUSAConsumerPriceIndexesResponse{
+		ConsumerPriceIndexes: result,
+		Meta:                 meta,
+		Err:                  nil,
+	}
+}
+
+func (service *ServiceAPI) GetUSAInflationRate(ctx context.Context, req GetTodayExchangeRateRequest) *GetTodayUSAInflationRateResponse {
+	// the consumer price index is published mid next month, so look a whole year back for the latest one
+	dateTo := utils.Now()
+	inflationRates, err := service.getUSAInflationRates(ctx, GetAllDollarColonesChangesRequest{
+		DateFrom: dateTo.AddDate(-1, 0, 0),
+		DateTo:   dateTo,
+	}, 12)
 	if err != nil {
 		return &GetTodayUSAInflationRateResponse{
 			InflationRate: nil,
@@ -710,19 +1049,16 @@ func (service *ServiceAPI) GetUSAInflationRate(ctx context.Context, req GetToday
 		}
 	}
 
-	inflationRate := models.USAInflationRate{}
-	lastResult := result[len(result)-1]
-	lastYearAgoInflationRate, err := service.getUSAInflationRateByDate(lastResult.Date.AddDate(-1, 0, 0), result)
-	if lastYearAgoInflationRate != nil && err == nil {
-		result := service.calculateInteranualInflationRate(lastResult, *lastYearAgoInflationRate)
-		inflationRate = models.USAInflationRate{
-			Date:  lastResult.Date,
-			Value: result,
+	if len(inflationRates) == 0 {
+		_ = level.Error(service.logger).Log("msg", "error scrapping USA inflation rate", "date", dateTo)
+		return &GetTodayUSAInflationRateResponse{
+			InflationRate: nil,
+			Err:           utils.ErrNotFound,
 		}
 	}
 
 	return &GetTodayUSAInflationRateResponse{
-		InflationRate: &inflationRate,
-		Err:           err,
+		InflationRate: &inflationRates[0],
+		Err:           nil,
 	}
 }
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+)
+
+// dailyScrapper answers an exchange rate for every day of the ranges and remembers the ranges
+type dailyScrapper struct {
+	fakeScrapper
+	mutex  sync.Mutex
+	ranges []models.DateRange
+}
+
+func (fake *dailyScrapper) GetDollarColonesChangeByDates(_ context.Context, dateFrom time.Time, dateTo time.Time, _ int64) ([]models.ExchangeRate, error) {
+	fake.mutex.Lock()
+	fake.ranges = append(fake.ranges, models.DateRange{DateFrom: dateFrom, DateTo: dateTo})
+	fake.mutex.Unlock()
+
+	exchangeRates := []models.ExchangeRate{}
+	for date := dateFrom; !date.After(dateTo); date = date.AddDate(0, 0, 1) {
+		exchangeRates = append(exchangeRates, models.ExchangeRate{Date: date, BuyPrice: 500, SalePrice: 510})
+	}
+	return exchangeRates, nil
+}
+
+func TestExchangeRatesScrapEveryDateOnce(t *testing.T) {
+	fake := &dailyScrapper{}
+	service := newTestService(fake)
+	dateFrom := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	dateTo := time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC)
+
+	response := service.GetDollarColonesChange(context.Background(), GetAllDollarColonesChangesRequest{DateFrom: dateFrom, DateTo: dateTo})
+	if response.Err != nil {
+		t.Fatal(response.Err)
+	}
+	if len(fake.ranges) != 3 {
+		t.Errorf("scrapped %d ranges, expected a month at a time: %+v", len(fake.ranges), fake.ranges)
+	}
+
+	days := int(dateTo.Sub(dateFrom).Hours()/24) + 1
+	if len(response.ExchangesRates) != days {
+		t.Fatalf("%d exchange rates, expected one per day, %d", len(response.ExchangesRates), days)
+	}
+	seen := map[time.Time]bool{}
+	for i, exchangeRate := range response.ExchangesRates {
+		if seen[exchangeRate.Date] {
+			t.Errorf("%s is repeated", exchangeRate.Date.Format("2006-01-02"))
+		}
+		seen[exchangeRate.Date] = true
+		if i > 0 && !exchangeRate.Date.Before(response.ExchangesRates[i-1].Date) {
+			t.Errorf("the exchange rates are not the newest first at %d", i)
+		}
+	}
+}
+
+func TestScrapStopsWhenTheRequestIsCancelled(t *testing.T) {
+	service := newTestService(&fakeScrapper{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	dateFrom := time.Date(2003, 1, 1, 0, 0, 0, 0, time.UTC)
+	dateTo := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	scraps := 0
+	scrap := func(context.Context, time.Time, time.Time) ([]int, error) {
+		scraps++
+		return []int{1}, nil
+	}
+	if _, err := scrapByYears(ctx, service, dateFrom, dateTo, 5, scrap); !errors.Is(err, context.Canceled) {
+		t.Errorf("scrapping by years answered %v", err)
+	}
+	if _, err := scrapByMonths(ctx, service, "test", dateFrom, dateTo, scrap); !errors.Is(err, context.Canceled) {
+		t.Errorf("scrapping by months answered %v", err)
+	}
+	if scraps != 0 {
+		t.Errorf("%d ranges were scrapped after the request was cancelled", scraps)
+	}
+}
+
+func TestScrapByMonthsDoesNotWaitForTheMonthsLoading(t *testing.T) {
+	service := newTestService(&fakeScrapper{})
+	ctx, cancel := context.WithCancel(context.Background())
+	release := make(chan struct{})
+	defer close(release)
+	scrap := func(ctx context.Context, _ time.Time, _ time.Time) ([]int, error) {
+		cancel()
+		<-release
+		return []int{1}, nil
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := scrapByMonths(ctx, service, "test", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), scrap)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("answered %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the cancelled request waited for the months loading")
+	}
+}
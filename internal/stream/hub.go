@@ -0,0 +1,196 @@
+package stream
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	kitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/configuration"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+)
+
+// SUBSCRIPTION_BUFFER is how many events a subscriber can fall behind before it is dropped, it resumes
+// from its last event when it reconnects
+const SUBSCRIPTION_BUFFER = 64
+
+// Hub sends the events to the subscribers of their series and keeps the last ones, so a client that
+// reconnects with the id of its last event does not miss anything
+type Hub struct {
+	mutex          sync.Mutex
+	lastID         uint64
+	history        []models.ObservationEvent
+	historySize    int
+	subscriptions  map[*Subscription]bool
+	maxSubscribers int
+	// perClient counts the subscriptions of every client, a client can not take the whole hub
+	perClient    map[string]int
+	maxPerClient int
+	closed       bool
+	subscribers  kitmetrics.Gauge
+}
+
+// Subscription receives the events of its series until it is closed, by the client or by the hub when
+// the client is too slow or the service shuts down
+type Subscription struct {
+	hub       *Hub
+	client    string
+	transport string
+	events    chan models.ObservationEvent
+	series    map[string]bool
+}
+
+// NewHub starts the ids at the current time in milliseconds, so the ids of a restarted service are
+// greater than the ones its clients saw before
+func NewHub(config configuration.StreamConfig, subscribers kitmetrics.Gauge) *Hub {
+	return &Hub{
+		lastID:         uint64(time.Now().UnixMilli()),
+		historySize:    config.History,
+		subscriptions:  map[*Subscription]bool{},
+		maxSubscribers: config.MaxSubscribers,
+		perClient:      map[string]int{},
+		maxPerClient:   config.MaxSubscribersPerClient,
+		subscribers:    subscribers,
+	}
+}
+
+// Subscribe returns the subscription and the events of its series after lastEventID that are still kept.
+// The replay and the subscription are taken together, no event falls between them. The client identifies
+// who subscribes, its API key or its IP, a limit of 0 per client lets it take the whole hub.
+func (hub *Hub) Subscribe(client string, transport string, series []string, lastEventID uint64) (*Subscription, []models.ObservationEvent, error) {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	if hub.closed || len(hub.subscriptions) >= hub.maxSubscribers {
+		return nil, nil, utils.ErrStreamFull
+	}
+	if hub.maxPerClient > 0 && hub.perClient[client] >= hub.maxPerClient {
+		return nil, nil, fmt.Errorf("%w: the limit is %d", utils.ErrStreamClientLimit, hub.maxPerClient)
+	}
+
+	subscription := &Subscription{
+		hub:       hub,
+		client:    client,
+		transport: transport,
+		events:    make(chan models.ObservationEvent, SUBSCRIPTION_BUFFER),
+		series:    map[string]bool{},
+	}
+	for _, name := range series {
+		subscription.series[name] = true
+	}
+	hub.subscriptions[subscription] = true
+	hub.perClient[client]++
+	hub.subscribers.With("transport", transport).Add(1)
+
+	replay := []models.ObservationEvent{}
+	if lastEventID > 0 {
+		for _, event := range hub.history {
+			if event.ID > lastEventID && subscription.series[event.Series] {
+				replay = append(replay, event)
+			}
+		}
+	}
+	return subscription, replay, nil
+}
+
+// Publish numbers the events and sends them to their subscribers. The subscribers that are too slow to
+// receive them are closed instead of blocking the others.
+func (hub *Hub) Publish(events ...models.ObservationEvent) {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	for _, event := range events {
+		hub.lastID++
+		event.ID = hub.lastID
+		hub.history = append(hub.history, event)
+		if len(hub.history) > hub.historySize {
+			hub.history = hub.history[len(hub.history)-hub.historySize:]
+		}
+
+		for subscription := range hub.subscriptions {
+			if !subscription.series[event.Series] {
+				continue
+			}
+			select {
+			case subscription.events <- event:
+			default:
+				hub.remove(subscription)
+			}
+		}
+	}
+}
+
+// Series returns the series with at least a subscriber, the only ones worth polling
+func (hub *Hub) Series() []string {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	seen := map[string]bool{}
+	for subscription := range hub.subscriptions {
+		for name := range subscription.series {
+			seen[name] = true
+		}
+	}
+	series := make([]string, 0, len(seen))
+	for name := range seen {
+		series = append(series, name)
+	}
+	sort.Strings(series)
+	return series
+}
+
+// Close ends every subscription and rejects the new ones, the streams finish before the server drains
+// its requests
+func (hub *Hub) Close() {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	hub.closed = true
+	for subscription := range hub.subscriptions {
+		hub.remove(subscription)
+	}
+}
+
+func (hub *Hub) remove(subscription *Subscription) {
+	if !hub.subscriptions[subscription] {
+		return
+	}
+	delete(hub.subscriptions, subscription)
+	if hub.perClient[subscription.client]--; hub.perClient[subscription.client] <= 0 {
+		delete(hub.perClient, subscription.client)
+	}
+	hub.subscribers.With("transport", subscription.transport).Add(-1)
+	close(subscription.events)
+}
+
+// Events is closed when the subscription ends
+func (subscription *Subscription) Events() <-chan models.ObservationEvent {
+	return subscription.events
+}
+
+// Add subscribes to more series, their past events are not replayed
+func (subscription *Subscription) Add(series ...string) {
+	subscription.hub.mutex.Lock()
+	defer subscription.hub.mutex.Unlock()
+	for _, name := range series {
+		subscription.series[name] = true
+	}
+}
+
+// Remove stops receiving the events of the series
+func (subscription *Subscription) Remove(series ...string) {
+	subscription.hub.mutex.Lock()
+	defer subscription.hub.mutex.Unlock()
+	for _, name := range series {
+		delete(subscription.series, name)
+	}
+}
+
+// Close ends the subscription, it can be called more than once
+func (subscription *Subscription) Close() {
+	subscription.hub.mutex.Lock()
+	defer subscription.hub.mutex.Unlock()
+	subscription.hub.remove(subscription)
+}
@@ -0,0 +1,48 @@
+package stream
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/configuration"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+)
+
+func TestSubscribeLimitsTheSubscriptionsOfAClient(t *testing.T) {
+	hub := NewHub(configuration.StreamConfig{History: 10, MaxSubscribers: 10, MaxSubscribersPerClient: 2}, discard.NewGauge())
+	series := []string{"tbp"}
+
+	first, _, err := hub.Subscribe("ip:1.2.3.4", "sse", series, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := hub.Subscribe("ip:1.2.3.4", "ws", series, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := hub.Subscribe("ip:1.2.3.4", "sse", series, 0); !errors.Is(err, utils.ErrStreamClientLimit) {
+		t.Errorf("the third subscription of the client answered %v", err)
+	}
+	if _, _, err := hub.Subscribe("key:other", "sse", series, 0); err != nil {
+		t.Errorf("another client could not subscribe: %s", err)
+	}
+
+	// a closed subscription, by the client or by the hub, frees its place
+	first.Close()
+	first.Close()
+	if _, _, err := hub.Subscribe("ip:1.2.3.4", "sse", series, 0); err != nil {
+		t.Errorf("the client could not subscribe after closing a subscription: %s", err)
+	}
+}
+
+func TestSubscribeWithoutLimitPerClient(t *testing.T) {
+	hub := NewHub(configuration.StreamConfig{History: 10, MaxSubscribers: 3}, discard.NewGauge())
+	for i := 0; i < 3; i++ {
+		if _, _, err := hub.Subscribe("ip:1.2.3.4", "sse", []string{"tbp"}, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, _, err := hub.Subscribe("ip:1.2.3.4", "sse", []string{"tbp"}, 0); !errors.Is(err, utils.ErrStreamFull) {
+		t.Errorf("the subscription over the hub limit answered %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package stream
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	kitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/services"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+)
+
+// Consumer receives the events of the poller and tells which series it needs, like the streams and the
+// webhooks
+type Consumer interface {
+	Series() []string
+	Publish(events ...models.ObservationEvent)
+}
+
+// Poller is the ingestion of new data: it scrapes the recent observations of the series its consumers need
+// and publishes the ones that are new or changed since the previous poll
+type Poller struct {
+	service   *services.ServiceAPI
+	consumers []Consumer
+	interval  time.Duration
+	events    kitmetrics.Counter
+	logger    log.Logger
+	// seen has the values of the last poll of every series, by date
+	seen map[string]map[string]float64
+}
+
+func NewPoller(service *services.ServiceAPI, interval time.Duration, events kitmetrics.Counter, logger log.Logger, consumers ...Consumer) *Poller {
+	return &Poller{
+		service:   service,
+		consumers: consumers,
+		interval:  interval,
+		events:    events,
+		logger:    logger,
+		seen:      map[string]map[string]float64{},
+	}
+}
+
+// Run polls until the context is cancelled
+func (poller *Poller) Run(ctx context.Context) {
+	ticker := time.NewTicker(poller.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			series := poller.series()
+			poller.forget(series)
+			for _, name := range series {
+				poller.poll(ctx, name)
+			}
+		}
+	}
+}
+
+// series returns the series of every consumer, each one is scraped once
+func (poller *Poller) series() []string {
+	seen := map[string]bool{}
+	series := []string{}
+	for _, consumer := range poller.consumers {
+		for _, name := range consumer.Series() {
+			if !seen[name] {
+				seen[name] = true
+				series = append(series, name)
+			}
+		}
+	}
+	sort.Strings(series)
+	return series
+}
+
+// forget drops the values of the series nobody needs anymore, like the ones of a hub that lost its last
+// subscriber. The observations published meanwhile are not news for the next subscriber, its first poll
+// only remembers them again.
+func (poller *Poller) forget(series []string) {
+	needed := make(map[string]bool, len(series))
+	for _, name := range series {
+		needed[name] = true
+	}
+	for name := range poller.seen {
+		if !needed[name] {
+			delete(poller.seen, name)
+		}
+	}
+}
+
+// poll compares the observations within the lookback of the series with the previous poll. The first
+// poll of a series only remembers them, they are not news.
+func (poller *Poller) poll(ctx context.Context, series string) {
+	dateTo := utils.Now()
+	response := poller.service.GetSeries(ctx, services.GetSeriesRequest{
+		Series:   series,
+		DateFrom: dateTo.AddDate(0, 0, -services.LookbackDays(series)),
+		DateTo:   dateTo,
+	})
+	if response.Err != nil {
+		// the scrape is retried on the next tick
+		_ = level.Warn(poller.logger).Log("msg", "error polling series", "series", series, "error", response.Err)
+		return
+	}
+
+	previous, polled := poller.seen[series]
+	current := make(map[string]float64, len(response.Observations))
+	events := []models.ObservationEvent{}
+	detectedAt := time.Now()
+	// the observations are oldest first, last is the one before the current one
+	var last *float64
+	for i, observation := range response.Observations {
+		date := observation.Date.Format(utils.DATE_FORMAT)
+		current[date] = observation.Value
+		before := last
+		last = &response.Observations[i].Value
+		if !polled {
+			continue
+		}
+
+		event := models.ObservationEvent{
+			Series:     series,
+			Date:       observation.Date,
+			Value:      observation.Value,
+			DetectedAt: detectedAt,
+		}
+		value, found := previous[date]
+		switch {
+		case !found:
+			event.Type = models.EventObservation
+			event.Previous = before
+		case value != observation.Value:
+			event.Type = models.EventRevision
+			event.Previous = &value
+		default:
+			continue
+		}
+		events = append(events, event)
+		poller.events.With("series", series, "type", event.Type).Add(1)
+	}
+	poller.seen[series] = current
+
+	if len(events) > 0 {
+		_ = level.Info(poller.logger).Log("msg", "new observations detected", "series", series, "events", len(events))
+		for _, consumer := range poller.consumers {
+			consumer.Publish(events...)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package stream
+
+import (
+	"testing"
+)
+
+func TestPollerForgetsTheSeriesWithoutSubscribers(t *testing.T) {
+	poller := &Poller{seen: map[string]map[string]float64{
+		"tbp":   {"2024/01/01": 6.5},
+		"prime": {"2024/01/01": 8.5},
+	}}
+
+	poller.forget([]string{"prime"})
+	if _, found := poller.seen["tbp"]; found {
+		t.Error("the values of a series without subscribers are kept, its next subscriber would get them as news")
+	}
+	if _, found := poller.seen["prime"]; !found {
+		t.Error("the values of a series with subscribers were dropped")
+	}
+
+	// the hub dropped to zero subscribers
+	poller.forget(nil)
+	if len(poller.seen) != 0 {
+		t.Errorf("%d series are kept without subscribers", len(poller.seen))
+	}
+}
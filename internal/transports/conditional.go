@@ -0,0 +1,76 @@
+package transports
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// conditionalRequest has the validators sent by the clients that already have a response
+type conditionalRequest struct {
+	ifNoneMatch     string
+	ifModifiedSince string
+}
+
+// entityTag identifies the exact bytes of a response, so it changes with the format and the language too
+func entityTag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:12]) + `"`
+}
+
+// matchesEntityTag compares with the weak comparison of RFC 9110, the W/ prefix is ignored
+func matchesEntityTag(ifNoneMatch string, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// isNotModified follows RFC 9110: If-Modified-Since is ignored when If-None-Match is sent
+func (conditional conditionalRequest) isNotModified(etag string, lastModified time.Time) bool {
+	if conditional.ifNoneMatch != "" {
+		return matchesEntityTag(conditional.ifNoneMatch, etag)
+	}
+	if conditional.ifModifiedSince == "" || lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(conditional.ifModifiedSince)
+	if err != nil {
+		return false
+	}
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// writeCacheable sets the validators and the max-age of a successful response. It answers 304 without a
+// body when the client already has it.
+func writeCacheable(w http.ResponseWriter, info routeInfo, body []byte, lastModified time.Time) error {
+	etag := entityTag(body)
+	header := w.Header()
+	header.Set("ETag", etag)
+	header.Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(info.maxAge.Seconds())))
+	header.Add("Vary", "Accept, Accept-Language")
+
+	// the observations are dated at noon, today's can not be modified in the future
+	if now := time.Now(); lastModified.After(now) {
+		lastModified = now
+	}
+	if !lastModified.IsZero() {
+		header.Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if info.conditional.isNotModified(etag, lastModified) {
+		header.Del("Content-Type")
+		header.Del("Content-Disposition")
+		w.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+
+	_, err := w.Write(body)
+	return err
+}
@@ -0,0 +1,114 @@
+package transports
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/services"
+)
+
+// encodeWith encodes the response of a request with the headers of the client
+func encodeWith(t *testing.T, response interface{}, headers map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := httptest.NewRequest("GET", "/exchange_rates", nil)
+	for name, value := range headers {
+		r.Header.Set(name, value)
+	}
+	ctx := populateRouteInfo("exchange_rates", time.Hour)(context.Background(), r)
+	w := httptest.NewRecorder()
+	if err := encodeResponse(ctx, w, response); err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func TestEncodeResponseSetsTheValidators(t *testing.T) {
+	w := encodeWith(t, map[string]float64{"sale": 512.5}, nil)
+
+	if w.Code != http.StatusOK || w.Body.Len() == 0 {
+		t.Fatalf("answered %d with %q", w.Code, w.Body.String())
+	}
+	if etag := w.Header().Get("ETag"); etag != entityTag(w.Body.Bytes()) {
+		t.Errorf("the etag %s is not the one of the body", etag)
+	}
+	if cacheControl := w.Header().Get("Cache-Control"); cacheControl != "public, max-age=3600" {
+		t.Errorf("the cache control is %q", cacheControl)
+	}
+
+	other := encodeWith(t, map[string]float64{"sale": 513}, nil)
+	if other.Header().Get("ETag") == w.Header().Get("ETag") {
+		t.Error("two different responses have the same etag")
+	}
+}
+
+func TestEncodeResponseNotModified(t *testing.T) {
+	response := map[string]float64{"sale": 512.5}
+	etag := encodeWith(t, response, nil).Header().Get("ETag")
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		expected int
+	}{
+		{"same etag", map[string]string{"If-None-Match": etag}, http.StatusNotModified},
+		{"weak etag in a list", map[string]string{"If-None-Match": `"other", W/` + etag}, http.StatusNotModified},
+		{"any etag", map[string]string{"If-None-Match": "*"}, http.StatusNotModified},
+		{"another etag", map[string]string{"If-None-Match": `"other"`}, http.StatusOK},
+		// the responses without observations have no Last-Modified to compare with
+		{"modified since without last modified", map[string]string{"If-Modified-Since": time.Now().UTC().Format(http.TimeFormat)}, http.StatusOK},
+	}
+	for _, test := range tests {
+		w := encodeWith(t, response, test.headers)
+		if w.Code != test.expected {
+			t.Errorf("%s: answered %d, expected %d", test.name, w.Code, test.expected)
+		}
+		if test.expected == http.StatusNotModified && (w.Body.Len() != 0 || w.Header().Get("Content-Type") != "") {
+			t.Errorf("%s: the 304 has a body %q or a content type", test.name, w.Body.String())
+		}
+	}
+}
+
+func TestEncodeResponseLastModifiedIsTheLastObservation(t *testing.T) {
+	response := services.GetAllDollarColonesChangesResponse{ExchangesRates: []models.ExchangeRate{
+		{Date: time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC), SalePrice: 512.5},
+		{Date: time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC), SalePrice: 510},
+	}}
+	w := encodeWith(t, response, nil)
+	lastModified := w.Header().Get("Last-Modified")
+	if lastModified != "Wed, 03 Jan 2024 12:00:00 GMT" {
+		t.Fatalf("the last modified is %q", lastModified)
+	}
+
+	if w := encodeWith(t, response, map[string]string{"If-Modified-Since": lastModified}); w.Code != http.StatusNotModified {
+		t.Errorf("answered %d to a client with the last observation", w.Code)
+	}
+	before := "Tue, 02 Jan 2024 12:00:00 GMT"
+	if w := encodeWith(t, response, map[string]string{"If-Modified-Since": before}); w.Code != http.StatusOK {
+		t.Errorf("answered %d to a client without the last observation", w.Code)
+	}
+}
+
+func TestIsNotModifiedSince(t *testing.T) {
+	lastModified := time.Date(2024, 1, 2, 12, 0, 0, 500, time.UTC)
+	tests := []struct {
+		name        string
+		conditional conditionalRequest
+		expected    bool
+	}{
+		{"same second", conditionalRequest{ifModifiedSince: lastModified.Format(http.TimeFormat)}, true},
+		{"later", conditionalRequest{ifModifiedSince: lastModified.Add(time.Hour).Format(http.TimeFormat)}, true},
+		{"earlier", conditionalRequest{ifModifiedSince: lastModified.Add(-time.Hour).Format(http.TimeFormat)}, false},
+		{"invalid date", conditionalRequest{ifModifiedSince: "yesterday"}, false},
+		// If-Modified-Since is ignored when If-None-Match is sent
+		{"etag takes precedence", conditionalRequest{ifNoneMatch: `"other"`, ifModifiedSince: lastModified.Format(http.TimeFormat)}, false},
+	}
+	for _, test := range tests {
+		if notModified := test.conditional.isNotModified(`"etag"`, lastModified); notModified != test.expected {
+			t.Errorf("%s: not modified is %v", test.name, notModified)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package transports
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+)
+
+// parseFields reads the comma separated list of the fields param, e.g. fields=date,sale
+func parseFields(param string) []string {
+	fields := []string{}
+	for _, field := range strings.Split(param, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// selectFields keeps only the fields requested of every observation in the data of a json response,
+// the meta and the responses without data are not changed
+func selectFields(body []byte, fields []string) ([]byte, error) {
+	if len(fields) == 0 {
+		return body, nil
+	}
+	var response map[string]json.RawMessage
+	if err := json.Unmarshal(body, &response); err != nil {
+		return body, nil
+	}
+	data, ok := response["data"]
+	if !ok {
+		return body, nil
+	}
+
+	var observations []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &observations); err != nil {
+		return body, nil
+	}
+	selected := make([]map[string]json.RawMessage, len(observations))
+	for index, observation := range observations {
+		selected[index] = map[string]json.RawMessage{}
+		for _, field := range fields {
+			value, ok := observation[field]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s", utils.ErrFields, field)
+			}
+			selected[index][field] = value
+		}
+	}
+
+	if response["data"], ok = marshal(selected); !ok {
+		return body, nil
+	}
+	selectedBody, ok := marshal(response)
+	if !ok {
+		return body, nil
+	}
+	return append(selectedBody, '\n'), nil
+}
+
+func marshal(value interface{}) (json.RawMessage, bool) {
+	encoded, err := json.Marshal(value)
+	return encoded, err == nil
+}
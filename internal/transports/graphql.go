@@ -0,0 +1,71 @@
+package transports
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	gql "github.com/jrodolforojas/libertadfinanciera-backend/internal/graphql"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+)
+
+// MAXIMUM_GRAPHQL_BODY is the size of the largest query accepted, in bytes
+const MAXIMUM_GRAPHQL_BODY = 1 << 20
+
+type graphQLBody struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// decodeGraphQLRequest accepts the query in the params of a GET, or in the body of a POST as JSON or
+// as application/graphql
+func decodeGraphQLRequest(executor *gql.Executor) func(context.Context, *http.Request) (interface{}, error) {
+	return func(_ context.Context, r *http.Request) (interface{}, error) {
+		body := graphQLBody{}
+		switch r.Method {
+		case http.MethodGet:
+			body.Query = r.URL.Query().Get("query")
+			body.OperationName = r.URL.Query().Get("operationName")
+			if variables := r.URL.Query().Get("variables"); variables != "" {
+				if err := json.Unmarshal([]byte(variables), &body.Variables); err != nil {
+					return nil, fmt.Errorf("%w: variables: %s", utils.ErrDecodeRequest, err)
+				}
+			}
+		case http.MethodPost:
+			reader := http.MaxBytesReader(nil, r.Body, MAXIMUM_GRAPHQL_BODY)
+			mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if mediaType == "application/graphql" {
+				query, err := io.ReadAll(reader)
+				if err != nil {
+					return nil, fmt.Errorf("%w: %s", utils.ErrDecodeRequest, err)
+				}
+				body.Query = string(query)
+			} else if err := json.NewDecoder(reader).Decode(&body); err != nil {
+				return nil, fmt.Errorf("%w: %s", utils.ErrDecodeRequest, err)
+			}
+		}
+
+		if body.Query == "" {
+			return nil, fmt.Errorf("%w: the query is missing", utils.ErrDecodeRequest)
+		}
+		return executor.Parse(body.Query, body.OperationName, body.Variables), nil
+	}
+}
+
+// encodeGraphQLResponse answers 200 even when the query fails, the errors are in the result
+func encodeGraphQLResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	return json.NewEncoder(w).Encode(response)
+}
+
+// isGraphQLFailed counts the queries with errors as failed, even if some of their fields were resolved
+func isGraphQLFailed(response interface{}) bool {
+	result, ok := response.(*graphql.Result)
+	return ok && result.HasErrors()
+}
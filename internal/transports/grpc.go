@@ -0,0 +1,410 @@
+package transports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	kitgrpc "github.com/go-kit/kit/transport/grpc"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/configuration"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/metrics"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/middleware"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/pb"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/ratelimit"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/services"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/tracing"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// streamChunkYears is the size of the ranges scraped by StreamSeries, the client receives the first
+// observations while the rest are scraped
+const streamChunkYears = 1
+
+// reflectionPrefix is the service that describes the others, it never needs a key like /openapi.json
+const reflectionPrefix = "/grpc.reflection."
+
+type grpcServer struct {
+	pb.UnimplementedIndicatorsServer
+	getSeries    kitgrpc.Handler
+	getLatest    kitgrpc.Handler
+	convert      kitgrpc.Handler
+	streamSeries endpoint.Endpoint
+}
+
+// MakeGRPCServer serves the same endpoints of the HTTP transport, charged to the same limiter when it is
+// not nil. The metrics and the traces of its routes are prefixed with grpc_.
+func MakeGRPCServer(s *services.ServiceAPI, serviceMetrics *metrics.Metrics, limiter *ratelimit.Limiter, config configuration.ServerConfig) pb.IndicatorsServer {
+	endpoints := services.MakeEndpoints(s)
+	options := []kitgrpc.ServerOption{
+		kitgrpc.ServerBefore(populateRequestID),
+	}
+	instrumentGRPC := func(name string) endpoint.Middleware {
+		return instrument(serviceMetrics, limiter, config.Limits, name)
+	}
+
+	return &grpcServer{
+		getSeries: kitgrpc.NewServer(
+			instrumentGRPC("grpc_get_series")(endpoints.GetSeries),
+			limitRange(config.Address.GRPCMaxRangeYears, decodeGRPCGetSeriesRequest),
+			encodeGRPCGetSeriesResponse,
+			options...,
+		),
+		getLatest: kitgrpc.NewServer(
+			instrumentGRPC("grpc_get_latest")(endpoints.GetLatestObservation),
+			decodeGRPCGetLatestRequest,
+			encodeGRPCGetLatestResponse,
+			options...,
+		),
+		convert: kitgrpc.NewServer(
+			instrumentGRPC("grpc_convert")(endpoints.Convert),
+			decodeGRPCConvertRequest,
+			encodeGRPCConvertResponse,
+			options...,
+		),
+		streamSeries: instrumentGRPC("grpc_stream_series")(endpoints.GetSeries),
+	}
+}
+
+// instrument wraps the endpoints in the same order of the HTTP routes, a rejected call is measured but
+// not traced
+func instrument(serviceMetrics *metrics.Metrics, limiter *ratelimit.Limiter, limits configuration.RateLimitConfig, name string) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		next = tracing.EndpointMiddleware(name, isFailed)(next)
+		if limiter != nil {
+			next = ratelimit.EndpointMiddleware(limiter, limits)(next)
+		}
+		return metrics.EndpointMiddleware(serviceMetrics, name, isFailed)(next)
+	}
+}
+
+// limitRange rejects the ranges of GetSeries longer than maxYears, StreamSeries sends the longer ones
+// and charges every year it scrapes
+func limitRange(maxYears int, decode kitgrpc.DecodeRequestFunc) kitgrpc.DecodeRequestFunc {
+	return func(ctx context.Context, grpcReq interface{}) (interface{}, error) {
+		request, err := decode(ctx, grpcReq)
+		if err != nil || maxYears <= 0 {
+			return request, err
+		}
+		seriesRequest := request.(services.GetSeriesRequest)
+		if seriesRequest.DateFrom.Before(seriesRequest.DateTo.AddDate(-maxYears, 0, 0)) {
+			return nil, fmt.Errorf("%w: the range is longer than %d years, use StreamSeries", utils.ErrInvalidDateRange, maxYears)
+		}
+		return request, nil
+	}
+}
+
+// grpcInterceptors authenticate every call like the HTTP middleware, the client in the context is charged
+// by the rate limits of the endpoints
+func grpcInterceptors(keys middleware.KeyLookup, config configuration.ServerConfig) []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			ctx, err := authenticateGRPC(ctx, info.FullMethod, keys, config)
+			if err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.ChainStreamInterceptor(func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			ctx, err := authenticateGRPC(stream.Context(), info.FullMethod, keys, config)
+			if err != nil {
+				return err
+			}
+			return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+		}),
+	}
+}
+
+// authenticatedStream carries the client of the call in its context
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *authenticatedStream) Context() context.Context {
+	return stream.ctx
+}
+
+// authenticateGRPC reads the key of the x-api-key metadata or the bearer token of authorization, the same
+// names of the HTTP headers
+func authenticateGRPC(ctx context.Context, method string, keys middleware.KeyLookup, config configuration.ServerConfig) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ip := grpcClientIP(ctx, md, config.Limits.TrustedProxies)
+	if strings.HasPrefix(method, reflectionPrefix) {
+		return utils.WithClient(ctx, utils.Client{IP: ip}), nil
+	}
+
+	key := firstValue(md, middleware.HeaderAPIKey)
+	if authorization := firstValue(md, "authorization"); key == "" && strings.HasPrefix(authorization, "Bearer ") {
+		key = strings.TrimSpace(strings.TrimPrefix(authorization, "Bearer "))
+	}
+	client, err := middleware.Identify(ctx, keys, config.Auth.Required, key, ip)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return utils.WithClient(ctx, client), nil
+}
+
+// grpcClientIP is the address appended to x-forwarded-for by the trusted proxies, like the HTTP routes, or
+// the address of the connection
+func grpcClientIP(ctx context.Context, md metadata.MD, trustedProxies int) string {
+	if forwarded := middleware.ForwardedClientIP(md.Get("x-forwarded-for"), trustedProxies); forwarded != "" {
+		return forwarded
+	}
+	remote, ok := peer.FromContext(ctx)
+	if !ok || remote.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(remote.Addr.String())
+	if err != nil {
+		return remote.Addr.String()
+	}
+	return host
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// populateRequestID keeps the request id sent by the client in the metadata, like the HTTP header
+func populateRequestID(ctx context.Context, md metadata.MD) context.Context {
+	if requestID := firstValue(md, middleware.HeaderRequestID); requestID != "" {
+		return utils.WithRequestID(ctx, requestID)
+	}
+	return ctx
+}
+
+func (server *grpcServer) GetSeries(ctx context.Context, req *pb.GetSeriesRequest) (*pb.GetSeriesResponse, error) {
+	_, response, err := server.getSeries.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return response.(*pb.GetSeriesResponse), nil
+}
+
+func (server *grpcServer) GetLatest(ctx context.Context, req *pb.GetLatestRequest) (*pb.Observation, error) {
+	_, response, err := server.getLatest.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return response.(*pb.Observation), nil
+}
+
+func (server *grpcServer) Convert(ctx context.Context, req *pb.ConvertRequest) (*pb.ConvertResponse, error) {
+	_, response, err := server.convert.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return response.(*pb.ConvertResponse), nil
+}
+
+// StreamSeries scrapes the range a year at a time and sends the observations of every year before
+// scraping the next one, a long range does not have to fit in a single response. Every year is charged
+// to the rate limits before it is scraped, the stream ends when the client runs out of tokens.
+func (server *grpcServer) StreamSeries(req *pb.GetSeriesRequest, stream pb.Indicators_StreamSeriesServer) error {
+	ctx := stream.Context()
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		ctx = populateRequestID(ctx, md)
+	}
+
+	request, err := decodeGRPCGetSeriesRequest(ctx, req)
+	if err != nil {
+		return grpcError(err)
+	}
+	seriesRequest := request.(services.GetSeriesRequest)
+
+	for dateFrom := seriesRequest.DateFrom; !dateFrom.After(seriesRequest.DateTo); {
+		if err := ctx.Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+
+		dateTo := dateFrom.AddDate(streamChunkYears, 0, -1)
+		if dateTo.After(seriesRequest.DateTo) {
+			dateTo = seriesRequest.DateTo
+		}
+		response, err := server.streamSeries(ctx, services.GetSeriesRequest{
+			Series:   seriesRequest.Series,
+			DateFrom: dateFrom,
+			DateTo:   dateTo,
+		})
+		if err != nil {
+			return grpcError(err)
+		}
+		seriesResponse := response.(*services.GetSeriesResponse)
+		if seriesResponse.Err != nil {
+			return grpcError(seriesResponse.Err)
+		}
+		for i := range seriesResponse.Observations {
+			if err := stream.Send(encodeObservation(&seriesResponse.Observations[i])); err != nil {
+				return err
+			}
+		}
+		dateFrom = dateTo.AddDate(0, 0, 1)
+	}
+	return nil
+}
+
+// grpcError uses the same classification of the HTTP status codes
+func grpcError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	}
+	switch codeFrom(err) {
+	case http.StatusBadRequest:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case http.StatusNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	case http.StatusUnauthorized:
+		return status.Error(codes.Unauthenticated, err.Error())
+	case http.StatusTooManyRequests:
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// timestampDate reads a date in the timezone of the service, the zero time when it is missing
+func timestampDate(timestamp *timestamppb.Timestamp) (time.Time, error) {
+	if timestamp == nil {
+		return time.Time{}, nil
+	}
+	if err := timestamp.CheckValid(); err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s", utils.ErrDateInvalidFormat, err)
+	}
+	return timestamp.AsTime().In(utils.Location()), nil
+}
+
+func decodeGRPCGetSeriesRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.GetSeriesRequest)
+	series, err := services.ParseSeries(req.GetSeries())
+	if err != nil {
+		return nil, err
+	}
+
+	dateTo, err := timestampDate(req.GetDateTo())
+	if err != nil {
+		return nil, fmt.Errorf("date_to: %w", err)
+	}
+	if dateTo.IsZero() {
+		dateTo = utils.Now()
+	}
+	dateFrom, err := timestampDate(req.GetDateFrom())
+	if err != nil {
+		return nil, fmt.Errorf("date_from: %w", err)
+	}
+	if dateFrom.IsZero() {
+		dateFrom = dateTo.AddDate(0, 0, -utils.DEFAULT_DAYS_TO_GO_BACK)
+	}
+	if err := utils.ValidateDateRange(dateFrom, dateTo); err != nil {
+		return nil, err
+	}
+
+	return services.GetSeriesRequest{
+		Series:   series,
+		DateFrom: dateFrom,
+		DateTo:   dateTo,
+	}, nil
+}
+
+func decodeGRPCGetLatestRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.GetLatestRequest)
+	series, err := services.ParseSeries(req.GetSeries())
+	if err != nil {
+		return nil, err
+	}
+	return services.GetLatestObservationRequest{Series: series}, nil
+}
+
+func decodeGRPCConvertRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.ConvertRequest)
+	if req.GetAmount() <= 0 {
+		return nil, utils.ErrInvalidAmount
+	}
+
+	from := strings.ToUpper(req.GetFrom())
+	to := strings.ToUpper(req.GetTo())
+	if !isCurrency(from) || !isCurrency(to) {
+		return nil, utils.ErrCurrency
+	}
+
+	date, err := timestampDate(req.GetDate())
+	if err != nil {
+		return nil, fmt.Errorf("date: %w", err)
+	}
+	if !date.IsZero() {
+		if err := utils.ValidateDateRange(date, date); err != nil {
+			return nil, err
+		}
+	}
+
+	return services.GetConvertRequest{
+		Amount: req.GetAmount(),
+		From:   from,
+		To:     to,
+		Date:   date,
+	}, nil
+}
+
+func encodeObservation(observation *models.Observation) *pb.Observation {
+	return &pb.Observation{
+		Series: observation.Series,
+		Date:   timestamppb.New(observation.Date),
+		Value:  observation.Value,
+	}
+}
+
+func encodeGRPCGetSeriesResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(*services.GetSeriesResponse)
+	if resp.Err != nil {
+		return nil, resp.Err
+	}
+
+	observations := make([]*pb.Observation, 0, len(resp.Observations))
+	for i := range resp.Observations {
+		observations = append(observations, encodeObservation(&resp.Observations[i]))
+	}
+	return &pb.GetSeriesResponse{
+		Series:       resp.Series,
+		Observations: observations,
+	}, nil
+}
+
+func encodeGRPCGetLatestResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(*services.GetLatestObservationResponse)
+	if resp.Err != nil {
+		return nil, resp.Err
+	}
+	return encodeObservation(resp.Observation), nil
+}
+
+func encodeGRPCConvertResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(*services.GetConvertResponse)
+	if resp.Err != nil {
+		return nil, resp.Err
+	}
+	return &pb.ConvertResponse{
+		Amount: resp.Conversion.Amount,
+		From:   resp.Conversion.From,
+		To:     resp.Conversion.To,
+		Result: resp.Conversion.Result,
+		Rate:   resp.Conversion.Rate,
+		Date:   timestamppb.New(resp.Conversion.Date),
+	}, nil
+}
@@ -0,0 +1,37 @@
+package transports
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestGRPCClientIP(t *testing.T) {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 50051}})
+
+	tests := []struct {
+		name           string
+		forwarded      []string
+		trustedProxies int
+		expected       string
+	}{
+		{"without proxies", []string{"203.0.113.7"}, 0, "10.0.0.2"},
+		{"without forwarded", nil, 1, "10.0.0.2"},
+		// the client can send any address in the leftmost entries, only the proxy appends the last one
+		{"spoofed by the client", []string{"1.2.3.4, 203.0.113.7"}, 1, "203.0.113.7"},
+		{"two proxies", []string{"1.2.3.4, 203.0.113.7, 10.0.0.1"}, 2, "203.0.113.7"},
+		{"several values", []string{"1.2.3.4", "203.0.113.7"}, 1, "203.0.113.7"},
+	}
+	for _, test := range tests {
+		md := metadata.MD{}
+		for _, value := range test.forwarded {
+			md.Append("x-forwarded-for", value)
+		}
+		if ip := grpcClientIP(ctx, md, test.trustedProxies); ip != test.expected {
+			t.Errorf("%s: the client ip is %q, expected %q", test.name, ip, test.expected)
+		}
+	}
+}
@@ -0,0 +1,358 @@
+package transports
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/health"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/openapi"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/services"
+)
+
+const (
+	tagExchangeRates = "Exchange rates"
+	tagInterestRates = "Interest rates"
+	tagInflation     = "Inflation"
+	tagCalculators   = "Calculators"
+	tagComparison    = "Comparison"
+	tagGraphQL       = "GraphQL"
+	tagStreams       = "Streams"
+	tagWebhooks      = "Webhooks"
+	tagOperations    = "Operations"
+)
+
+const dateFormats = "YYYY/MM/DD, YYYY-MM-DD or RFC 3339"
+
+// operationDoc documents a route of the route table, the response is a zero value of what its endpoint
+// returns
+type operationDoc struct {
+	summary  string
+	tag      string
+	params   [][]openapi.Parameter
+	response interface{}
+}
+
+func queryParam(name string, description string, schema *openapi.Schema) openapi.Parameter {
+	return openapi.Parameter{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+func requiredParam(param openapi.Parameter) openapi.Parameter {
+	param.Required = true
+	return param
+}
+
+func stringSchema() *openapi.Schema {
+	return &openapi.Schema{Type: "string"}
+}
+
+func enumSchema(values ...string) *openapi.Schema {
+	return &openapi.Schema{Type: "string", Enum: values}
+}
+
+var (
+	dateRangeParams = []openapi.Parameter{
+		queryParam("date_from", "First day of the range, in format "+dateFormats+". By default, 30 days before date_to", stringSchema()),
+		queryParam("from", "Alias of date_from", stringSchema()),
+		queryParam("date_to", "Last day of the range, in format "+dateFormats+". By default, today", stringSchema()),
+		queryParam("to", "Alias of date_to", stringSchema()),
+		queryParam("last", "Range up to today: 90d, 12w, 6m, 2y or ytd. Can not be combined with the other dates", &openapi.Schema{Type: "string", Pattern: `^([0-9]+[dwmy]|ytd)$`}),
+		queryParam("ytd", "Range from the first day of the year, same as last=ytd", &openapi.Schema{Type: "boolean"}),
+		queryParam("since", "Range up to today from a year (2020), a month (2020-06) or a date", stringSchema()),
+	}
+	paginationParams = []openapi.Parameter{
+		queryParam("limit", "Maximum number of observations, all of them by default", &openapi.Schema{Type: "integer"}),
+		queryParam("offset", "Observations skipped", &openapi.Schema{Type: "integer"}),
+		queryParam("cursor", "next_cursor of the previous page", stringSchema()),
+		queryParam("order", "Order of the dates, newest first by default", enumSchema(models.OrderAscending, models.OrderDescending)),
+		queryParam("fields", "Comma separated fields of the observations in data, e.g. date,sale", stringSchema()),
+	}
+	exportParams = []openapi.Parameter{
+		queryParam("format", "Format of the response, it takes precedence over the Accept header", enumSchema(formatJSON, formatCSV, formatXLSX)),
+		queryParam("lang", "Language of the headers of the exported files, it takes precedence over the Accept-Language header", enumSchema("es", "en")),
+	}
+	filterParams = []openapi.Parameter{
+		queryParam("periocity", "Periodicity of the observations", enumSchema("monthly", "quarterly", "biannual", "annual", "quinquennium")),
+	}
+	listParams = [][]openapi.Parameter{dateRangeParams, paginationParams, exportParams}
+)
+
+func makeRouteDocs() map[string]operationDoc {
+	today := [][]openapi.Parameter{exportParams}
+	filter := [][]openapi.Parameter{filterParams, exportParams}
+	return map[string]operationDoc{
+		"exchange_rates":                {"Dollar to colones exchange rates", tagExchangeRates, listParams, services.GetAllDollarColonesChangesResponse{}},
+		"exchange_rates_today":          {"Dollar to colones exchange rate of today", tagExchangeRates, today, services.GetTodayExchangeRateResponse{}},
+		"exchange_rates_filter":         {"Dollar to colones exchange rates by periodicity", tagExchangeRates, filter, services.GetAllDollarColonesChangesResponse{}},
+		"monex_exchange_rates":          {"MONEX exchange rates", tagExchangeRates, listParams, services.GetMonexExchangeRatesResponse{}},
+		"monex_exchange_rates_today":    {"MONEX exchange rate of today", tagExchangeRates, today, services.GetTodayMonexExchangeRateResponse{}},
+		"euro_exchange_rates":           {"Euro exchange rates", tagExchangeRates, listParams, services.GetEuroExchangeRatesResponse{}},
+		"euro_exchange_rates_today":     {"Euro exchange rate of today", tagExchangeRates, today, services.GetTodayEuroExchangeRateResponse{}},
+		"basic_passive_rates":           {"Basic passive rates (TBP)", tagInterestRates, listParams, services.GetBasicPassiveRatesResponse{}},
+		"basic_passive_rates_today":     {"Basic passive rate (TBP) of today", tagInterestRates, today, services.GetTodayBasicPassiveRateResponse{}},
+		"reference_deposit_rates":       {"Reference deposit rates in dollars (TRI)", tagInterestRates, listParams, services.GetReferenceDepositRatesResponse{}},
+		"reference_deposit_rates_today": {"Reference deposit rate in dollars (TRI) of today", tagInterestRates, today, services.GetTodayReferenceDepositRateResponse{}},
+		"monetary_policy_rates":         {"Monetary policy rates", tagInterestRates, listParams, services.GetMonetaryPolicyRatesResponse{}},
+		"monetary_policy_rates_today":   {"Monetary policy rate of today", tagInterestRates, today, services.GetTodayMonetaryPolicyRateResponse{}},
+		"prime_rates":                   {"Prime rates", tagInterestRates, listParams, services.GetPrimeRatesResponse{}},
+		"prime_rates_today":             {"Prime rate of today", tagInterestRates, today, services.GetTodayPrimeRateResponse{}},
+		"treasury_rates_usa":            {"Treasury rates of the USA", tagInterestRates, listParams, services.GetTreasuryRatesUSAResponse{}},
+		"treasury_rates_usa_today":      {"Treasury rate of the USA of today", tagInterestRates, today, services.GetTodayTreasuryRateUSAResponse{}},
+		"treasury_yield_curve": {"Treasury yield curve of the USA", tagInterestRates, [][]openapi.Parameter{{
+			queryParam("date", "Day of the curve, in format "+dateFormats+". By default, today", stringSchema()),
+		}, exportParams}, services.GetTreasuryYieldCurveResponse{}},
+		"treasury_yield_series": {"Treasury yields of the USA of a maturity", tagInterestRates, [][]openapi.Parameter{{
+			requiredParam(queryParam("maturity", "Maturity of the yields, like 3M, 2Y or 10Y", &openapi.Schema{Type: "string", Pattern: maturityRegexp.String()})),
+		}, dateRangeParams, paginationParams, exportParams}, services.GetTreasuryYieldSeriesResponse{}},
+		"inflation_rates_cr":          {"Inflation rates of Costa Rica", tagInflation, listParams, services.GetCostaRicaInflationRatesResponse{}},
+		"inflation_rates_cr_filter":   {"Inflation rates of Costa Rica by periodicity", tagInflation, filter, services.GetCostaRicaInflationRatesResponse{}},
+		"inflation_rates_cr_today":    {"Inflation rate of Costa Rica of this month", tagInflation, today, services.GetTodayCostaRicaInflationRateResponse{}},
+		"inflation_rates_usa":         {"Inflation rates of the USA", tagInflation, listParams, services.GetUSAInflationRatesResponse{}},
+		"inflation_rates_usa_today":   {"Inflation rate of the USA of this month", tagInflation, today, services.GetTodayUSAInflationRateResponse{}},
+		"inflation_rates_usa_monthly": {"Month over month inflation rates of the USA", tagInflation, listParams, services.GetUSAInflationRatesResponse{}},
+		"consumer_price_index_usa":    {"Consumer price index of the USA", tagInflation, listParams, services.GetUSAConsumerPriceIndexesResponse{}},
+		"inflation_calculator": {"Amount adjusted by the inflation between two dates", tagCalculators, [][]openapi.Parameter{{
+			requiredParam(queryParam("amount", "Amount to adjust, a positive number", &openapi.Schema{Type: "number"})),
+			queryParam("currency", "Currency of the amount, CRC by default", enumSchema(models.CurrencyColones, models.CurrencyDollars)),
+			requiredParam(queryParam("date_from", "Date of the amount, in format "+dateFormats, stringSchema())),
+			queryParam("date_to", "Date to adjust the amount to, in format "+dateFormats+". By default, today", stringSchema()),
+		}, exportParams}, services.GetInflationCalculatorResponse{}},
+		"loan_calculator": {"Amortization schedule of a loan or a savings plan", tagCalculators, [][]openapi.Parameter{{
+			queryParam("type", "Kind of calculation, loan by default", enumSchema(models.CalculatorLoan, models.CalculatorSavings)),
+			requiredParam(queryParam("principal", "Amount of the loan, a positive number", &openapi.Schema{Type: "number"})),
+			requiredParam(queryParam("term", "Number of months, up to 600", &openapi.Schema{Type: "integer"})),
+			queryParam("margin", "Percentage points added to the reference rate", &openapi.Schema{Type: "number"}),
+			requiredParam(queryParam("rate", "Reference rate", enumSchema(models.ReferenceRateBasicPassive, models.ReferenceRatePrime))),
+			queryParam("frequency", "How often the payments are made, monthly by default", enumSchema("monthly", "quarterly", "biannual", "annual")),
+			queryParam("date_from", "Start of a back-test with the historical rates, in format "+dateFormats+". By default, a projection with the latest rate", stringSchema()),
+		}, exportParams}, services.GetLoanCalculatorResponse{}},
+		"compare": {"Several indicators aligned on the same dates", tagComparison, [][]openapi.Parameter{{
+			requiredParam(queryParam("series", fmt.Sprintf("Comma separated list of up to %d series: %s", services.MAXIMUM_COMPARED_SERIES, strings.Join(services.ComparableSeries(), ", ")), stringSchema())),
+			queryParam("freq", "Frequency of the rows, monthly by default", enumSchema(models.FrequencyDaily, models.FrequencyWeekly, models.FrequencyMonthly)),
+			queryParam("frequency", "Alias of freq", enumSchema(models.FrequencyDaily, models.FrequencyWeekly, models.FrequencyMonthly)),
+		}, dateRangeParams, exportParams}, services.GetCompareSeriesResponse{}},
+		"convert": {"Amount converted between colones, dollars and euros", tagCalculators, [][]openapi.Parameter{{
+			requiredParam(queryParam("amount", "Amount to convert, a positive number", &openapi.Schema{Type: "number"})),
+			requiredParam(queryParam("from", "Currency of the amount", enumSchema(models.CurrencyColones, models.CurrencyDollars, models.CurrencyEuros))),
+			requiredParam(queryParam("to", "Currency to convert the amount to", enumSchema(models.CurrencyColones, models.CurrencyDollars, models.CurrencyEuros))),
+			queryParam("date", "Date of the exchange rates, in format "+dateFormats+". By default, the latest ones", stringSchema()),
+		}, exportParams}, services.GetConvertResponse{}},
+	}
+}
+
+func errorResponse(description string) openapi.Response {
+	return openapi.Response{
+		Description: description,
+		Content:     map[string]openapi.MediaType{"application/json": {Schema: openapi.Ref("Error")}},
+	}
+}
+
+func responseRef(name string) openapi.Response {
+	return openapi.Response{Ref: "#/components/responses/" + name}
+}
+
+// makeOpenAPI documents every route of the route table and of the webhooks in every version, the legacy
+// paths as deprecated, GraphQL, the streams and the operations. The routes without documentation are left out of the document and returned as problems,
+// with the documentation without route, a document out of date should not stop the service. The tests
+// fail on any problem.
+func makeOpenAPI(routes []route, webhookRoutes []webhookRoute) (*openapi.Document, []error) {
+	docs := makeRouteDocs()
+	schemas := openapi.NewSchemas()
+	schemas.Add("Error", &openapi.Schema{
+		Type:       "object",
+		Properties: map[string]*openapi.Schema{"error": stringSchema()},
+		Required:   []string{"error"},
+	})
+
+	document := &openapi.Document{
+		OpenAPI: openapi.Version,
+		Info: openapi.Info{
+			Title:       "Libertad Financiera API",
+			Description: "Economic indicators of Costa Rica and the USA published by the BCCR.",
+			Version:     versions[len(versions)-1].name,
+		},
+		Paths: map[string]openapi.PathItem{},
+		Components: openapi.Components{
+			Responses: map[string]openapi.Response{
+				"BadRequest":          errorResponse("The params are not valid"),
+				"Unauthorized":        errorResponse("The API key is missing or not valid"),
+				"NotFound":            errorResponse("There is no data for the request"),
+				"InternalServerError": errorResponse("The data could not be scraped"),
+				"TooManyRequests": {
+					Description: "The rate limit of the client was exceeded",
+					Headers: map[string]openapi.Header{
+						"Retry-After": {Description: "Seconds until the request can be retried", Schema: &openapi.Schema{Type: "integer"}},
+					},
+					Content: map[string]openapi.MediaType{"application/json": {Schema: openapi.Ref("Error")}},
+				},
+			},
+			SecuritySchemes: map[string]openapi.SecurityScheme{
+				"ApiKeyHeader": {Type: "apiKey", In: "header", Name: "X-API-Key"},
+				"BearerAuth":   {Type: "http", Scheme: "bearer", Description: "The API key as a bearer token"},
+			},
+		},
+		// the keys are optional unless AUTH_REQUIRED is set
+		Security: []map[string][]string{{}, {"ApiKeyHeader": {}}, {"BearerAuth": {}}},
+		Tags: []openapi.Tag{
+			{Name: tagExchangeRates},
+			{Name: tagInterestRates},
+			{Name: tagInflation},
+			{Name: tagCalculators},
+			{Name: tagComparison},
+			{Name: tagGraphQL, Description: "Queries of several indicators in one request, not versioned"},
+			{Name: tagStreams, Description: "Observations pushed as they are published or revised"},
+			{Name: tagWebhooks, Description: "Observations delivered to the URLs of the client"},
+			{Name: tagOperations, Description: "Health, metrics and documentation, not versioned"},
+		},
+	}
+
+	problems := []error{}
+	documented := map[string]bool{}
+	for _, route := range routes {
+		doc, found := docs[route.name]
+		if !found {
+			problems = append(problems, fmt.Errorf("openapi: route %s (%s) is not documented", route.name, route.path))
+			continue
+		}
+		documented[route.name] = true
+
+		data, err := schemas.Of(doc.response)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("openapi: route %s: %w", route.name, err))
+			continue
+		}
+		var params []openapi.Parameter
+		for _, group := range doc.params {
+			params = append(params, group...)
+		}
+
+		for _, v := range versions {
+			document.Paths[v.prefix()+route.path] = openapi.PathItem{Get: makeOperation(v.name+"_"+route.name, doc, params, data, false)}
+		}
+		if route.legacyPath != "" {
+			document.Paths[route.legacyPath] = openapi.PathItem{Get: makeOperation("legacy_"+route.name, doc, params, data, true)}
+		}
+	}
+	for name := range docs {
+		if !documented[name] {
+			problems = append(problems, fmt.Errorf("openapi: route %s is documented but not registered", name))
+		}
+	}
+
+	problems = append(problems, addWebhookPaths(document, schemas, webhookRoutes)...)
+	if err := addStreamPaths(document, schemas); err != nil {
+		problems = append(problems, err)
+	}
+	if err := addGraphQLPath(document, schemas); err != nil {
+		problems = append(problems, err)
+	}
+
+	readiness, err := schemas.Of(health.Readiness{})
+	if err != nil {
+		problems = append(problems, fmt.Errorf("openapi: readiness: %w", err))
+		readiness = &openapi.Schema{Type: "object"}
+	}
+	document.Paths["/healthz"] = operationsPath("liveness", "Whether the process is answering", "application/json", &openapi.Schema{
+		Type:       "object",
+		Properties: map[string]*openapi.Schema{"status": enumSchema(health.StatusOK)},
+	})
+	document.Paths["/readyz"] = operationsPath("readiness", "Whether the dependencies are reachable, 503 when they are not", "application/json", readiness)
+	document.Paths["/metrics"] = operationsPath("metrics", "Metrics in the Prometheus text format", "text/plain", stringSchema())
+	document.Paths["/openapi.json"] = operationsPath("openapi", "This document", "application/json", &openapi.Schema{Type: "object"})
+	addDocsPaths(document)
+
+	document.Components.Schemas = schemas.Components()
+	return document, problems
+}
+
+func makeOperation(operationID string, doc operationDoc, params []openapi.Parameter, data *openapi.Schema, deprecated bool) *openapi.Operation {
+	description := ""
+	if deprecated {
+		description = "Deprecated alias of the path under /api/v1, it answers with the Deprecation, Sunset and Link headers."
+	}
+	return &openapi.Operation{
+		OperationID: operationID,
+		Summary:     doc.summary,
+		Description: description,
+		Tags:        []string{doc.tag},
+		Parameters:  params,
+		Deprecated:  deprecated,
+		Responses: map[string]openapi.Response{
+			"200": {
+				Description: "The data requested, or a spreadsheet of it with the format param",
+				Headers: map[string]openapi.Header{
+					"ETag":          {Schema: stringSchema()},
+					"Last-Modified": {Description: "Date of the latest observation", Schema: stringSchema()},
+				},
+				Content: map[string]openapi.MediaType{
+					"application/json": {Schema: data},
+					contentTypeCSV:     {Schema: stringSchema()},
+					contentTypeXLSX:    {Schema: &openapi.Schema{Type: "string", Format: "binary"}},
+				},
+			},
+			"304": {Description: "Nothing changed since the ETag of If-None-Match or the date of If-Modified-Since"},
+			"400": responseRef("BadRequest"),
+			"401": responseRef("Unauthorized"),
+			"404": responseRef("NotFound"),
+			"429": responseRef("TooManyRequests"),
+			"500": responseRef("InternalServerError"),
+		},
+	}
+}
+
+func operationsPath(operationID string, summary string, contentType string, schema *openapi.Schema) openapi.PathItem {
+	return openapi.PathItem{Get: &openapi.Operation{
+		OperationID: operationID,
+		Summary:     summary,
+		Tags:        []string{tagOperations},
+		Responses: map[string]openapi.Response{
+			"200": {Description: summary, Content: map[string]openapi.MediaType{contentType: {Schema: schema}}},
+		},
+	}}
+}
+
+//go:embed docs.html
+var docsPage []byte
+
+//go:generate curl -sSfL -o assets/redoc.standalone.js https://cdn.redoc.ly/redoc/v2.1.5/bundles/redoc.standalone.js
+
+// redocPath serves the Redoc bundle from the binary, the documentation does not depend on a CDN
+const redocPath = "/docs/redoc.standalone.js"
+
+// redocCDN is the same version of the bundle, for the binaries built before it was generated
+const redocCDN = "https://cdn.redoc.ly/redoc/v2.1.5/bundles/redoc.standalone.js"
+
+//go:embed assets
+var assets embed.FS
+
+// handleOpenAPI serves the document encoded once, it only changes with the route table
+func handleOpenAPI(document *openapi.Document) (http.Handler, error) {
+	body, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		w.Write(body)
+	}), nil
+}
+
+func handleDocs(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(docsPage)
+}
+
+func handleRedoc(w http.ResponseWriter, r *http.Request) {
+	bundle, err := assets.ReadFile("assets/redoc.standalone.js")
+	if err != nil {
+		http.Redirect(w, r, redocCDN, http.StatusFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/javascript; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.Write(bundle)
+}
@@ -0,0 +1,195 @@
+package transports
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/openapi"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/services"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/webhooks"
+)
+
+// webhookDoc documents a route of the webhooks, a nil response answers 204 without body
+type webhookDoc struct {
+	summary  string
+	request  interface{}
+	response interface{}
+}
+
+func makeWebhookDocs() map[string]webhookDoc {
+	return map[string]webhookDoc{
+		"create_webhook":           {"Register a webhook, the response is the only one with its secret", webhooks.CreateWebhookRequest{}, webhooks.CreateWebhookResponse{}},
+		"list_webhooks":            {"Webhooks of the client", nil, []models.Webhook{}},
+		"get_webhook_dead_letters": {"Payloads of the client that could not be delivered", nil, []models.DeadLetter{}},
+		"get_webhook":              {"Webhook of the client", nil, models.Webhook{}},
+		"delete_webhook":           {"Delete a webhook of the client", nil, nil},
+		"test_webhook":             {"Deliver a test payload to a webhook", nil, webhooks.DeliveryResult{}},
+	}
+}
+
+func pathParam(name string, description string) openapi.Parameter {
+	return openapi.Parameter{Name: name, In: "path", Description: description, Required: true, Schema: stringSchema()}
+}
+
+func jsonContent(schema *openapi.Schema) map[string]openapi.MediaType {
+	return map[string]openapi.MediaType{"application/json": {Schema: schema}}
+}
+
+// addWebhookPaths documents the webhook routes in every version, the methods of a path share its item
+func addWebhookPaths(document *openapi.Document, schemas *openapi.Schemas, routes []webhookRoute) []error {
+	docs := makeWebhookDocs()
+	problems := []error{}
+	documented := map[string]bool{}
+	for _, route := range routes {
+		doc, found := docs[route.name]
+		if !found {
+			problems = append(problems, fmt.Errorf("openapi: webhook route %s (%s %s) is not documented", route.name, route.method, route.path))
+			continue
+		}
+		documented[route.name] = true
+
+		operation := &openapi.Operation{
+			Summary: doc.summary,
+			Tags:    []string{tagWebhooks},
+			Responses: map[string]openapi.Response{
+				"400": responseRef("BadRequest"),
+				"401": responseRef("Unauthorized"),
+				"404": responseRef("NotFound"),
+				"429": responseRef("TooManyRequests"),
+			},
+		}
+		if strings.Contains(route.path, "{id}") {
+			operation.Parameters = []openapi.Parameter{pathParam("id", "Id of the webhook")}
+		}
+		if doc.request != nil {
+			request, err := schemas.Of(doc.request)
+			if err != nil {
+				problems = append(problems, fmt.Errorf("openapi: webhook route %s: %w", route.name, err))
+				continue
+			}
+			operation.RequestBody = &openapi.RequestBody{Required: true, Content: jsonContent(request)}
+		}
+		if doc.response == nil {
+			operation.Responses[fmt.Sprint(route.status)] = openapi.Response{Description: doc.summary}
+		} else {
+			response, err := schemas.Of(doc.response)
+			if err != nil {
+				problems = append(problems, fmt.Errorf("openapi: webhook route %s: %w", route.name, err))
+				continue
+			}
+			operation.Responses[fmt.Sprint(route.status)] = openapi.Response{Description: doc.summary, Content: jsonContent(response)}
+		}
+
+		for _, v := range versions {
+			versioned := *operation
+			versioned.OperationID = v.name + "_" + route.name
+			path := v.prefix() + route.path
+			document.Paths[path] = document.Paths[path].WithOperation(route.method, &versioned)
+		}
+	}
+	for name := range docs {
+		if !documented[name] {
+			problems = append(problems, fmt.Errorf("openapi: webhook route %s is documented but not registered", name))
+		}
+	}
+	return problems
+}
+
+// addStreamPaths documents the Server-Sent Events and the WebSockets of every version
+func addStreamPaths(document *openapi.Document, schemas *openapi.Schemas) error {
+	event, err := schemas.Of(models.ObservationEvent{})
+	if err != nil {
+		return fmt.Errorf("openapi: streams: %w", err)
+	}
+	series := fmt.Sprintf("Comma separated list of series: %s", strings.Join(services.ComparableSeries(), ", "))
+	lastEventID := queryParam("last_event_id", "Id of the last event received, the stream resumes after it. Same as the Last-Event-ID header", &openapi.Schema{Type: "integer"})
+
+	for _, v := range versions {
+		document.Paths[v.prefix()+"/stream"] = openapi.PathItem{Get: &openapi.Operation{
+			OperationID: v.name + "_stream",
+			Summary:     "Server-Sent Events of the observations published or revised",
+			Tags:        []string{tagStreams},
+			Parameters:  []openapi.Parameter{requiredParam(queryParam("series", series, stringSchema())), lastEventID},
+			Responses: map[string]openapi.Response{
+				"200": {
+					Description: "A text/event-stream, the data of every event is an observation event",
+					Content:     map[string]openapi.MediaType{"text/event-stream": {Schema: event}},
+				},
+				"400": responseRef("BadRequest"),
+				"401": responseRef("Unauthorized"),
+				"429": errorResponse("The client has too many streams open"),
+				"503": errorResponse("There are too many subscribers"),
+			},
+		}}
+		document.Paths[v.prefix()+"/stream/ws"] = openapi.PathItem{Get: &openapi.Operation{
+			OperationID: v.name + "_stream_ws",
+			Summary:     "WebSocket of the observations published or revised",
+			Description: `Every message is an observation event. The client can send {"type": "subscribe", "series": [...]} and {"type": "unsubscribe", "series": [...]} while connected.`,
+			Tags:        []string{tagStreams},
+			Parameters:  []openapi.Parameter{queryParam("series", series, stringSchema()), lastEventID},
+			Responses: map[string]openapi.Response{
+				"101": {Description: "The connection is upgraded to a WebSocket"},
+				"400": responseRef("BadRequest"),
+				"401": responseRef("Unauthorized"),
+				"429": errorResponse("The client has too many streams open"),
+				"503": errorResponse("There are too many subscribers"),
+			},
+		}}
+	}
+	return nil
+}
+
+// addGraphQLPath documents the endpoint of the queries, the schema is documented by its introspection
+func addGraphQLPath(document *openapi.Document, schemas *openapi.Schemas) error {
+	body, err := schemas.Of(graphQLBody{})
+	if err != nil {
+		return fmt.Errorf("openapi: graphql: %w", err)
+	}
+	result := &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"data":   {Type: "object"},
+			"errors": {Type: "array", Items: &openapi.Schema{Type: "object"}},
+		},
+	}
+	responses := map[string]openapi.Response{
+		"200": {Description: "The result of the query, with the errors of the fields that failed", Content: jsonContent(result)},
+		"400": responseRef("BadRequest"),
+		"401": responseRef("Unauthorized"),
+		"429": responseRef("TooManyRequests"),
+	}
+	document.Paths["/graphql"] = openapi.PathItem{
+		Get: &openapi.Operation{
+			OperationID: "graphql_get",
+			Summary:     "GraphQL query in the params",
+			Tags:        []string{tagGraphQL},
+			Parameters: []openapi.Parameter{
+				requiredParam(queryParam("query", "The query", stringSchema())),
+				queryParam("operationName", "Operation of the query to execute", stringSchema()),
+				queryParam("variables", "Variables of the query as a JSON object", stringSchema()),
+			},
+			Responses: responses,
+		},
+		Post: &openapi.Operation{
+			OperationID: "graphql_post",
+			Summary:     "GraphQL query in the body",
+			Tags:        []string{tagGraphQL},
+			RequestBody: &openapi.RequestBody{
+				Required: true,
+				Content: map[string]openapi.MediaType{
+					"application/json":    {Schema: body},
+					"application/graphql": {Schema: stringSchema()},
+				},
+			},
+			Responses: responses,
+		},
+	}
+	return nil
+}
+
+// addDocsPaths documents this documentation, the page and the Redoc bundle it loads
+func addDocsPaths(document *openapi.Document) {
+	document.Paths["/docs"] = operationsPath("docs", "Documentation of the API rendered with Redoc", "text/html", stringSchema())
+	document.Paths[redocPath] = operationsPath("redoc", "Redoc bundle of the documentation", "application/javascript", stringSchema())
+}
@@ -0,0 +1,139 @@
+package transports
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/log"
+	"github.com/gorilla/mux"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/configuration"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/metrics"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/services"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/stream"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/webhooks"
+)
+
+// makeTestRouter registers the routes of the server with the default configuration and without dependencies
+func makeTestRouter(t *testing.T) *mux.Router {
+	t.Helper()
+	config, err := configuration.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := services.NewService(log.NewNopLogger(), nil, nil, discard.NewHistogram())
+	hub := stream.NewHub(config.Stream, discard.NewGauge())
+	webhookService := webhooks.NewService(webhooks.NewMemoryStore(10), nil, config.Webhooks)
+
+	router, err := makeRouter(context.Background(), config, service, metrics.NewDiscard(), nil, nil, nil, hub, webhookService, log.NewNopLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return router
+}
+
+func TestEveryRouteIsDocumented(t *testing.T) {
+	routes := makeRoutes(services.MakeEndpoints(nil))
+	docs := makeRouteDocs()
+
+	registered := map[string]bool{}
+	for _, route := range routes {
+		registered[route.name] = true
+		if _, found := docs[route.name]; !found {
+			t.Errorf("route %s (%s) has no entry in makeRouteDocs", route.name, route.path)
+		}
+	}
+	for name := range docs {
+		if !registered[name] {
+			t.Errorf("makeRouteDocs documents %s but the route table has no such route", name)
+		}
+	}
+}
+
+func TestOpenAPIHasEveryPath(t *testing.T) {
+	routes := makeRoutes(services.MakeEndpoints(nil))
+	document, problems := makeOpenAPI(routes, makeWebhookRoutes(webhooks.MakeEndpoints(nil)))
+	for _, problem := range problems {
+		t.Error(problem)
+	}
+
+	for _, route := range routes {
+		for _, v := range versions {
+			if path, found := document.Paths[v.prefix()+route.path]; !found || path.Get == nil {
+				t.Errorf("the document has no GET %s", v.prefix()+route.path)
+			}
+		}
+		if route.legacyPath == "" {
+			continue
+		}
+		if path, found := document.Paths[route.legacyPath]; !found || path.Get == nil || !path.Get.Deprecated {
+			t.Errorf("the document has no deprecated GET %s", route.legacyPath)
+		}
+	}
+
+	if _, err := handleOpenAPI(document); err != nil {
+		t.Fatalf("the document can not be encoded: %s", err)
+	}
+}
+
+func TestOpenAPIDocumentsEveryRouteOfTheRouter(t *testing.T) {
+	router := makeTestRouter(t)
+	document, problems := makeOpenAPI(makeRoutes(services.MakeEndpoints(nil)), makeWebhookRoutes(webhooks.MakeEndpoints(nil)))
+	for _, problem := range problems {
+		t.Error(problem)
+	}
+
+	registered := map[string]bool{}
+	err := router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return err
+		}
+		for _, method := range methods {
+			registered[method+" "+path] = true
+			item, found := document.Paths[path]
+			if !found || item.Operation(method) == nil {
+				t.Errorf("%s %s is served but not documented", method, path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for path, item := range document.Paths {
+		for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodDelete} {
+			if item.Operation(method) != nil && !registered[method+" "+path] {
+				t.Errorf("%s %s is documented but not served", method, path)
+			}
+		}
+	}
+}
+
+func TestDocsDoNotLoadScriptsFromOtherHosts(t *testing.T) {
+	router := makeTestRouter(t)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `src="`+redocPath+`"`) {
+		t.Fatalf("the docs answered %d loading %q", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, redocPath, nil))
+	if _, err := assets.ReadFile("assets/redoc.standalone.js"); err == nil {
+		if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/javascript; charset=utf-8" {
+			t.Errorf("the embedded bundle answered %d with %q", w.Code, w.Header().Get("Content-Type"))
+		}
+	} else if w.Code != http.StatusFound || w.Header().Get("Location") != redocCDN {
+		t.Errorf("without the bundle answered %d to %q", w.Code, w.Header().Get("Location"))
+	}
+}
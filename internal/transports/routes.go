@@ -0,0 +1,153 @@
+package transports
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/configuration"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/export"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/services"
+)
+
+// cadence is how often the indicator of a route gets published, the responses are cached accordingly
+type cadence int
+
+const (
+	cadenceDaily cadence = iota
+	cadenceWeekly
+	cadenceMonthly
+)
+
+// route is a GET endpoint of the API. The name identifies the indicator in the exported file names, the
+// path is relative to the prefix of each version and the legacy path is the one served before the
+// versions, it is kept as a deprecated alias of v1. The routes added after the versions have none.
+type route struct {
+	name       string
+	path       string
+	legacyPath string
+	cadence    cadence
+	endpoint   endpoint.Endpoint
+	decoder    httptransport.DecodeRequestFunc
+}
+
+// ttl returns how long the responses of a cadence are cached
+func (c cadence) ttl(config configuration.CacheConfig) time.Duration {
+	switch c {
+	case cadenceWeekly:
+		return config.WeeklyTTL
+	case cadenceMonthly:
+		return config.MonthlyTTL
+	default:
+		return config.DailyTTL
+	}
+}
+
+func makeRoutes(endpoints services.Endpoints) []route {
+	return []route{
+		{"exchange_rates", "/exchange_rates", "/exchange_rates", cadenceDaily, endpoints.GetAllDolarColonesChanges, decodeGetAllDolarColonesChangesRequest},
+		{"exchange_rates_today", "/exchange_rates/today", "/exchange_rates/today", cadenceDaily, endpoints.GetTodayExchangeRate, decodeTodayExchangeRateRequest},
+		{"exchange_rates_filter", "/exchange_rates/filter", "/exchange_rates/filter", cadenceDaily, endpoints.GetExchangeRatesByFilter, decodeGetDataByFilterRequest(services.EXCHANGE_RATES_HISTORY_FROM)},
+		{"basic_passive_rates", "/country_interest_rates/cr", "/country_interes_rates/cr", cadenceWeekly, endpoints.GetBasicPassiveRates, decodeGetAllDolarColonesChangesRequest},
+		{"basic_passive_rates_today", "/country_interest_rates/cr/today", "/country_interes_rates/cr/today", cadenceWeekly, endpoints.GetTodayBasicPassiveRate, decodeTodayExchangeRateRequest},
+		{"treasury_rates_usa", "/country_interest_rates/usa", "/country_interes_rates/usa", cadenceDaily, endpoints.GetTreasuryRatesUSA, decodeGetAllDolarColonesChangesRequest},
+		{"treasury_rates_usa_today", "/country_interest_rates/usa/today", "/country_interes_rates/usa/today", cadenceDaily, endpoints.GetTreasuryRateUSA, decodeTodayExchangeRateRequest},
+		{"treasury_yield_curve", "/country_interest_rates/usa/curve", "", cadenceDaily, endpoints.GetTreasuryYieldCurve, decodeTreasuryYieldCurveRequest},
+		{"treasury_yield_series", "/country_interest_rates/usa/curve/series", "", cadenceDaily, endpoints.GetTreasuryYieldSeries, decodeTreasuryYieldSeriesRequest},
+		{"monetary_policy_rates", "/monetary_policy_rates", "/monetary_policy_rates", cadenceDaily, endpoints.GetMonetaryPolicyRates, decodeGetAllDolarColonesChangesRequest},
+		{"monetary_policy_rates_today", "/monetary_policy_rates/today", "/monetary_policy_rates/today", cadenceDaily, endpoints.GetTodayMonetaryPolicyRate, decodeTodayExchangeRateRequest},
+		{"prime_rates", "/prime_rates", "/prime_rates", cadenceDaily, endpoints.GetPrimeRates, decodeGetAllDolarColonesChangesRequest},
+		{"prime_rates_today", "/prime_rates/today", "/prime_rates/today", cadenceDaily, endpoints.GetPrimeRate, decodeTodayExchangeRateRequest},
+		{"inflation_rates_cr", "/inflation_rates/cr", "/inflation_rates/cr", cadenceMonthly, endpoints.GetCostaRicaInflationRates, decodeGetAllDolarColonesChangesRequest},
+		{"inflation_rates_cr_filter", "/inflation_rates/cr/filter", "/inflation_rates/cr/filter", cadenceMonthly, endpoints.GetCostaRicaInflationRatesByFilter, decodeGetDataByFilterRequest(services.COSTA_RICA_INFLATION_HISTORY_FROM)},
+		{"inflation_rates_cr_today", "/inflation_rates/cr/today", "/inflation_rates/cr/today", cadenceMonthly, endpoints.GetCostaRicaInflationRate, decodeTodayExchangeRateRequest},
+		{"inflation_rates_usa", "/inflation_rates/usa", "/inflation_rates/usa", cadenceMonthly, endpoints.GetUSAInflationRates, decodeGetAllDolarColonesChangesRequest},
+		{"inflation_rates_usa_today", "/inflation_rates/usa/today", "/inflation_rates/usa/today", cadenceMonthly, endpoints.GetUSAInflationRate, decodeTodayExchangeRateRequest},
+		{"inflation_rates_usa_monthly", "/inflation_rates/usa/monthly", "", cadenceMonthly, endpoints.GetUSAMonthlyInflationRates, decodeGetAllDolarColonesChangesRequest},
+		{"consumer_price_index_usa", "/inflation_rates/usa/cpi", "", cadenceMonthly, endpoints.GetUSAConsumerPriceIndexes, decodeGetAllDolarColonesChangesRequest},
+		{"monex_exchange_rates", "/exchange_rates/monex", "", cadenceDaily, endpoints.GetMonexExchangeRates, decodeGetAllDolarColonesChangesRequest},
+		{"monex_exchange_rates_today", "/exchange_rates/monex/today", "", cadenceDaily, endpoints.GetTodayMonexExchangeRate, decodeTodayExchangeRateRequest},
+		{"euro_exchange_rates", "/exchange_rates/euro", "", cadenceDaily, endpoints.GetEuroExchangeRates, decodeGetAllDolarColonesChangesRequest},
+		{"euro_exchange_rates_today", "/exchange_rates/euro/today", "", cadenceDaily, endpoints.GetTodayEuroExchangeRate, decodeTodayExchangeRateRequest},
+		{"reference_deposit_rates", "/country_interest_rates/cr/tri", "", cadenceWeekly, endpoints.GetReferenceDepositRates, decodeGetAllDolarColonesChangesRequest},
+		{"reference_deposit_rates_today", "/country_interest_rates/cr/tri/today", "", cadenceWeekly, endpoints.GetTodayReferenceDepositRate, decodeTodayExchangeRateRequest},
+		{"inflation_calculator", "/calculators/inflation", "", cadenceDaily, endpoints.GetInflationAdjustedAmount, decodeInflationCalculatorRequest},
+		{"loan_calculator", "/calculators/loan", "", cadenceDaily, endpoints.GetLoanSchedule, decodeLoanCalculatorRequest},
+		{"compare", "/compare", "", cadenceDaily, endpoints.CompareSeries, decodeCompareSeriesRequest},
+		{"convert", "/convert", "", cadenceDaily, endpoints.Convert, decodeConvertRequest},
+	}
+}
+
+type contextKey int
+
+const routeInfoKey contextKey = iota
+
+// routeInfo is what encodeResponse needs to know about the request to negotiate the response format and
+// answer conditional requests
+type routeInfo struct {
+	name        string
+	format      string
+	language    string
+	maxAge      time.Duration
+	fields      []string
+	conditional conditionalRequest
+}
+
+const (
+	formatJSON = "json"
+	formatCSV  = "csv"
+	formatXLSX = "xlsx"
+)
+
+const (
+	contentTypeCSV  = "text/csv"
+	contentTypeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// negotiateFormat prefers the format query param over the Accept header
+func negotiateFormat(query url.Values, accept string) string {
+	switch query.Get("format") {
+	case formatCSV:
+		return formatCSV
+	case formatXLSX:
+		return formatXLSX
+	case formatJSON:
+		return formatJSON
+	}
+
+	for _, mediaType := range strings.Split(accept, ",") {
+		switch strings.TrimSpace(strings.Split(mediaType, ";")[0]) {
+		case contentTypeCSV:
+			return formatCSV
+		case contentTypeXLSX:
+			return formatXLSX
+		case "application/json":
+			return formatJSON
+		}
+	}
+	return formatJSON
+}
+
+func populateRouteInfo(name string, maxAge time.Duration) httptransport.RequestFunc {
+	return func(ctx context.Context, r *http.Request) context.Context {
+		language := r.URL.Query().Get("lang")
+		if language == "" {
+			language = r.Header.Get("Accept-Language")
+		}
+		return context.WithValue(ctx, routeInfoKey, routeInfo{
+			name:     name,
+			format:   negotiateFormat(r.URL.Query(), r.Header.Get("Accept")),
+			language: export.Language(language),
+			maxAge:   maxAge,
+			fields:   parseFields(r.URL.Query().Get("fields")),
+			conditional: conditionalRequest{
+				ifNoneMatch:     r.Header.Get("If-None-Match"),
+				ifModifiedSince: r.Header.Get("If-Modified-Since"),
+			},
+		})
+	}
+}
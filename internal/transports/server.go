@@ -2,19 +2,33 @@ package transports
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/go-kit/kit/log/level"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/alerts"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/auth"
 	"github.com/jrodolforojas/libertadfinanciera-backend/internal/configuration"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/health"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/metrics"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/pb"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/ratelimit"
 	"github.com/jrodolforojas/libertadfinanciera-backend/internal/repositories/supabase"
 	"github.com/jrodolforojas/libertadfinanciera-backend/internal/services"
 	"github.com/jrodolforojas/libertadfinanciera-backend/internal/services/scrapper"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/stream"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/tracing"
 	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/webhooks"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
 )
 
 // WebServer has the logic to start the microservice
@@ -28,16 +42,35 @@ func (ws *WebServer) StartServer() {
 		panic(err)
 	}
 
-	logger := utils.NewLogger()
+	logger, closeLogs, err := utils.NewLogger(config.Log)
+	if err != nil {
+		panic(err)
+	}
+	defer closeLogs()
 	_ = level.Debug(logger).Log("msg", "service started")
 
-	ctx := context.Background()
+	if err := utils.SetTimezone(config.Timezone); err != nil {
+		panic(err)
+	}
+
+	// ctx is cancelled once the requests in flight are drained, the background work stops with it
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	shutdownTracing, err := tracing.Init(ctx, config.Tracing)
+	if err != nil {
+		panic(err)
+	}
 
 	supabaseClient := supabase.InitSupabase(config.Database.SupabaseUrl, config.Database.SupabaseKey)
 
 	_ = level.Debug(logger).Log("msg", "supabase client initialized")
 
-	scrapper := scrapper.NewBCCRScrapper(logger, config.Scrapper)
+	serviceMetrics := metrics.New()
+
+	var bccrScrapper scrapper.Scrapper = scrapper.NewBCCRScrapper(utils.NewSampledLogger(logger, config.Log), config.Scrapper)
+	freshness := health.NewFreshness()
+	bccrScrapper = scrapper.NewInstrumentingScrapper(bccrScrapper, serviceMetrics.ScrapeDuration, serviceMetrics.ScrapeFailures, serviceMetrics.LastSuccess, freshness)
 
 	_ = level.Debug(logger).Log("msg", "BCCR scrapper initialized")
 
@@ -45,11 +78,29 @@ func (ws *WebServer) StartServer() {
 
 	_ = level.Debug(logger).Log("msg", "repository initialized")
 
-	service := services.NewService(logger, scrapper, repository)
+	service := services.NewService(logger, bccrScrapper, repository, serviceMetrics.FanOut)
+
+	checker := health.NewChecker(config.Health, repository, freshness)
+
+	var keyRepository auth.KeyRepository
+	if config.Auth.KeysFromRepository {
+		keyRepository = repository
+	}
+	keys, err := auth.NewKeys(config.Auth, keyRepository)
+	if err != nil {
+		panic(err)
+	}
+
+	// the HTTP and gRPC calls of a client are charged to the same bucket
+	var limiter *ratelimit.Limiter
+	if config.Limits.Enabled {
+		limiter = ratelimit.NewLimiter()
+	}
 
-	errs := make(chan error)
+	errs := make(chan error, 3)
 
 	var httpAddr = flag.String("http", fmt.Sprintf(":%s", config.Address.Port), "http listen address")
+	var grpcAddr = flag.String("grpc", fmt.Sprintf(":%s", config.Address.GRPCPort), "grpc listen address, empty to disable it")
 
 	flag.Parse()
 
@@ -59,12 +110,102 @@ func (ws *WebServer) StartServer() {
 		errs <- fmt.Errorf("%s", <-c)
 	}()
 
+	// the poller scrapes the subscribed series until the background work is cancelled
+	hub := stream.NewHub(config.Stream, serviceMetrics.Subscribers)
+	webhookStore := webhooks.NewMemoryStore(config.Webhooks.DeadLetters)
+	dispatcher := webhooks.NewDispatcher(ctx, webhookStore, config.Webhooks, serviceMetrics.Deliveries, logger)
+	if webhookStore.Ephemeral() {
+		_ = level.Warn(logger).Log("msg", "the webhooks are kept in memory, they are lost when the service restarts")
+	}
+	consumers := []stream.Consumer{hub, dispatcher}
+	if config.Alerts.RulesFile != "" {
+		rules, err := alerts.LoadRules(config.Alerts.RulesFile)
+		if err != nil {
+			panic(err)
+		}
+		notifiers, err := alerts.NewNotifiers(config.Alerts, logger)
+		if err != nil {
+			panic(err)
+		}
+		engine := alerts.NewEngine(service, rules, notifiers, config.Alerts.NotifyTimeout, serviceMetrics.Notifications, logger)
+		go engine.Run(ctx)
+		consumers = append(consumers, engine)
+		_ = level.Info(logger).Log("msg", "alert rules loaded", "rules", len(rules), "notifiers", len(notifiers))
+	}
+	go stream.NewPoller(service, config.Stream.PollInterval, serviceMetrics.Events, logger, consumers...).Run(ctx)
+
+	handler, err := MakeHTTPHandler(ctx, service, serviceMetrics, checker, keys, limiter, hub, webhooks.NewService(webhookStore, dispatcher, config.Webhooks), logger)
+	if err != nil {
+		panic(err)
+	}
+
+	server := &http.Server{
+		Addr:              *httpAddr,
+		Handler:           handler,
+		ReadTimeout:       config.Address.ReadTimeout,
+		ReadHeaderTimeout: config.Address.ReadHeaderTimeout,
+		WriteTimeout:      config.Address.WriteTimeout,
+		IdleTimeout:       config.Address.IdleTimeout,
+	}
+	// the streams end as soon as the shutdown starts, they would hold the drain until its timeout
+	server.RegisterOnShutdown(hub.Close)
+
 	go func() {
-		_ = level.Debug(logger).Log("msg", "listening", "port", *httpAddr, "transport", "HTTP")
-		handler := MakeHTTPHandler(ctx, service, logger)
-		errs <- http.ListenAndServe(*httpAddr, handler)
+		_ = level.Info(logger).Log("msg", "listening", "port", *httpAddr, "transport", "HTTP")
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errs <- err
+		}
 	}()
 
-	_ = level.Debug(logger).Log("shutdown", <-errs)
+	var grpcServer *grpc.Server
+	if *grpcAddr != "" && *grpcAddr != ":" {
+		listener, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			panic(err)
+		}
+		grpcServer = grpc.NewServer(grpcInterceptors(keys, *config)...)
+		pb.RegisterIndicatorsServer(grpcServer, MakeGRPCServer(service, serviceMetrics, limiter, *config))
+		reflection.Register(grpcServer)
+
+		go func() {
+			_ = level.Info(logger).Log("msg", "listening", "port", *grpcAddr, "transport", "gRPC")
+			if err := grpcServer.Serve(listener); err != nil {
+				errs <- err
+			}
+		}()
+	}
+
+	reason := <-errs
+	_ = level.Info(logger).Log("msg", "shutdown started", "reason", reason, "drain_timeout", config.Address.ShutdownTimeout)
+	begin := time.Now()
 
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), config.Address.ShutdownTimeout)
+	defer cancelShutdown()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		_ = level.Error(logger).Log("msg", "requests in flight not drained, closing connections", "error", err)
+		_ = server.Close()
+	}
+	if grpcServer != nil {
+		stopGRPC(shutdownCtx, grpcServer)
+	}
+	cancel()
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		_ = level.Error(logger).Log("msg", "spans not flushed", "error", err)
+	}
+
+	_ = level.Info(logger).Log("msg", "shutdown completed", "duration", time.Since(begin))
+}
+
+// stopGRPC waits for the calls in flight, the streams still open when the drain timeout ends are closed
+func stopGRPC(ctx context.Context, server *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		server.Stop()
+	}
 }
@@ -0,0 +1,296 @@
+package transports
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/configuration"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/middleware"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/services"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/stream"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+)
+
+const (
+	transportSSE       = "sse"
+	transportWebSocket = "websocket"
+	// webSocketWriteWait is how long a write to a WebSocket can take before the client is considered gone
+	webSocketWriteWait = 10 * time.Second
+	// sseRetry is how long the browsers wait before reconnecting a stream, in milliseconds
+	sseRetry = 5000
+)
+
+type streamHandler struct {
+	hub          *stream.Hub
+	heartbeat    time.Duration
+	writeTimeout time.Duration
+	upgrader     websocket.Upgrader
+}
+
+func newStreamHandler(hub *stream.Hub, config *configuration.ServerConfig) (*streamHandler, error) {
+	checkOrigin, err := middleware.CheckOrigin(config.CORS)
+	if err != nil {
+		return nil, err
+	}
+	return &streamHandler{
+		hub:          hub,
+		heartbeat:    config.Stream.Heartbeat,
+		writeTimeout: config.Address.WriteTimeout,
+		upgrader: websocket.Upgrader{
+			HandshakeTimeout: config.Address.ReadHeaderTimeout,
+			CheckOrigin:      checkOrigin,
+		},
+	}, nil
+}
+
+// subscriber identifies the client of a stream like the rate limits, by its API key or else by its IP
+func subscriber(ctx context.Context) string {
+	client, _ := utils.ClientFrom(ctx)
+	if client.ID != "" {
+		return "key:" + client.ID
+	}
+	return "ip:" + client.IP
+}
+
+// decodeSubscription reads the series and the id of the last event received, from the Last-Event-ID
+// header the browsers send when they reconnect or from the last_event_id param
+func decodeSubscription(r *http.Request, seriesRequired bool) ([]string, uint64, error) {
+	series := []string{}
+	if value := r.FormValue("series"); value != "" || seriesRequired {
+		var err error
+		series, err = services.ParseComparableSeries(value)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.FormValue("last_event_id")
+	}
+	if lastEventID == "" {
+		return series, 0, nil
+	}
+	id, err := strconv.ParseUint(lastEventID, 10, 64)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: invalid last event id %q", utils.ErrDecodeRequest, lastEventID)
+	}
+	return series, id, nil
+}
+
+func writeEvent(w http.ResponseWriter, event models.ObservationEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
+
+// serveEvents streams the events as Server-Sent Events. The stream is closed before the write timeout of
+// the server cuts it, the browsers reconnect by themselves and resume from their last event.
+func (handler *streamHandler) serveEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	series, lastEventID, err := decodeSubscription(r, true)
+	if err != nil {
+		encodeError(ctx, err, w)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		encodeError(ctx, fmt.Errorf("streaming not supported"), w)
+		return
+	}
+	subscription, replay, err := handler.hub.Subscribe(subscriber(ctx), transportSSE, series, lastEventID)
+	if err != nil {
+		encodeError(ctx, err, w)
+		return
+	}
+	defer subscription.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", sseRetry)
+	for _, event := range replay {
+		if err := writeEvent(w, event); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(handler.heartbeat)
+	defer heartbeat.Stop()
+	var reconnect <-chan time.Time
+	if handler.writeTimeout > 0 {
+		timer := time.NewTimer(handler.writeTimeout - handler.writeTimeout/10)
+		defer timer.Stop()
+		reconnect = timer.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-reconnect:
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case event, open := <-subscription.Events():
+			if !open {
+				return
+			}
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// webSocketMessage changes the series of a WebSocket, its type is subscribe or unsubscribe
+type webSocketMessage struct {
+	Type   string   `json:"type"`
+	Series []string `json:"series"`
+}
+
+type webSocketReply struct {
+	Type   string   `json:"type"`
+	Series []string `json:"series,omitempty"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// serveWebSocket sends the events as JSON messages. The client can subscribe and unsubscribe from series
+// while the connection is open, and it is pinged every heartbeat.
+func (handler *streamHandler) serveWebSocket(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	series, lastEventID, err := decodeSubscription(r, false)
+	if err != nil {
+		encodeError(ctx, err, w)
+		return
+	}
+	// the subscription is taken before the upgrade, so a full hub is answered with a status
+	subscription, replay, err := handler.hub.Subscribe(subscriber(ctx), transportWebSocket, series, lastEventID)
+	if err != nil {
+		encodeError(ctx, err, w)
+		return
+	}
+	defer subscription.Close()
+
+	conn, err := handler.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader already answered the error
+		return
+	}
+	defer conn.Close()
+
+	replies := make(chan webSocketReply)
+	stopped := make(chan struct{})
+	defer close(stopped)
+	readerDone := make(chan struct{})
+	go handler.readWebSocket(conn, subscription, replies, stopped, readerDone)
+
+	write := func(message interface{}) error {
+		_ = conn.SetWriteDeadline(time.Now().Add(webSocketWriteWait))
+		return conn.WriteJSON(message)
+	}
+	for _, event := range replay {
+		if err := write(event); err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(handler.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-readerDone:
+			return
+		case reply := <-replies:
+			if err := write(reply); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(webSocketWriteWait)); err != nil {
+				return
+			}
+		case event, open := <-subscription.Events():
+			if !open {
+				// a slow client can reconnect and resume, a shut down service is going away
+				_ = conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseGoingAway, "subscription closed, resume from the last event"),
+					time.Now().Add(webSocketWriteWait))
+				return
+			}
+			if err := write(event); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readWebSocket handles the messages of the client, the connection is closed when it misses two pings
+func (handler *streamHandler) readWebSocket(conn *websocket.Conn, subscription *stream.Subscription, replies chan<- webSocketReply, stopped <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	pongWait := 2 * handler.heartbeat
+	conn.SetReadLimit(4096)
+	_ = conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(pongWait))
+
+		reply := webSocketReply{}
+		message := webSocketMessage{}
+		if err := json.Unmarshal(data, &message); err != nil {
+			reply = webSocketReply{Type: "error", Error: utils.ErrDecodeRequest.Error()}
+		} else if series, err := validSeries(message.Series); err != nil {
+			reply = webSocketReply{Type: "error", Error: err.Error()}
+		} else {
+			switch message.Type {
+			case "subscribe":
+				subscription.Add(series...)
+				reply = webSocketReply{Type: "subscribed", Series: series}
+			case "unsubscribe":
+				subscription.Remove(series...)
+				reply = webSocketReply{Type: "unsubscribed", Series: series}
+			default:
+				reply = webSocketReply{Type: "error", Error: "message type not supported. Should be subscribe or unsubscribe"}
+			}
+		}
+
+		select {
+		case replies <- reply:
+		case <-stopped:
+			return
+		}
+	}
+}
+
+func validSeries(series []string) ([]string, error) {
+	valid := make([]string, 0, len(series))
+	for _, name := range series {
+		name, err := services.ParseSeries(name)
+		if err != nil {
+			return nil, err
+		}
+		valid = append(valid, name)
+	}
+	return valid, nil
+}
@@ -1,17 +1,37 @@
 package transports
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
 	httptransport "github.com/go-kit/kit/transport/http"
 	"github.com/gorilla/mux"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/cache"
 	"github.com/jrodolforojas/libertadfinanciera-backend/internal/configuration"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/export"
+	gql "github.com/jrodolforojas/libertadfinanciera-backend/internal/graphql"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/health"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/metrics"
 	"github.com/jrodolforojas/libertadfinanciera-backend/internal/middleware"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/ratelimit"
 	"github.com/jrodolforojas/libertadfinanciera-backend/internal/services"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/stream"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/tracing"
 	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/webhooks"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 type errorer interface {
@@ -35,15 +55,59 @@ func encodeResponse(ctx context.Context, w http.ResponseWriter, response interfa
 		encodeError(ctx, e.error(), w)
 		return nil
 	}
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	return json.NewEncoder(w).Encode(response)
+
+	info, _ := ctx.Value(routeInfoKey).(routeInfo)
+	table, isTable := export.FromResponse(response)
+	body := &bytes.Buffer{}
+	if isTable && (info.format == formatCSV || info.format == formatXLSX) {
+		if err := encodeTable(body, w, info, table); err != nil {
+			return err
+		}
+	} else {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		if err := json.NewEncoder(body).Encode(response); err != nil {
+			return err
+		}
+		selected, err := selectFields(body.Bytes(), info.fields)
+		if err != nil {
+			encodeError(ctx, err, w)
+			return nil
+		}
+		body = bytes.NewBuffer(selected)
+	}
+
+	// the last observation is the last time the data of the response changed
+	return writeCacheable(w, info, body.Bytes(), table.DateTo)
+}
+
+// exportFileName has the indicator and the range of dates of the table, e.g. exchange_rates_2023-07-01_2023-08-07.csv
+func exportFileName(name string, table export.Table, extension string) string {
+	if table.DateFrom.IsZero() {
+		return fmt.Sprintf("%s.%s", name, extension)
+	}
+	return fmt.Sprintf("%s_%s_%s.%s", name, table.DateFrom.Format("2006-01-02"), table.DateTo.Format("2006-01-02"), extension)
+}
+
+func encodeTable(body io.Writer, w http.ResponseWriter, info routeInfo, table export.Table) error {
+	w.Header().Set("Content-Language", info.language)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", exportFileName(info.name, table, info.format)))
+	if info.format == formatXLSX {
+		w.Header().Set("Content-Type", contentTypeXLSX)
+		return export.WriteXLSX(body, table, info.language, info.name)
+	}
+	w.Header().Set("Content-Type", contentTypeCSV+"; charset=utf-8")
+	return export.WriteCSV(body, table, info.language)
 }
 
 func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	ww := &responseWriterWrapper{ResponseWriter: w}
+	var limitErr *ratelimit.LimitError
+	if errors.As(err, &limitErr) {
+		ww.Header().Set("Retry-After", strconv.Itoa(limitErr.Seconds()))
+	}
 	if !ww.wroteHeader {
 		ww.Header().Set("Content-Type", "application/json; charset=utf-8")
-		ww.WriteHeader(http.StatusInternalServerError) // Only call once
+		ww.WriteHeader(codeFrom(err)) // Only call once
 	}
 
 	json.NewEncoder(ww).Encode(map[string]interface{}{
@@ -51,167 +115,382 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	})
 }
 
+// badRequestErrors are the validation errors of the decoders
+var badRequestErrors = []error{
+	utils.ErrDateInvalidFormat,
+	utils.ErrInvalidDateRange,
+	utils.ErrRelativeRange,
+	utils.ErrPeriodicity,
+	utils.ErrDecodeRequest,
+	utils.ErrInvalidAmount,
+	utils.ErrCurrency,
+	utils.ErrInvalidTerm,
+	utils.ErrReferenceRate,
+	utils.ErrCalculatorType,
+	utils.ErrMaturity,
+	utils.ErrPagination,
+	utils.ErrOrder,
+	utils.ErrInvalidCursor,
+	utils.ErrFields,
+	utils.ErrSeries,
+	utils.ErrUnknownSeries,
+	utils.ErrWebhook,
+}
+
 func codeFrom(err error) int {
-	switch err {
-	case utils.ErrNotFound:
+	switch {
+	case errors.Is(err, utils.ErrNotFound):
 		return http.StatusNotFound
-	case utils.ErrDateInvalidFormat, utils.ErrInvalidDateRange:
-		return http.StatusBadRequest
-	default:
-		return http.StatusInternalServerError
+	case errors.Is(err, utils.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, utils.ErrRateLimited), errors.Is(err, utils.ErrStreamClientLimit):
+		return http.StatusTooManyRequests
+	case errors.Is(err, utils.ErrStreamFull):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, utils.ErrWebhookLimit):
+		return http.StatusForbidden
+	}
+	for _, badRequestError := range badRequestErrors {
+		if errors.Is(err, badRequestError) {
+			return http.StatusBadRequest
+		}
+	}
+	return http.StatusInternalServerError
+}
+
+// isFailed tells the responses with errors apart, the services return them inside the response
+func isFailed(response interface{}) bool {
+	e, ok := response.(errorer)
+	return ok && e.error() != nil
+}
+
+// isCacheable discards the responses with errors, the next request tries again
+func isCacheable(response interface{}) bool {
+	return !isFailed(response)
+}
+
+func MakeHTTPHandler(ctx context.Context, s *services.ServiceAPI, serviceMetrics *metrics.Metrics, checker *health.Checker, keys middleware.KeyLookup, limiter *ratelimit.Limiter, hub *stream.Hub, webhookService *webhooks.Service, logger log.Logger) (http.Handler, error) {
+	config, err := configuration.Read()
+	if err != nil {
+		return nil, err
+	}
+	cors, err := middleware.CORS(config.CORS)
+	if err != nil {
+		return nil, err
 	}
+	router, err := makeRouter(ctx, config, s, serviceMetrics, checker, keys, limiter, hub, webhookService, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// the CORS wrap the router, the preflights do not match any route
+	return middleware.RequestID()(middleware.AccessLog(logger)(cors(router))), nil
 }
 
-func MakeHTTPHandler(ctx context.Context, s *services.ServiceAPI, logger log.Logger) http.Handler {
+// makeRouter registers every route of the API, the tests walk it to compare it with the OpenAPI document
+func makeRouter(ctx context.Context, config *configuration.ServerConfig, s *services.ServiceAPI, serviceMetrics *metrics.Metrics, checker *health.Checker, keys middleware.KeyLookup, limiter *ratelimit.Limiter, hub *stream.Hub, webhookService *webhooks.Service, logger log.Logger) (*mux.Router, error) {
 	router := mux.NewRouter()
 	endpoints := services.MakeEndpoints(s)
 
-	subRouter := router.PathPrefix("/api").Subrouter()
-	router = router.PathPrefix("/").Subrouter()
+	legacyDeprecation, err := newDeprecation(config.Address)
+	if err != nil {
+		return nil, err
+	}
+	router.Use(middleware.Authenticate(keys, config.Auth.Required, config.Limits.TrustedProxies))
 
-	corsMethods := []string{http.MethodOptions, http.MethodGet}
-	config, _ := configuration.Read()
-	router.Use(middleware.CORSPolicies(corsMethods, config.Address.AllowedOrigins))
-	subRouter.Use(middleware.CORSPolicies(corsMethods, config.Address.AllowedOrigins))
+	router.Methods(http.MethodGet).Path("/metrics").Handler(promhttp.Handler())
+	router.Methods(http.MethodGet).Path("/healthz").HandlerFunc(handleLiveness)
+	router.Methods(http.MethodGet).Path("/readyz").Handler(handleReadiness(checker))
 
-	router.Methods(http.MethodGet).Path("/exchange_rates").Handler(httptransport.NewServer(
-		endpoints.GetAllDolarColonesChanges,
-		decodeGetAllDolarColonesChangesRequest,
-		encodeResponse,
-	))
+	// a route without documentation is served anyway, the tests catch it before it is deployed
+	routes := makeRoutes(endpoints)
+	webhookRoutes := makeWebhookRoutes(webhooks.MakeEndpoints(webhookService))
+	document, problems := makeOpenAPI(routes, webhookRoutes)
+	for _, problem := range problems {
+		_ = level.Warn(logger).Log("msg", "route left out of the OpenAPI document", "error", problem)
+	}
+	openAPIHandler, err := handleOpenAPI(document)
+	if err != nil {
+		return nil, err
+	}
+	router.Methods(http.MethodGet).Path("/openapi.json").Handler(openAPIHandler)
+	router.Methods(http.MethodGet).Path("/docs").HandlerFunc(handleDocs)
+	router.Methods(http.MethodGet).Path(redocPath).HandlerFunc(handleRedoc)
 
-	router.Methods(http.MethodGet).Path("/exchange_rates/today").Handler(httptransport.NewServer(
-		endpoints.GetTodayExchangeRate,
-		decodeTodayExchangeRateRequest,
-		encodeResponse,
-	))
+	var responseCache *cache.Cache
+	if config.Cache.Enabled {
+		responseCache = cache.New(config.Cache.MaxEntries, serviceMetrics.CacheHits, serviceMetrics.CacheMisses)
+	}
 
-	router.Methods(http.MethodGet).Path("/exchange_rates/filter").Handler(httptransport.NewServer(
-		endpoints.GetExchangeRatesByFilter,
-		decodeGetDataByFilterRequest,
-		encodeResponse,
-	))
+	for _, route := range routes {
+		routeEndpoint := route.endpoint
+		if responseCache != nil {
+			routeEndpoint = cache.Middleware(ctx, responseCache, route.name, route.cadence.ttl(config.Cache), isCacheable)(routeEndpoint)
+		}
+		routeEndpoint = tracing.EndpointMiddleware(route.name, isFailed)(routeEndpoint)
+		if limiter != nil {
+			routeEndpoint = ratelimit.EndpointMiddleware(limiter, config.Limits)(routeEndpoint)
+		}
+		routeEndpoint = metrics.EndpointMiddleware(serviceMetrics, route.name, isFailed)(routeEndpoint)
+		options := []httptransport.ServerOption{
+			httptransport.ServerBefore(populateRouteInfo(route.name, route.cadence.ttl(config.Cache))),
+			httptransport.ServerErrorEncoder(encodeError),
+		}
 
-	router.Methods(http.MethodGet).Path("/country_interes_rates/cr").Handler(httptransport.NewServer(
-		endpoints.GetBasicPassiveRates,
-		decodeGetAllDolarColonesChangesRequest,
-		encodeResponse,
+		// every version shares the endpoint, its cache and its metrics, only the encoding changes
+		for _, v := range versions {
+			router.Methods(http.MethodGet).Path(v.prefix() + route.path).Handler(httptransport.NewServer(
+				routeEndpoint,
+				route.decoder,
+				v.encoder(route.name),
+				options...,
+			))
+		}
+		if route.legacyPath != "" {
+			legacy := httptransport.NewServer(routeEndpoint, route.decoder, versions[0].encoder(route.name), options...)
+			router.Methods(http.MethodGet).Path(route.legacyPath).Handler(legacyDeprecation.wrap(legacy, versions[0].prefix()+route.path))
+		}
+	}
+	// GraphQL has its own versioning, the schema evolves without prefixes
+	executor, err := gql.NewExecutor(s, config.GraphQL, config.Limits.DaysPerToken)
+	if err != nil {
+		return nil, err
+	}
+	graphQLEndpoint := tracing.EndpointMiddleware("graphql", isGraphQLFailed)(executor.Endpoint())
+	if limiter != nil {
+		graphQLEndpoint = ratelimit.EndpointMiddleware(limiter, config.Limits)(graphQLEndpoint)
+	}
+	graphQLEndpoint = metrics.EndpointMiddleware(serviceMetrics, "graphql", isGraphQLFailed)(graphQLEndpoint)
+	router.Methods(http.MethodGet, http.MethodPost).Path("/graphql").Handler(httptransport.NewServer(
+		graphQLEndpoint,
+		decodeGraphQLRequest(executor),
+		encodeGraphQLResponse,
+		httptransport.ServerErrorEncoder(encodeError),
 	))
 
-	router.Methods(http.MethodGet).Path("/country_interes_rates/cr/today").Handler(httptransport.NewServer(
-		endpoints.GetTodayBasicPassiveRate,
-		decodeTodayExchangeRateRequest,
-		encodeResponse,
-	))
-	router.Methods(http.MethodGet).Path("/country_interes_rates/usa").Handler(httptransport.NewServer(
-		endpoints.GetTreasuryRatesUSA,
-		decodeGetAllDolarColonesChangesRequest,
-		encodeResponse,
-	))
-	router.Methods(http.MethodGet).Path("/country_interes_rates/usa/today").Handler(httptransport.NewServer(
-		endpoints.GetTreasuryRateUSA,
-		decodeTodayExchangeRateRequest,
-		encodeResponse,
-	))
-	router.Methods(http.MethodGet).Path("/monetary_policy_rates").Handler(httptransport.NewServer(
-		endpoints.GetMonetaryPolicyRates,
-		decodeGetAllDolarColonesChangesRequest,
-		encodeResponse,
-	))
-	router.Methods(http.MethodGet).Path("/monetary_policy_rates/today").Handler(httptransport.NewServer(
-		endpoints.GetTodayMonetaryPolicyRate,
-		decodeTodayExchangeRateRequest,
-		encodeResponse,
-	))
-	router.Methods(http.MethodGet).Path("/prime_rates").Handler(httptransport.NewServer(
-		endpoints.GetPrimeRates,
-		decodeGetAllDolarColonesChangesRequest,
-		encodeResponse,
-	))
-	router.Methods(http.MethodGet).Path("/prime_rates/today").Handler(httptransport.NewServer(
-		endpoints.GetPrimeRate,
-		decodeTodayExchangeRateRequest,
-		encodeResponse,
-	))
+	streams, err := newStreamHandler(hub, config)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range versions {
+		router.Methods(http.MethodGet).Path(v.prefix() + "/stream").HandlerFunc(streams.serveEvents)
+		router.Methods(http.MethodGet).Path(v.prefix() + "/stream/ws").HandlerFunc(streams.serveWebSocket)
+	}
 
-	router.Methods(http.MethodGet).Path("/inflation_rates/cr").Handler(httptransport.NewServer(
-		endpoints.GetCostaRicaInflationRates,
-		decodeGetAllDolarColonesChangesRequest,
-		encodeResponse,
-	))
+	for _, route := range webhookRoutes {
+		routeEndpoint := tracing.EndpointMiddleware(route.name, isWebhookFailed)(route.endpoint)
+		if limiter != nil {
+			routeEndpoint = ratelimit.EndpointMiddleware(limiter, config.Limits)(routeEndpoint)
+		}
+		routeEndpoint = metrics.EndpointMiddleware(serviceMetrics, route.name, isWebhookFailed)(routeEndpoint)
+		handler := httptransport.NewServer(
+			routeEndpoint,
+			route.decoder,
+			encodeWebhookResponse(route.status),
+			httptransport.ServerErrorEncoder(encodeError),
+		)
+		for _, v := range versions {
+			router.Methods(route.method).Path(v.prefix() + route.path).Handler(handler)
+		}
+	}
 
-	router.Methods(http.MethodGet).Path("/inflation_rates/cr/filter").Handler(httptransport.NewServer(
-		endpoints.GetCostaRicaInflationRatesByFilter,
-		decodeGetDataByFilterRequest,
-		encodeResponse,
-	))
+	return router, nil
+}
 
-	router.Methods(http.MethodGet).Path("/inflation_rates/cr/today").Handler(httptransport.NewServer(
-		endpoints.GetCostaRicaInflationRate,
-		decodeTodayExchangeRateRequest,
-		encodeResponse,
-	))
-	router.Methods(http.MethodGet).Path("/inflation_rates/usa").Handler(httptransport.NewServer(
-		endpoints.GetUSAInflationRates,
-		decodeGetAllDolarColonesChangesRequest,
-		encodeResponse,
-	))
-	router.Methods(http.MethodGet).Path("/inflation_rates/usa/today").Handler(httptransport.NewServer(
-		endpoints.GetUSAInflationRate,
-		decodeTodayExchangeRateRequest,
-		encodeResponse,
-	))
-	return router
+// handleLiveness only tells the process is answering, the dependencies are checked by the readiness
+func handleLiveness(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": health.StatusOK,
+	})
 }
 
-func decodeGetDataByFilterRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
-	periocity := r.FormValue("periocity")
+func handleReadiness(checker *health.Checker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		readiness := checker.Readiness(r.Context())
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		if readiness.Status != health.StatusReady {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(readiness)
+	})
+}
+
+// decodeGetDataByFilterRequest decodes the periodicities of an indicator whose history starts on historyFrom
+func decodeGetDataByFilterRequest(historyFrom time.Time) httptransport.DecodeRequestFunc {
+	return func(_ context.Context, r *http.Request) (request interface{}, err error) {
+		periocity := r.FormValue("periocity")
+
+		if periocity == "" {
+			return services.GetDataByFilterRequest{
+				Periodicity: "monthly",
+				HistoryFrom: historyFrom,
+			}, nil
+		}
+
+		if periocity != "quarterly" && periocity != "biannual" && periocity != "annual" &&
+			periocity != "quinquennium" && periocity != "monthly" {
+			return nil, utils.ErrPeriodicity
+		}
 
-	if periocity == "" {
 		return services.GetDataByFilterRequest{
-			Periodicity: "monthly",
+			Periodicity: periocity,
+			HistoryFrom: historyFrom,
 		}, nil
 	}
+}
 
-	if periocity != "quarterly" && periocity != "biannual" && periocity != "annual" &&
-		periocity != "quinquennium" && periocity != "monthly" {
-		return nil, utils.ErrPeriodicity
+func decodePagination(r *http.Request) (services.Pagination, error) {
+	page := services.Pagination{
+		Cursor: r.FormValue("cursor"),
+		Order:  strings.ToLower(r.FormValue("order")),
 	}
 
-	return services.GetDataByFilterRequest{
-		Periodicity: periocity,
-	}, nil
+	if limitParam := r.FormValue("limit"); limitParam != "" {
+		limit, err := strconv.Atoi(limitParam)
+		if err != nil || limit < 0 {
+			return page, utils.ErrPagination
+		}
+		page.Limit = limit
+	}
+
+	if offsetParam := r.FormValue("offset"); offsetParam != "" {
+		offset, err := strconv.Atoi(offsetParam)
+		if err != nil || offset < 0 {
+			return page, utils.ErrPagination
+		}
+		page.Offset = offset
+	}
+
+	if page.Order != "" && page.Order != models.OrderAscending && page.Order != models.OrderDescending {
+		return page, utils.ErrOrder
+	}
+
+	if page.Cursor != "" {
+		if _, _, err := utils.DecodeCursor(page.Cursor); err != nil {
+			return page, err
+		}
+	}
+
+	return page, nil
 }
 
-func decodeGetAllDolarColonesChangesRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
-	dateFromParam := r.FormValue("date_from")
-	dateToParam := r.FormValue("date_to")
+// formValue returns the first param that is present among a name and its aliases
+func formValue(r *http.Request, names ...string) string {
+	for _, name := range names {
+		if value := r.FormValue(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// decodeDateRange accepts explicit dates (date_from/from and date_to/to, any of them can be missing), relative
+// ranges up to today (last=90d, last=6m, ytd) and open ranges (since=2020). By default, the last 30 days.
+func decodeDateRange(r *http.Request) (time.Time, time.Time, error) {
+	dateFromParam := formValue(r, "date_from", "from")
+	dateToParam := formValue(r, "date_to", "to")
+	lastParam := r.FormValue("last")
+	sinceParam := r.FormValue("since")
+	if _, ok := r.URL.Query()["ytd"]; ok && lastParam == "" {
+		lastParam = "ytd"
+	}
 
-	if dateFromParam != "" && dateToParam != "" {
-		dateFrom, error := utils.ConvertStringDate(dateFromParam)
-		if error != nil {
-			return nil, utils.ErrDateInvalidFormat
+	if lastParam != "" {
+		if dateFromParam != "" || dateToParam != "" || sinceParam != "" {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: last and ytd can not be combined with date_from, date_to or since", utils.ErrInvalidDateRange)
 		}
+		return utils.ParseRelativeRange(lastParam)
+	}
+	if dateFromParam != "" && sinceParam != "" {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: since can not be combined with date_from", utils.ErrInvalidDateRange)
+	}
 
-		dateTo, error := utils.ConvertStringDate(dateToParam)
-		if error != nil {
-			return nil, utils.ErrDateInvalidFormat
+	var err error
+	dateTo := utils.Now()
+	if dateToParam != "" {
+		dateTo, err = utils.ParseDate(dateToParam)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("date_to: %w", err)
 		}
+	}
 
-		if !utils.IsDatesValid(dateFrom, dateTo) {
-			return nil, utils.ErrInvalidDateRange
+	dateFrom := dateTo.AddDate(0, 0, -utils.DEFAULT_DAYS_TO_GO_BACK)
+	switch {
+	case dateFromParam != "":
+		dateFrom, err = utils.ParseDate(dateFromParam)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("date_from: %w", err)
 		}
+	case sinceParam != "":
+		dateFrom, err = utils.ParseSince(sinceParam)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("since: %w", err)
+		}
+	}
 
-		return services.GetAllDollarColonesChangesRequest{
-			DateFrom: dateFrom,
-			DateTo:   dateTo,
-		}, nil
+	if err := utils.ValidateDateRange(dateFrom, dateTo); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return dateFrom, dateTo, nil
+}
+
+func decodeGetAllDolarColonesChangesRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	dateFrom, dateTo, err := decodeDateRange(r)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := decodePagination(r)
+	if err != nil {
+		return nil, err
 	}
 
-	dateFrom, dateTo := utils.GetDateFromDateToFromToday(utils.DEFAULT_DAYS_TO_GO_BACK)
 	return services.GetAllDollarColonesChangesRequest{
 		DateFrom: dateFrom,
 		DateTo:   dateTo,
+		Page:     page,
+	}, nil
+}
+
+func decodeTreasuryYieldCurveRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	date := utils.Now()
+	if dateParam := r.FormValue("date"); dateParam != "" {
+		date, err = utils.ParseDate(dateParam)
+		if err != nil {
+			return nil, fmt.Errorf("date: %w", err)
+		}
+		if err := utils.ValidateDateRange(date, date); err != nil {
+			return nil, err
+		}
+	}
+
+	return services.GetTreasuryYieldCurveRequest{
+		Date: date,
+	}, nil
+}
+
+var maturityRegexp = regexp.MustCompile(`^[0-9]+[DMY]$`)
+
+func decodeTreasuryYieldSeriesRequest(ctx context.Context, r *http.Request) (request interface{}, err error) {
+	maturity := strings.ToUpper(r.FormValue("maturity"))
+	if !maturityRegexp.MatchString(maturity) {
+		return nil, utils.ErrMaturity
+	}
+
+	dateRange, err := decodeGetAllDolarColonesChangesRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	req := dateRange.(services.GetAllDollarColonesChangesRequest)
+
+	return services.GetTreasuryYieldSeriesRequest{
+		Maturity: maturity,
+		DateFrom: req.DateFrom,
+		DateTo:   req.DateTo,
+		Page:     req.Page,
 	}, nil
 }
 
@@ -219,3 +498,167 @@ func decodeTodayExchangeRateRequest(_ context.Context, r *http.Request) (request
 	var req services.GetTodayExchangeRateRequest
 	return req, nil
 }
+
+func decodeInflationCalculatorRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	amount, err := strconv.ParseFloat(r.FormValue("amount"), 64)
+	if err != nil || amount <= 0 {
+		return nil, utils.ErrInvalidAmount
+	}
+
+	currency := strings.ToUpper(r.FormValue("currency"))
+	if currency == "" {
+		currency = models.CurrencyColones
+	}
+	if currency != models.CurrencyColones && currency != models.CurrencyDollars {
+		return nil, utils.ErrCurrency
+	}
+
+	dateFrom, err := utils.ParseDate(formValue(r, "date_from", "from"))
+	if err != nil {
+		return nil, fmt.Errorf("date_from: %w", err)
+	}
+
+	dateTo := utils.Now()
+	if dateToParam := formValue(r, "date_to", "to"); dateToParam != "" {
+		dateTo, err = utils.ParseDate(dateToParam)
+		if err != nil {
+			return nil, fmt.Errorf("date_to: %w", err)
+		}
+	}
+
+	if err := utils.ValidateDateRange(dateFrom, dateTo); err != nil {
+		return nil, err
+	}
+
+	return services.GetInflationCalculatorRequest{
+		Amount:   amount,
+		Currency: currency,
+		DateFrom: dateFrom,
+		DateTo:   dateTo,
+	}, nil
+}
+
+func decodeLoanCalculatorRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	calculatorType := r.FormValue("type")
+	if calculatorType == "" {
+		calculatorType = models.CalculatorLoan
+	}
+	if calculatorType != models.CalculatorLoan && calculatorType != models.CalculatorSavings {
+		return nil, utils.ErrCalculatorType
+	}
+
+	principal, err := strconv.ParseFloat(r.FormValue("principal"), 64)
+	if err != nil || principal <= 0 {
+		return nil, utils.ErrInvalidAmount
+	}
+
+	term, err := strconv.Atoi(r.FormValue("term"))
+	if err != nil || term <= 0 || term > services.MAXIMUM_LOAN_TERM {
+		return nil, utils.ErrInvalidTerm
+	}
+
+	margin := 0.0
+	if marginParam := r.FormValue("margin"); marginParam != "" {
+		margin, err = strconv.ParseFloat(marginParam, 64)
+		if err != nil {
+			return nil, utils.ErrInvalidAmount
+		}
+	}
+
+	referenceRate := strings.ToLower(r.FormValue("rate"))
+	if referenceRate != models.ReferenceRateBasicPassive && referenceRate != models.ReferenceRatePrime {
+		return nil, utils.ErrReferenceRate
+	}
+
+	frequency := r.FormValue("frequency")
+	if frequency == "" {
+		frequency = "monthly"
+	}
+	if frequency != "monthly" && frequency != "quarterly" && frequency != "biannual" && frequency != "annual" {
+		return nil, utils.ErrPeriodicity
+	}
+
+	dateFrom := time.Time{}
+	if dateFromParam := formValue(r, "date_from", "from"); dateFromParam != "" {
+		dateFrom, err = utils.ParseDate(dateFromParam)
+		if err != nil {
+			return nil, fmt.Errorf("date_from: %w", err)
+		}
+		if err := utils.ValidateDateRange(dateFrom, dateFrom); err != nil {
+			return nil, err
+		}
+	}
+
+	return services.GetLoanCalculatorRequest{
+		Type:          calculatorType,
+		Principal:     principal,
+		Term:          term,
+		Margin:        margin,
+		ReferenceRate: referenceRate,
+		Frequency:     frequency,
+		DateFrom:      dateFrom,
+	}, nil
+}
+
+func decodeCompareSeriesRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	series, err := services.ParseComparableSeries(r.FormValue("series"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.Join(services.ComparableSeries(), ", "))
+	}
+
+	frequency := formValue(r, "freq", "frequency")
+	if frequency == "" {
+		frequency = models.FrequencyMonthly
+	}
+	if frequency != models.FrequencyDaily && frequency != models.FrequencyWeekly && frequency != models.FrequencyMonthly {
+		return nil, utils.ErrPeriodicity
+	}
+
+	dateFrom, dateTo, err := decodeDateRange(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return services.GetCompareSeriesRequest{
+		Series:    series,
+		Frequency: frequency,
+		DateFrom:  dateFrom,
+		DateTo:    dateTo,
+	}, nil
+}
+
+// isCurrency accepts the currencies that can be converted
+func isCurrency(currency string) bool {
+	return currency == models.CurrencyColones || currency == models.CurrencyDollars || currency == models.CurrencyEuros
+}
+
+func decodeConvertRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	amount, err := strconv.ParseFloat(r.FormValue("amount"), 64)
+	if err != nil || amount <= 0 {
+		return nil, utils.ErrInvalidAmount
+	}
+
+	from := strings.ToUpper(r.FormValue("from"))
+	to := strings.ToUpper(r.FormValue("to"))
+	if !isCurrency(from) || !isCurrency(to) {
+		return nil, utils.ErrCurrency
+	}
+
+	date := time.Time{}
+	if dateParam := r.FormValue("date"); dateParam != "" {
+		date, err = utils.ParseDate(dateParam)
+		if err != nil {
+			return nil, fmt.Errorf("date: %w", err)
+		}
+		if err := utils.ValidateDateRange(date, date); err != nil {
+			return nil, err
+		}
+	}
+
+	return services.GetConvertRequest{
+		Amount: amount,
+		From:   from,
+		To:     to,
+		Date:   date,
+	}, nil
+}
@@ -0,0 +1,67 @@
+package transports
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/services"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+)
+
+func TestDecodeLoanCalculatorTerm(t *testing.T) {
+	// expected is 0 for the terms that are not valid
+	tests := []struct {
+		term     string
+		expected int
+	}{
+		{"1", 1},
+		{"600", services.MAXIMUM_LOAN_TERM},
+		{"601", 0},
+		{"0", 0},
+		{"-12", 0},
+		{"ten", 0},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("GET", "/calculator/loan?principal=1000000&rate=tbp&term="+test.term, nil)
+		request, err := decodeLoanCalculatorRequest(context.Background(), r)
+		if test.expected == 0 {
+			if !errors.Is(err, utils.ErrInvalidTerm) {
+				t.Errorf("term %s: %v, expected %v", test.term, err, utils.ErrInvalidTerm)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("term %s: %v", test.term, err)
+			continue
+		}
+		if term := request.(services.GetLoanCalculatorRequest).Term; term != test.expected {
+			t.Errorf("term %s decoded as %d", test.term, term)
+		}
+	}
+}
+
+func TestSelectFields(t *testing.T) {
+	body := []byte(`{"data":[{"date":"2024-01-02","sale":512.5,"buy":505.1}],"meta":{"total":1}}` + "\n")
+
+	selected, err := selectFields(body, parseFields(" date, sale ,"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"data":[{"date":"2024-01-02","sale":512.5}],"meta":{"total":1}}` + "\n"; string(selected) != expected {
+		t.Errorf("selected %s, expected %s", selected, expected)
+	}
+
+	if _, err := selectFields(body, []string{"date", "value"}); !errors.Is(err, utils.ErrFields) {
+		t.Errorf("an unknown field was selected: %v", err)
+	}
+	if selected, err := selectFields(body, nil); err != nil || string(selected) != string(body) {
+		t.Errorf("the response changed without fields: %s", selected)
+	}
+	// the responses without observations, e.g. the errors, are not changed
+	single := []byte(`{"date":"2024-01-02","sale":512.5}`)
+	if selected, err := selectFields(single, []string{"date"}); err != nil || string(selected) != string(single) {
+		t.Errorf("a response without data changed: %s", selected)
+	}
+}
@@ -0,0 +1,71 @@
+package transports
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/configuration"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+)
+
+// version is a version of the API under /api/<name>. A new version only lists the encoders of the routes
+// whose responses change shape, the rest are served like in v1, and the previous versions keep theirs.
+type version struct {
+	name     string
+	encoders map[string]httptransport.EncodeResponseFunc
+}
+
+var versions = []version{
+	{name: "v1"},
+}
+
+func (v version) prefix() string {
+	return "/api/" + v.name
+}
+
+func (v version) encoder(routeName string) httptransport.EncodeResponseFunc {
+	if encoder, found := v.encoders[routeName]; found {
+		return encoder
+	}
+	return encodeResponse
+}
+
+// deprecation are the headers of the legacy paths, they point the clients to the path in v1. The dates are
+// opt-in, the legacy paths only announce the ones configured.
+type deprecation struct {
+	deprecated time.Time
+	sunset     time.Time
+}
+
+func newDeprecation(config configuration.AddressConfig) (deprecation, error) {
+	d := deprecation{}
+	var err error
+	if config.LegacyDeprecated != "" {
+		if d.deprecated, err = utils.ParseDate(config.LegacyDeprecated); err != nil {
+			return deprecation{}, fmt.Errorf("LEGACY_ROUTES_DEPRECATED: %w", err)
+		}
+	}
+	if config.LegacySunset != "" {
+		if d.sunset, err = utils.ParseDate(config.LegacySunset); err != nil {
+			return deprecation{}, fmt.Errorf("LEGACY_ROUTES_SUNSET: %w", err)
+		}
+	}
+	return d, nil
+}
+
+// wrap adds the Deprecation (RFC 9745) and Sunset (RFC 8594) headers that are configured and the successor
+// Link header to a legacy path
+func (d deprecation) wrap(h http.Handler, successor string) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if !d.deprecated.IsZero() {
+			rw.Header().Set("Deprecation", fmt.Sprintf("@%d", d.deprecated.Unix()))
+		}
+		if !d.sunset.IsZero() {
+			rw.Header().Set("Sunset", d.sunset.UTC().Format(http.TimeFormat))
+		}
+		rw.Header().Add("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", successor))
+		h.ServeHTTP(rw, r)
+	})
+}
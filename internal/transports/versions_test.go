@@ -0,0 +1,88 @@
+package transports
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/configuration"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/services"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+)
+
+// baselineLegacyPaths are the paths served before the versions, the only ones with a legacy alias
+var baselineLegacyPaths = map[string]bool{
+	"/exchange_rates":                  true,
+	"/exchange_rates/today":            true,
+	"/exchange_rates/filter":           true,
+	"/country_interes_rates/cr":        true,
+	"/country_interes_rates/cr/today":  true,
+	"/country_interes_rates/usa":       true,
+	"/country_interes_rates/usa/today": true,
+	"/monetary_policy_rates":           true,
+	"/monetary_policy_rates/today":     true,
+	"/prime_rates":                     true,
+	"/prime_rates/today":               true,
+	"/inflation_rates/cr":              true,
+	"/inflation_rates/cr/filter":       true,
+	"/inflation_rates/cr/today":        true,
+	"/inflation_rates/usa":             true,
+	"/inflation_rates/usa/today":       true,
+}
+
+func TestOnlyTheBaselineRoutesHaveLegacyPaths(t *testing.T) {
+	legacyPaths := 0
+	for _, route := range makeRoutes(services.MakeEndpoints(nil)) {
+		if route.legacyPath == "" {
+			continue
+		}
+		legacyPaths++
+		if !baselineLegacyPaths[route.legacyPath] {
+			t.Errorf("route %s has the legacy path %s, it was added after the versions", route.name, route.legacyPath)
+		}
+	}
+	if legacyPaths != len(baselineLegacyPaths) {
+		t.Errorf("%d legacy paths, expected the %d of the baseline", legacyPaths, len(baselineLegacyPaths))
+	}
+}
+
+func TestDeprecationHeaders(t *testing.T) {
+	// the dates are days of the TIMEZONE
+	deprecated := fmt.Sprintf("@%d", time.Date(2026, 10, 19, 0, 0, 0, 0, utils.Location()).Unix())
+	sunset := time.Date(2027, 10, 19, 0, 0, 0, 0, utils.Location()).UTC().Format(http.TimeFormat)
+	tests := []struct {
+		name       string
+		config     configuration.AddressConfig
+		deprecated string
+		sunset     string
+	}{
+		{"without dates", configuration.AddressConfig{}, "", ""},
+		{"deprecated", configuration.AddressConfig{LegacyDeprecated: "2026-10-19"}, deprecated, ""},
+		{"deprecated with sunset", configuration.AddressConfig{LegacyDeprecated: "2026-10-19", LegacySunset: "2027-10-19"}, deprecated, sunset},
+	}
+	for _, test := range tests {
+		d, err := newDeprecation(test.config)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		w := httptest.NewRecorder()
+		ok := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+		d.wrap(ok, "/api/v1/exchange_rates").ServeHTTP(w, httptest.NewRequest("GET", "/exchange_rates", nil))
+
+		if deprecated := w.Header().Get("Deprecation"); deprecated != test.deprecated {
+			t.Errorf("%s: the deprecation is %q, expected %q", test.name, deprecated, test.deprecated)
+		}
+		if sunset := w.Header().Get("Sunset"); sunset != test.sunset {
+			t.Errorf("%s: the sunset is %q, expected %q", test.name, sunset, test.sunset)
+		}
+		if link := w.Header().Get("Link"); link != `</api/v1/exchange_rates>; rel="successor-version"` {
+			t.Errorf("%s: the link is %q", test.name, link)
+		}
+	}
+
+	if _, err := newDeprecation(configuration.AddressConfig{LegacySunset: "next year"}); err == nil {
+		t.Error("an invalid sunset was accepted")
+	}
+}
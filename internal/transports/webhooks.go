@@ -0,0 +1,77 @@
+package transports
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-kit/kit/endpoint"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/webhooks"
+)
+
+// MAXIMUM_WEBHOOK_BODY is the size of the largest webhook accepted, in bytes
+const MAXIMUM_WEBHOOK_BODY = 1 << 14
+
+type webhookRoute struct {
+	name     string
+	method   string
+	path     string
+	endpoint endpoint.Endpoint
+	decoder  httptransport.DecodeRequestFunc
+	status   int
+}
+
+func makeWebhookRoutes(endpoints webhooks.Endpoints) []webhookRoute {
+	return []webhookRoute{
+		{"create_webhook", http.MethodPost, "/webhooks", endpoints.CreateWebhook, decodeCreateWebhookRequest, http.StatusCreated},
+		{"list_webhooks", http.MethodGet, "/webhooks", endpoints.ListWebhooks, decodeNoRequest, http.StatusOK},
+		// the dead letters are registered before the ids, gorilla matches the routes in order
+		{"get_webhook_dead_letters", http.MethodGet, "/webhooks/dead_letters", endpoints.GetDeadLetters, decodeNoRequest, http.StatusOK},
+		{"get_webhook", http.MethodGet, "/webhooks/{id}", endpoints.GetWebhook, decodeWebhookRequest, http.StatusOK},
+		{"delete_webhook", http.MethodDelete, "/webhooks/{id}", endpoints.DeleteWebhook, decodeWebhookRequest, http.StatusNoContent},
+		{"test_webhook", http.MethodPost, "/webhooks/{id}/test", endpoints.TestWebhook, decodeWebhookRequest, http.StatusOK},
+	}
+}
+
+func decodeCreateWebhookRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	req := webhooks.CreateWebhookRequest{}
+	decoder := json.NewDecoder(http.MaxBytesReader(nil, r.Body, MAXIMUM_WEBHOOK_BODY))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		return nil, fmt.Errorf("%w: %s", utils.ErrDecodeRequest, err)
+	}
+	return req, nil
+}
+
+func decodeWebhookRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	return webhooks.WebhookRequest{ID: mux.Vars(r)["id"]}, nil
+}
+
+func decodeNoRequest(_ context.Context, _ *http.Request) (interface{}, error) {
+	return nil, nil
+}
+
+// encodeWebhookResponse wraps the response in data like the rest of the API, the webhooks are never cached
+func encodeWebhookResponse(status int) httptransport.EncodeResponseFunc {
+	return func(_ context.Context, w http.ResponseWriter, response interface{}) error {
+		w.Header().Set("Cache-Control", "no-store")
+		if status == http.StatusNoContent {
+			w.WriteHeader(status)
+			return nil
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(status)
+		return json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": response,
+		})
+	}
+}
+
+// isWebhookFailed is never true, the webhook endpoints return their errors instead of responses
+func isWebhookFailed(interface{}) bool {
+	return false
+}
@@ -0,0 +1,39 @@
+package utils
+
+import "context"
+
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	clientKey
+)
+
+// Client is who sent a request. The requests with an API key are limited by key, the rest by IP.
+type Client struct {
+	ID                string  `json:"id"`
+	IP                string  `json:"ip"`
+	RequestsPerMinute float64 `json:"requests_per_minute"`
+}
+
+// WithRequestID returns a context that carries the id of the request that started the work
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestID returns the id of the request of a context, empty for the work not started by a request
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// WithClient returns a context that carries the client of the request
+func WithClient(ctx context.Context, client Client) context.Context {
+	return context.WithValue(ctx, clientKey, client)
+}
+
+// ClientFrom returns the client of the request of a context
+func ClientFrom(ctx context.Context) (Client, bool) {
+	client, ok := ctx.Value(clientKey).(Client)
+	return client, ok
+}
@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DEFAULT_TIMEZONE = "America/Costa_Rica"
+
+// location is the timezone of "today" and of the dates without time received in the requests
+var location = time.UTC
+
+// dateLayouts are the formats accepted in the requests, the ones without time are dates of location
+var dateLayouts = []string{
+	DATE_FORMAT,
+	"2006-01-02",
+	"2006/1/2",
+	"2006-1-2",
+	"2006-01-02T15:04:05",
+	time.RFC3339,
+	time.RFC3339Nano,
+}
+
+// SetTimezone sets the timezone used to resolve today and the dates of the requests
+func SetTimezone(name string) error {
+	if name == "" {
+		name = DEFAULT_TIMEZONE
+	}
+	loaded, err := time.LoadLocation(name)
+	if err != nil {
+		return err
+	}
+	location = loaded
+	return nil
+}
+
+func Location() *time.Location {
+	return location
+}
+
+// Now returns the current time in the configured timezone
+func Now() time.Time {
+	return time.Now().In(location)
+}
+
+func startOfDay(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+}
+
+func endOfDay(date time.Time) time.Time {
+	return startOfDay(date).AddDate(0, 0, 1).Add(-time.Nanosecond)
+}
+
+// ParseDate accepts YYYY/MM/DD, YYYY-MM-DD and RFC 3339 dates
+func ParseDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range dateLayouts {
+		if date, err := time.ParseInLocation(layout, value, location); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w, got %q", ErrDateInvalidFormat, value)
+}
+
+// ParseSince accepts a year (2020), a month (2020/05 or 2020-05) or a date
+func ParseSince(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if year, err := strconv.Atoi(value); err == nil && len(value) == 4 {
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, location), nil
+	}
+	for _, layout := range []string{"2006/01", "2006-01"} {
+		if date, err := time.ParseInLocation(layout, value, location); err == nil {
+			return date, nil
+		}
+	}
+	return ParseDate(value)
+}
+
+// ParseRelativeRange returns the range of dates of expressions like 90d, 12w, 6m, 2y or ytd, up to today
+func ParseRelativeRange(value string) (time.Time, time.Time, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	dateTo := Now()
+	if value == "ytd" {
+		return time.Date(dateTo.Year(), time.January, 1, 0, 0, 0, 0, location), dateTo, nil
+	}
+	dateFrom, err := SubtractPeriod(dateTo, value)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return dateFrom, dateTo, nil
+}
+
+// SubtractPeriod returns the date a period like 90d, 12w, 6m or 2y before another date
+func SubtractPeriod(date time.Time, value string) (time.Time, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if len(value) < 2 {
+		return time.Time{}, fmt.Errorf("%w, got %q", ErrRelativeRange, value)
+	}
+	amount, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || amount <= 0 {
+		return time.Time{}, fmt.Errorf("%w, got %q", ErrRelativeRange, value)
+	}
+
+	switch value[len(value)-1] {
+	case 'd':
+		return date.AddDate(0, 0, -amount), nil
+	case 'w':
+		return date.AddDate(0, 0, -7*amount), nil
+	case 'm':
+		return date.AddDate(0, -amount, 0), nil
+	case 'y':
+		return date.AddDate(-amount, 0, 0), nil
+	default:
+		return time.Time{}, fmt.Errorf("%w, got %q", ErrRelativeRange, value)
+	}
+}
+
+// ValidateDateRange explains why a range is not valid. Both dates can be the same day and the range
+// can end today.
+func ValidateDateRange(dateFrom time.Time, dateTo time.Time) error {
+	if dateFrom.Year() < MINIMUM_YEAR || dateTo.Year() < MINIMUM_YEAR {
+		return fmt.Errorf("%w: dates should be after %d", ErrInvalidDateRange, MINIMUM_YEAR)
+	}
+	if dateFrom.After(dateTo) {
+		return fmt.Errorf("%w: date_from %s is after date_to %s", ErrInvalidDateRange,
+			dateFrom.Format(DATE_FORMAT), dateTo.Format(DATE_FORMAT))
+	}
+	if dateTo.After(endOfDay(Now())) {
+		return fmt.Errorf("%w: date_to %s is in the future", ErrInvalidDateRange, dateTo.Format(DATE_FORMAT))
+	}
+	return nil
+}
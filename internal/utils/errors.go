@@ -5,9 +5,33 @@ import (
 )
 
 var (
-	ErrDateInvalidFormat = errors.New("invalid date format. Should be in format: YYYY/MM/DD")
+	ErrDateInvalidFormat = errors.New("invalid date format. Should be in format: YYYY/MM/DD or YYYY-MM-DD")
 	ErrInvalidDateRange  = errors.New("invalid date range")
 	ErrNotFound          = errors.New("not found")
 	ErrPeriodicity       = errors.New("periodicity not supported")
 	ErrDecodeRequest     = errors.New("unable to decode the request")
+	ErrInvalidAmount     = errors.New("invalid amount. Should be a positive number")
+	ErrCurrency          = errors.New("currency not supported")
+	ErrInvalidTerm       = errors.New("invalid term. Should be a number of months between 1 and 600")
+	ErrReferenceRate     = errors.New("reference rate not supported. Should be tbp or prime")
+	ErrCalculatorType    = errors.New("calculator type not supported. Should be loan or savings")
+	ErrMaturity          = errors.New("maturity not supported. Should be in format: 3M, 2Y, 10Y")
+	ErrPagination        = errors.New("invalid pagination. limit and offset should be positive numbers")
+	ErrOrder             = errors.New("order not supported. Should be asc or desc")
+	ErrInvalidCursor     = errors.New("invalid cursor")
+	ErrFields            = errors.New("field not supported by the observations")
+	ErrSeries            = errors.New("series not supported. Should be a comma separated list of up to 6 series")
+	ErrUnknownSeries     = errors.New("series not supported")
+	ErrRelativeRange     = errors.New("invalid relative range. Should be in format: 90d, 12w, 6m, 2y or ytd")
+	ErrLogLevel          = errors.New("log level not supported. Should be debug, info, warn or error")
+	ErrUnauthorized      = errors.New("missing or invalid API key. Should be sent in the X-API-Key header")
+	ErrRateLimited       = errors.New("rate limit exceeded")
+	ErrLogFormat         = errors.New("log format not supported. Should be json or logfmt")
+	ErrQueryComplexity   = errors.New("query too complex")
+	ErrStreamFull        = errors.New("too many subscribers, try again later")
+	ErrStreamClientLimit = errors.New("too many streams open by the client")
+	ErrWebhook           = errors.New("invalid webhook")
+	ErrWebhookLimit      = errors.New("too many webhooks for the client")
+	ErrAlertRule         = errors.New("invalid alert rule")
+	ErrNotifier          = errors.New("notifier not supported. Should be log, smtp or telegram")
 )
@@ -1,19 +1,166 @@
 package utils
 
 import (
+	"io"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/configuration"
 )
 
-func NewLogger() log.Logger {
+// NewLogger builds the logger of the service from the configuration. The returned function closes the
+// log file, if there is one.
+func NewLogger(config configuration.LogConfig) (log.Logger, func() error, error) {
+	allowed, err := levelOption(config.Level)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	closeFile := func() error { return nil }
+	var output io.Writer = os.Stdout
+	if config.File != "" {
+		file, err := openRotatingFile(config.File, config.FileMaxSizeMB, config.FileMaxBackups)
+		if err != nil {
+			return nil, nil, err
+		}
+		output = io.MultiWriter(os.Stdout, file)
+		closeFile = file.Close
+	}
+	output = log.NewSyncWriter(output)
+
 	var logger log.Logger
-	{
-		logger = log.NewJSONLogger(os.Stdout)
-		logger = log.With(logger, "timestamp", log.DefaultTimestampUTC, "caller", log.Caller(4))
-		logger = level.NewFilter(logger, level.AllowAll())
+	switch strings.ToLower(config.Format) {
+	case "json":
+		logger = log.NewJSONLogger(output)
+	case "logfmt":
+		logger = log.NewLogfmtLogger(output)
+	default:
+		return nil, nil, ErrLogFormat
+	}
+	logger = level.NewFilter(logger, allowed)
+	logger = log.With(logger, "timestamp", log.DefaultTimestampUTC, "caller", caller)
+
+	return logger, closeFile, nil
+}
+
+func levelOption(name string) (level.Option, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return level.AllowDebug(), nil
+	case "info":
+		return level.AllowInfo(), nil
+	case "warn":
+		return level.AllowWarn(), nil
+	case "error":
+		return level.AllowError(), nil
+	}
+	return nil, ErrLogLevel
+}
+
+// loggerFile is this file, its frames are skipped like the ones of go-kit when looking for the caller
+var _, loggerFile, _, _ = runtime.Caller(0)
+
+// caller is the first frame outside of the loggers. A fixed depth breaks as soon as a logger is wrapped,
+// like the ones of level.Debug or the sampling.
+var caller log.Valuer = func() interface{} {
+	pcs := make([]uintptr, 16)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "github.com/go-kit/") && frame.File != loggerFile {
+			return filepath.Base(frame.File) + ":" + strconv.Itoa(frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// sampler keeps the first logs of each message per interval and then one of every thereafter. The
+// warnings and the errors are never dropped.
+type sampler struct {
+	next       log.Logger
+	first      int
+	thereafter int
+	interval   time.Duration
+
+	mutex   sync.Mutex
+	windows map[string]*samplingWindow
+}
+
+type samplingWindow struct {
+	start   time.Time
+	count   int
+	dropped int
+}
+
+// NewSampledLogger drops the repetitive debug and info logs, like the ones of every scrape or of every
+// row that fails to convert. A LOG_SAMPLING_FIRST of zero disables the sampling.
+func NewSampledLogger(logger log.Logger, config configuration.LogConfig) log.Logger {
+	if config.SamplingFirst <= 0 || config.SamplingInterval <= 0 {
+		return logger
+	}
+	return &sampler{
+		next:       logger,
+		first:      config.SamplingFirst,
+		thereafter: config.SamplingThereafter,
+		interval:   config.SamplingInterval,
+		windows:    map[string]*samplingWindow{},
+	}
+}
+
+func (s *sampler) Log(keyvals ...interface{}) error {
+	message, sampled := samplingKey(keyvals)
+	if !sampled {
+		return s.next.Log(keyvals...)
 	}
 
-	return logger
+	s.mutex.Lock()
+	now := time.Now()
+	window, found := s.windows[message]
+	if !found || now.Sub(window.start) >= s.interval {
+		window = &samplingWindow{start: now}
+		s.windows[message] = window
+	}
+	window.count++
+	keep := window.count <= s.first || (s.thereafter > 0 && (window.count-s.first)%s.thereafter == 0)
+	dropped := window.dropped
+	if keep {
+		window.dropped = 0
+	} else {
+		window.dropped++
+	}
+	s.mutex.Unlock()
+
+	if !keep {
+		return nil
+	}
+	if dropped > 0 {
+		keyvals = append(keyvals, "sampled_out", dropped)
+	}
+	return s.next.Log(keyvals...)
+}
+
+// samplingKey returns the message of the log and whether its level can be sampled
+func samplingKey(keyvals []interface{}) (string, bool) {
+	message := ""
+	sampled := false
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		switch keyvals[i] {
+		case level.Key():
+			sampled = keyvals[i+1] == level.DebugValue() || keyvals[i+1] == level.InfoValue()
+		case "msg", "message":
+			if value, ok := keyvals[i+1].(string); ok {
+				message = value
+			}
+		}
+	}
+	return message, sampled && message != ""
 }
@@ -1,25 +1,57 @@
 package utils
 
-import "time"
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
 
 const DATE_FORMAT = "2006/01/02"
 const MINIMUM_YEAR = 1900
 const DEFAULT_DAYS_TO_GO_BACK = 30
 
 func GetDateFromDateToFromToday(days int) (time.Time, time.Time) {
-	dateTo := time.Now()
+	dateTo := Now()
 	dateFrom := dateTo.AddDate(0, 0, -days)
 
 	return dateFrom, dateTo
 }
 
 func ConvertStringDate(date string) (time.Time, error) {
-	return time.Parse(DATE_FORMAT, date)
+	return ParseDate(date)
 }
 
 func IsDatesValid(dateFrom time.Time, dateTo time.Time) bool {
-	return dateFrom.Year() >= MINIMUM_YEAR &&
-		dateTo.Year() >= MINIMUM_YEAR &&
-		dateFrom.Before(dateTo) &&
-		dateTo.Before(time.Now())
+	return ValidateDateRange(dateFrom, dateTo) == nil
+}
+
+// EncodeCursor returns an opaque cursor that points to an observation: its date and how many observations
+// of that date were already returned, several observations can share a date
+func EncodeCursor(date time.Time, position int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%s|%d", date.UTC().Format(time.RFC3339Nano), position)))
+}
+
+// DecodeCursor returns the date and the position of a cursor. The cursors without position point after
+// every observation of their date.
+func DecodeCursor(cursor string) (time.Time, int, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+	dateText, positionText, hasPosition := strings.Cut(string(decoded), "|")
+	date, err := time.Parse(time.RFC3339Nano, dateText)
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+	if !hasPosition {
+		return date, math.MaxInt, nil
+	}
+	position, err := strconv.Atoi(positionText)
+	if err != nil || position < 1 {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+	return date, position, nil
 }
@@ -0,0 +1,39 @@
+package webhooks
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+)
+
+func validateCondition(condition models.WebhookCondition) error {
+	switch condition.Type {
+	case models.ConditionCrossesAbove, models.ConditionCrossesBelow, models.ConditionChanges:
+		return nil
+	case models.ConditionChangesByPercent:
+		if condition.Threshold <= 0 {
+			return fmt.Errorf("%w: the threshold of changes_by_percent should be a percentage greater than 0", utils.ErrWebhook)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: condition %q not supported. Should be %s, %s, %s or %s", utils.ErrWebhook, condition.Type,
+		models.ConditionCrossesAbove, models.ConditionCrossesBelow, models.ConditionChanges, models.ConditionChangesByPercent)
+}
+
+// matches compares the new value with the one before it. A crossing needs the previous value on the other
+// side of the threshold, or on it, so a value that stays above does not fire again.
+func matches(condition models.WebhookCondition, previous float64, value float64) bool {
+	switch condition.Type {
+	case models.ConditionCrossesAbove:
+		return previous <= condition.Threshold && value > condition.Threshold
+	case models.ConditionCrossesBelow:
+		return previous >= condition.Threshold && value < condition.Threshold
+	case models.ConditionChanges:
+		return value != previous
+	case models.ConditionChangesByPercent:
+		return previous != 0 && math.Abs(value-previous)/math.Abs(previous)*100 >= condition.Threshold
+	}
+	return false
+}
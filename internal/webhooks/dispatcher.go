@@ -0,0 +1,304 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	kitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/configuration"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+)
+
+const (
+	deliveryDelivered  = "delivered"
+	deliveryRetried    = "retried"
+	deliveryDeadLetter = "dead_letter"
+	userAgent          = "libertadfinanciera-webhooks/1.0"
+)
+
+// errPrivateTarget stops the deliveries to the network of the service, a webhook could be used to reach it
+var errPrivateTarget = fmt.Errorf("the url resolves to a private address")
+
+type delivery struct {
+	webhook models.Webhook
+	payload models.WebhookPayload
+	body    []byte
+	attempt int
+}
+
+// DeliveryResult is the answer of the receiver to a delivery
+type DeliveryResult struct {
+	Delivered bool   `json:"delivered"`
+	Status    int    `json:"status,omitempty"`
+	Error     string `json:"error,omitempty"`
+	Duration  string `json:"duration"`
+}
+
+// Dispatcher evaluates the webhooks with the events of the poller and delivers the payloads of the ones
+// that match. The deliveries run in a pool of workers, the failed ones are retried with an exponential
+// backoff and end in the dead letters after the last attempt.
+type Dispatcher struct {
+	ctx        context.Context
+	store      Store
+	config     configuration.WebhookConfig
+	client     *http.Client
+	queue      chan delivery
+	deliveries kitmetrics.Counter
+	logger     log.Logger
+	mutex      sync.Mutex
+	// latest is the date of the newest event of every series, the older ones are backfills
+	latest map[string]time.Time
+}
+
+// NewDispatcher starts the workers, they stop with the context
+func NewDispatcher(ctx context.Context, store Store, config configuration.WebhookConfig, deliveries kitmetrics.Counter, logger log.Logger) *Dispatcher {
+	dispatcher := &Dispatcher{
+		ctx:        ctx,
+		store:      store,
+		config:     config,
+		client:     newClient(config),
+		queue:      make(chan delivery, config.QueueSize),
+		deliveries: deliveries,
+		logger:     logger,
+		latest:     map[string]time.Time{},
+	}
+	for i := 0; i < config.Workers; i++ {
+		go dispatcher.work()
+	}
+	return dispatcher
+}
+
+// newClient does not follow the redirects, a receiver answers itself or the delivery fails
+func newClient(config configuration.WebhookConfig) *http.Client {
+	dialer := &net.Dialer{Timeout: config.Timeout}
+	if !config.AllowPrivateTargets {
+		// the address is checked after the resolution, a public name can point to a private address
+		dialer.Control = func(_ string, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || isPrivate(ip) {
+				return errPrivateTarget
+			}
+			return nil
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	transport.Proxy = nil
+	return &http.Client{
+		Timeout:   config.Timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func isPrivate(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast()
+}
+
+// Series returns the series with at least a webhook, the poller scrapes them
+func (dispatcher *Dispatcher) Series() []string {
+	series, err := dispatcher.store.Series(dispatcher.ctx)
+	if err != nil {
+		_ = level.Error(dispatcher.logger).Log("msg", "error reading the series of the webhooks", "error", err)
+		return nil
+	}
+	return series
+}
+
+// Publish evaluates the webhooks of every event. It does not block the poller, the deliveries that do not
+// fit in the queue go straight to the dead letters.
+func (dispatcher *Dispatcher) Publish(events ...models.ObservationEvent) {
+	for _, event := range events {
+		if event.Previous == nil || !dispatcher.isLatest(event) {
+			continue
+		}
+		webhooks, err := dispatcher.store.BySeries(dispatcher.ctx, event.Series)
+		if err != nil {
+			_ = level.Error(dispatcher.logger).Log("msg", "error reading the webhooks", "series", event.Series, "error", err)
+			continue
+		}
+		for _, webhook := range webhooks {
+			if !matches(webhook.Condition, *event.Previous, event.Value) {
+				continue
+			}
+			event := event
+			next, err := newDelivery(webhook, models.WebhookAlert, &event)
+			if err != nil {
+				_ = level.Error(dispatcher.logger).Log("msg", "error encoding the webhook payload", "webhook", webhook.ID, "error", err)
+				continue
+			}
+			dispatcher.enqueue(next, "")
+		}
+	}
+}
+
+// isLatest discards the events of dates before the newest one seen, a late revision of an old value is
+// not a change of the series
+func (dispatcher *Dispatcher) isLatest(event models.ObservationEvent) bool {
+	dispatcher.mutex.Lock()
+	defer dispatcher.mutex.Unlock()
+	latest, found := dispatcher.latest[event.Series]
+	if found && event.Date.Before(latest) {
+		return false
+	}
+	dispatcher.latest[event.Series] = event.Date
+	return true
+}
+
+func newDelivery(webhook models.Webhook, payloadType string, event *models.ObservationEvent) (delivery, error) {
+	id, err := newID("whd_")
+	if err != nil {
+		return delivery{}, err
+	}
+	payload := models.WebhookPayload{
+		ID:        id,
+		Type:      payloadType,
+		WebhookID: webhook.ID,
+		Series:    webhook.Series,
+		Condition: webhook.Condition,
+		Event:     event,
+		CreatedAt: time.Now(),
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return delivery{}, err
+	}
+	return delivery{webhook: webhook, payload: payload, body: body}, nil
+}
+
+func (dispatcher *Dispatcher) enqueue(next delivery, lastError string) {
+	select {
+	case dispatcher.queue <- next:
+	default:
+		if lastError == "" {
+			lastError = "the delivery queue is full"
+		}
+		dispatcher.deadLetter(next, 0, lastError)
+	}
+}
+
+func (dispatcher *Dispatcher) work() {
+	for {
+		select {
+		case <-dispatcher.ctx.Done():
+			return
+		case next := <-dispatcher.queue:
+			dispatcher.attempt(next)
+		}
+	}
+}
+
+// attempt delivers the payload once and schedules the next attempt when it fails. The worker does not wait
+// for the backoff, the other deliveries go on meanwhile.
+func (dispatcher *Dispatcher) attempt(next delivery) {
+	next.attempt++
+	result := dispatcher.send(dispatcher.ctx, next)
+	if result.Delivered {
+		dispatcher.deliveries.With("result", deliveryDelivered).Add(1)
+		return
+	}
+	if next.attempt >= dispatcher.config.MaxAttempts {
+		dispatcher.deadLetter(next, result.Status, result.Error)
+		return
+	}
+
+	dispatcher.deliveries.With("result", deliveryRetried).Add(1)
+	backoff := dispatcher.config.RetryBackoff << (next.attempt - 1)
+	_ = level.Warn(dispatcher.logger).Log("msg", "webhook delivery failed, retrying", "webhook", next.webhook.ID,
+		"delivery", next.payload.ID, "attempt", next.attempt, "status", result.Status, "error", result.Error, "backoff", backoff)
+	time.AfterFunc(backoff, func() {
+		// the retries pending when the service shuts down are lost with the memory store
+		if dispatcher.ctx.Err() == nil {
+			dispatcher.enqueue(next, result.Error)
+		}
+	})
+}
+
+// send posts the payload signed with the secret of the webhook, any status other than 2xx is a failure
+func (dispatcher *Dispatcher) send(ctx context.Context, next delivery) (result DeliveryResult) {
+	start := time.Now()
+	defer func() {
+		result.Duration = time.Since(start).String()
+	}()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, next.webhook.URL, bytes.NewReader(next.body))
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", userAgent)
+	request.Header.Set(HeaderWebhookID, next.webhook.ID)
+	request.Header.Set(HeaderDelivery, next.payload.ID)
+	request.Header.Set(HeaderSignature, Sign(next.webhook.Secret, time.Now(), next.body))
+
+	response, err := dispatcher.client.Do(request)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer response.Body.Close()
+	// the body is drained so the connection is reused, the receivers have nothing to tell
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 1<<16))
+
+	result.Status = response.StatusCode
+	result.Delivered = response.StatusCode >= 200 && response.StatusCode < 300
+	if !result.Delivered {
+		result.Error = fmt.Sprintf("the receiver answered %s", response.Status)
+	}
+	return result
+}
+
+func (dispatcher *Dispatcher) deadLetter(next delivery, status int, lastError string) {
+	dispatcher.deliveries.With("result", deliveryDeadLetter).Add(1)
+	_ = level.Error(dispatcher.logger).Log("msg", "webhook delivery failed", "webhook", next.webhook.ID,
+		"delivery", next.payload.ID, "attempts", next.attempt, "status", status, "error", lastError)
+	err := dispatcher.store.AddDeadLetter(dispatcher.ctx, models.DeadLetter{
+		Payload:    next.payload,
+		Owner:      next.webhook.Owner,
+		URL:        next.webhook.URL,
+		Attempts:   next.attempt,
+		LastStatus: status,
+		LastError:  lastError,
+		FailedAt:   time.Now(),
+	})
+	if err != nil {
+		_ = level.Error(dispatcher.logger).Log("msg", "error saving the dead letter", "delivery", next.payload.ID, "error", err)
+	}
+}
+
+// Ping delivers a ping to the webhook once, without retries, and returns the answer of the receiver
+func (dispatcher *Dispatcher) Ping(ctx context.Context, webhook models.Webhook) (DeliveryResult, error) {
+	ping, err := newDelivery(webhook, models.WebhookPing, nil)
+	if err != nil {
+		return DeliveryResult{}, err
+	}
+	ping.attempt = 1
+	return dispatcher.send(ctx, ping), nil
+}
+
+func newID(prefix string) (string, error) {
+	random := make([]byte, 12)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return prefix + hex.EncodeToString(random), nil
+}
@@ -0,0 +1,243 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/configuration"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+)
+
+const (
+	testOwner  = "acme"
+	testSecret = "whsec_test"
+)
+
+// receiver answers the deliveries with the statuses in order, the last one is repeated
+type receiver struct {
+	mutex      sync.Mutex
+	statuses   []int
+	deliveries []receivedDelivery
+}
+
+type receivedDelivery struct {
+	header http.Header
+	body   []byte
+	at     time.Time
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.deliveries = append(r.deliveries, receivedDelivery{header: req.Header.Clone(), body: body, at: time.Now()})
+	status := r.statuses[len(r.statuses)-1]
+	if len(r.deliveries) <= len(r.statuses) {
+		status = r.statuses[len(r.deliveries)-1]
+	}
+	w.WriteHeader(status)
+}
+
+func (r *receiver) received() []receivedDelivery {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]receivedDelivery{}, r.deliveries...)
+}
+
+func newTestDispatcher(t *testing.T, target string, maxAttempts int, backoff time.Duration) (*Dispatcher, *MemoryStore) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	store := NewMemoryStore(10)
+	config := configuration.WebhookConfig{
+		Timeout:             time.Second,
+		MaxAttempts:         maxAttempts,
+		RetryBackoff:        backoff,
+		Workers:             2,
+		QueueSize:           10,
+		DeadLetters:         10,
+		AllowPrivateTargets: true,
+	}
+	dispatcher := NewDispatcher(ctx, store, config, discard.NewCounter(), log.NewNopLogger())
+	err := store.Create(ctx, models.Webhook{
+		ID:        "wh_test",
+		Owner:     testOwner,
+		Series:    "exchange_rate",
+		Condition: models.WebhookCondition{Type: models.ConditionCrossesAbove, Threshold: 540},
+		URL:       target,
+		Secret:    testSecret,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dispatcher, store
+}
+
+func publish(dispatcher *Dispatcher, previous float64, value float64) {
+	dispatcher.Publish(models.ObservationEvent{
+		Type:     models.EventObservation,
+		Series:   "exchange_rate",
+		Date:     time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+		Value:    value,
+		Previous: &previous,
+	})
+}
+
+// waitFor polls the condition until it holds or the time runs out
+func waitFor(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// verify checks the signature header like a receiver would, with the raw body
+func verify(t *testing.T, delivery receivedDelivery) {
+	t.Helper()
+	header := delivery.header.Get(HeaderSignature)
+	timestamp, _, found := strings.Cut(strings.TrimPrefix(header, "t="), ",")
+	if !found {
+		t.Fatalf("signature header %q is not t=<unix>,v1=<hex>", header)
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		t.Fatalf("signature time %q: %s", timestamp, err)
+	}
+	if expected := Sign(testSecret, time.Unix(unix, 0), delivery.body); header != expected {
+		t.Errorf("signature %q, expected %q", header, expected)
+	}
+	if Sign("another secret", time.Unix(unix, 0), delivery.body) == header {
+		t.Error("the signature does not depend on the secret")
+	}
+}
+
+func TestDispatcherDeliversSignedPayloads(t *testing.T) {
+	target := &receiver{statuses: []int{http.StatusNoContent}}
+	server := httptest.NewServer(target)
+	defer server.Close()
+	dispatcher, store := newTestDispatcher(t, server.URL, 3, 10*time.Millisecond)
+
+	// the value stays under the threshold, nothing is delivered
+	publish(dispatcher, 530, 535)
+	publish(dispatcher, 535, 541)
+	waitFor(t, "the delivery", func() bool { return len(target.received()) == 1 })
+
+	delivery := target.received()[0]
+	verify(t, delivery)
+	payload := models.WebhookPayload{}
+	if err := json.Unmarshal(delivery.body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.Type != models.WebhookAlert || payload.WebhookID != "wh_test" || payload.Event == nil || payload.Event.Value != 541 {
+		t.Errorf("unexpected payload %+v", payload)
+	}
+	if delivery.header.Get(HeaderWebhookID) != "wh_test" || delivery.header.Get(HeaderDelivery) != payload.ID {
+		t.Errorf("unexpected headers %v", delivery.header)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if received := len(target.received()); received != 1 {
+		t.Errorf("%d deliveries, expected 1", received)
+	}
+	if deadLetters, _ := store.DeadLetters(context.Background(), testOwner); len(deadLetters) != 0 {
+		t.Errorf("unexpected dead letters %+v", deadLetters)
+	}
+}
+
+func TestDispatcherRetriesWithBackoff(t *testing.T) {
+	target := &receiver{statuses: []int{http.StatusServiceUnavailable, http.StatusInternalServerError, http.StatusOK}}
+	server := httptest.NewServer(target)
+	defer server.Close()
+	backoff := 20 * time.Millisecond
+	dispatcher, store := newTestDispatcher(t, server.URL, 5, backoff)
+
+	publish(dispatcher, 535, 541)
+	waitFor(t, "the third attempt", func() bool { return len(target.received()) == 3 })
+
+	deliveries := target.received()
+	for i, delivery := range deliveries {
+		verify(t, delivery)
+		if id := delivery.header.Get(HeaderDelivery); id != deliveries[0].header.Get(HeaderDelivery) {
+			t.Errorf("attempt %d has the delivery id %s, the retries keep the first one", i+1, id)
+		}
+	}
+	if wait := deliveries[1].at.Sub(deliveries[0].at); wait < backoff {
+		t.Errorf("the first retry waited %s, expected at least %s", wait, backoff)
+	}
+	if wait := deliveries[2].at.Sub(deliveries[1].at); wait < 2*backoff {
+		t.Errorf("the second retry waited %s, expected at least %s", wait, 2*backoff)
+	}
+
+	time.Sleep(8 * backoff)
+	if received := len(target.received()); received != 3 {
+		t.Errorf("%d attempts, the delivery should stop once it is delivered", received)
+	}
+	if deadLetters, _ := store.DeadLetters(context.Background(), testOwner); len(deadLetters) != 0 {
+		t.Errorf("unexpected dead letters %+v", deadLetters)
+	}
+}
+
+func TestDispatcherDeadLettersAfterTheLastAttempt(t *testing.T) {
+	target := &receiver{statuses: []int{http.StatusBadGateway}}
+	server := httptest.NewServer(target)
+	defer server.Close()
+	dispatcher, store := newTestDispatcher(t, server.URL, 3, 5*time.Millisecond)
+
+	publish(dispatcher, 535, 541)
+	var deadLetters []models.DeadLetter
+	waitFor(t, "the dead letter", func() bool {
+		deadLetters, _ = store.DeadLetters(context.Background(), testOwner)
+		return len(deadLetters) > 0
+	})
+
+	if received := len(target.received()); received != 3 {
+		t.Errorf("%d attempts, expected 3", received)
+	}
+	deadLetter := deadLetters[0]
+	if deadLetter.Attempts != 3 || deadLetter.LastStatus != http.StatusBadGateway || deadLetter.URL != server.URL {
+		t.Errorf("unexpected dead letter %+v", deadLetter)
+	}
+	if deadLetter.Payload.ID != target.received()[0].header.Get(HeaderDelivery) {
+		t.Errorf("the dead letter has the payload %s, expected the delivered one", deadLetter.Payload.ID)
+	}
+	if others, _ := store.DeadLetters(context.Background(), "another client"); len(others) != 0 {
+		t.Errorf("the dead letters of %s are listed to another client", testOwner)
+	}
+}
+
+func TestDispatcherRejectsPrivateTargets(t *testing.T) {
+	target := &receiver{statuses: []int{http.StatusOK}}
+	server := httptest.NewServer(target)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dispatcher := NewDispatcher(ctx, NewMemoryStore(10), configuration.WebhookConfig{Timeout: time.Second, MaxAttempts: 1},
+		discard.NewCounter(), log.NewNopLogger())
+
+	result, err := dispatcher.Ping(ctx, models.Webhook{ID: "wh_test", URL: server.URL, Secret: testSecret})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Delivered || !strings.Contains(result.Error, errPrivateTarget.Error()) {
+		t.Errorf("the ping to %s was not rejected: %+v", server.URL, result)
+	}
+	if received := len(target.received()); received != 0 {
+		t.Errorf("the receiver got %d deliveries", received)
+	}
+}
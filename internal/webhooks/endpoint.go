@@ -0,0 +1,82 @@
+package webhooks
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+)
+
+type Endpoints struct {
+	CreateWebhook  endpoint.Endpoint
+	ListWebhooks   endpoint.Endpoint
+	GetWebhook     endpoint.Endpoint
+	DeleteWebhook  endpoint.Endpoint
+	TestWebhook    endpoint.Endpoint
+	GetDeadLetters endpoint.Endpoint
+}
+
+// MakeEndpoints returns the errors instead of carrying them in the responses, the transports answer them
+// with their status
+func MakeEndpoints(s *Service) Endpoints {
+	return Endpoints{
+		CreateWebhook:  makeCreateWebhookEndpoint(s),
+		ListWebhooks:   makeListWebhooksEndpoint(s),
+		GetWebhook:     makeGetWebhookEndpoint(s),
+		DeleteWebhook:  makeDeleteWebhookEndpoint(s),
+		TestWebhook:    makeTestWebhookEndpoint(s),
+		GetDeadLetters: makeGetDeadLettersEndpoint(s),
+	}
+}
+
+func makeCreateWebhookEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(CreateWebhookRequest)
+		if !ok {
+			return nil, utils.ErrDecodeRequest
+		}
+		return s.Create(ctx, req)
+	}
+}
+
+func makeListWebhooksEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		return s.List(ctx)
+	}
+}
+
+func makeGetWebhookEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(WebhookRequest)
+		if !ok {
+			return nil, utils.ErrDecodeRequest
+		}
+		return s.Get(ctx, req)
+	}
+}
+
+func makeDeleteWebhookEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(WebhookRequest)
+		if !ok {
+			return nil, utils.ErrDecodeRequest
+		}
+		return nil, s.Delete(ctx, req)
+	}
+}
+
+func makeTestWebhookEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req, ok := request.(WebhookRequest)
+		if !ok {
+			return nil, utils.ErrDecodeRequest
+		}
+		return s.Test(ctx, req)
+	}
+}
+
+func makeGetDeadLettersEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		return s.DeadLetters(ctx)
+	}
+}
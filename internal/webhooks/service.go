@@ -0,0 +1,169 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/configuration"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/services"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+)
+
+// Service manages the webhooks of the clients. A webhook belongs to the API key that created it, the
+// other clients can not see it.
+type Service struct {
+	store        Store
+	dispatcher   *Dispatcher
+	maxPerClient int
+}
+
+func NewService(store Store, dispatcher *Dispatcher, config configuration.WebhookConfig) *Service {
+	return &Service{
+		store:        store,
+		dispatcher:   dispatcher,
+		maxPerClient: config.MaxPerClient,
+	}
+}
+
+type CreateWebhookRequest struct {
+	Series    string                  `json:"series"`
+	Condition models.WebhookCondition `json:"condition"`
+	URL       string                  `json:"url"`
+	Secret    string                  `json:"secret"`
+}
+
+// CreateWebhookResponse is the only answer with the secret, the client keeps it to verify the signatures
+type CreateWebhookResponse struct {
+	models.Webhook
+	Secret string `json:"secret"`
+}
+
+type WebhookRequest struct {
+	ID string `json:"id"`
+}
+
+// owner is the API key of the request, the webhooks of an anonymous client could not be told apart
+func owner(ctx context.Context) (string, error) {
+	client, found := utils.ClientFrom(ctx)
+	if !found || client.ID == "" {
+		return "", utils.ErrUnauthorized
+	}
+	return client.ID, nil
+}
+
+func (service *Service) Create(ctx context.Context, req CreateWebhookRequest) (*CreateWebhookResponse, error) {
+	owner, err := owner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	series, err := services.ParseSeries(req.Series)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateCondition(req.Condition); err != nil {
+		return nil, err
+	}
+	if err := validateURL(req.URL); err != nil {
+		return nil, err
+	}
+
+	webhooks, err := service.store.List(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	if len(webhooks) >= service.maxPerClient {
+		return nil, fmt.Errorf("%w: the limit is %d", utils.ErrWebhookLimit, service.maxPerClient)
+	}
+
+	id, err := newID("wh_")
+	if err != nil {
+		return nil, err
+	}
+	secret := req.Secret
+	if secret == "" {
+		if secret, err = newID("whsec_"); err != nil {
+			return nil, err
+		}
+	}
+	webhook := models.Webhook{
+		ID:        id,
+		Owner:     owner,
+		Series:    series,
+		Condition: req.Condition,
+		URL:       req.URL,
+		Secret:    secret,
+		Ephemeral: service.store.Ephemeral(),
+		CreatedAt: time.Now(),
+	}
+	if err := service.store.Create(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return &CreateWebhookResponse{Webhook: webhook, Secret: secret}, nil
+}
+
+func validateURL(target string) error {
+	parsed, err := url.Parse(target)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: the url should be an absolute http or https url", utils.ErrWebhook)
+	}
+	if parsed.User != nil || strings.Contains(parsed.Host, "@") {
+		return fmt.Errorf("%w: the url should not have credentials, the payloads are signed with the secret", utils.ErrWebhook)
+	}
+	return nil
+}
+
+func (service *Service) List(ctx context.Context) ([]models.Webhook, error) {
+	owner, err := owner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return service.store.List(ctx, owner)
+}
+
+// Get answers not found for the webhooks of the other clients, their ids are not disclosed
+func (service *Service) Get(ctx context.Context, req WebhookRequest) (*models.Webhook, error) {
+	owner, err := owner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	webhook, err := service.store.Get(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.Owner != owner {
+		return nil, utils.ErrNotFound
+	}
+	return webhook, nil
+}
+
+func (service *Service) Delete(ctx context.Context, req WebhookRequest) error {
+	if _, err := service.Get(ctx, req); err != nil {
+		return err
+	}
+	return service.store.Delete(ctx, req.ID)
+}
+
+// Test sends a ping to the webhook right away, a receiver can be checked before any value changes
+func (service *Service) Test(ctx context.Context, req WebhookRequest) (*DeliveryResult, error) {
+	webhook, err := service.Get(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	result, err := service.dispatcher.Ping(ctx, *webhook)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (service *Service) DeadLetters(ctx context.Context) ([]models.DeadLetter, error) {
+	owner, err := owner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return service.store.DeadLetters(ctx, owner)
+}
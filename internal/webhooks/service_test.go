@@ -0,0 +1,44 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/configuration"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+)
+
+func TestServiceCreatesEphemeralWebhooks(t *testing.T) {
+	service := NewService(NewMemoryStore(10), nil, configuration.WebhookConfig{MaxPerClient: 1})
+	request := CreateWebhookRequest{
+		Series:    "exchange_rate",
+		Condition: models.WebhookCondition{Type: models.ConditionChanges},
+		URL:       "https://example.com/hooks",
+	}
+
+	if _, err := service.Create(context.Background(), request); !errors.Is(err, utils.ErrUnauthorized) {
+		t.Errorf("an anonymous client created a webhook: %v", err)
+	}
+
+	ctx := utils.WithClient(context.Background(), utils.Client{ID: testOwner})
+	created, err := service.Create(ctx, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !created.Ephemeral || created.Secret == "" {
+		t.Errorf("unexpected webhook %+v", created)
+	}
+	listed, err := service.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listed) != 1 || !listed[0].Ephemeral {
+		t.Errorf("the listed webhooks %+v are not ephemeral", listed)
+	}
+
+	if _, err := service.Create(ctx, request); !errors.Is(err, utils.ErrWebhookLimit) {
+		t.Errorf("the limit of webhooks per client was not enforced: %v", err)
+	}
+}
@@ -0,0 +1,27 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+const (
+	// HeaderSignature signs the body with the secret of the webhook, in the format t=<unix time>,v1=<hex>
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderWebhookID = "X-Webhook-ID"
+	// HeaderDelivery is the id of the payload, the same on every retry
+	HeaderDelivery = "X-Webhook-Delivery"
+)
+
+// Sign returns the signature header of a body. The HMAC-SHA256 covers the time and the body joined by a
+// dot, the receivers can reject the old signatures to stop replays.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	unix := timestamp.Unix()
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", unix)
+	mac.Write(body)
+	return fmt.Sprintf("t=%d,v1=%s", unix, hex.EncodeToString(mac.Sum(nil)))
+}
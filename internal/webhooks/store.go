@@ -0,0 +1,137 @@
+package webhooks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/models"
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/utils"
+)
+
+// Store keeps the webhooks and the deliveries that failed
+type Store interface {
+	Create(ctx context.Context, webhook models.Webhook) error
+	Get(ctx context.Context, id string) (*models.Webhook, error)
+	List(ctx context.Context, owner string) ([]models.Webhook, error)
+	Delete(ctx context.Context, id string) error
+	BySeries(ctx context.Context, series string) ([]models.Webhook, error)
+	Series(ctx context.Context) ([]string, error)
+	AddDeadLetter(ctx context.Context, deadLetter models.DeadLetter) error
+	DeadLetters(ctx context.Context, owner string) ([]models.DeadLetter, error)
+	// Ephemeral tells the webhooks are lost when the service restarts
+	Ephemeral() bool
+}
+
+// MemoryStore keeps the webhooks in memory, they are lost when the service restarts. It keeps the last
+// maxDeadLetters failed deliveries.
+type MemoryStore struct {
+	mutex          sync.RWMutex
+	webhooks       map[string]models.Webhook
+	deadLetters    []models.DeadLetter
+	maxDeadLetters int
+}
+
+func NewMemoryStore(maxDeadLetters int) *MemoryStore {
+	return &MemoryStore{
+		webhooks:       map[string]models.Webhook{},
+		maxDeadLetters: maxDeadLetters,
+	}
+}
+
+func (store *MemoryStore) Ephemeral() bool {
+	return true
+}
+
+func (store *MemoryStore) Create(_ context.Context, webhook models.Webhook) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.webhooks[webhook.ID] = webhook
+	return nil
+}
+
+func (store *MemoryStore) Get(_ context.Context, id string) (*models.Webhook, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	webhook, found := store.webhooks[id]
+	if !found {
+		return nil, utils.ErrNotFound
+	}
+	return &webhook, nil
+}
+
+// List returns the webhooks of the owner, the oldest first
+func (store *MemoryStore) List(_ context.Context, owner string) ([]models.Webhook, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	webhooks := []models.Webhook{}
+	for _, webhook := range store.webhooks {
+		if webhook.Owner == owner {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	sort.Slice(webhooks, func(i, j int) bool {
+		return webhooks[i].CreatedAt.Before(webhooks[j].CreatedAt)
+	})
+	return webhooks, nil
+}
+
+func (store *MemoryStore) Delete(_ context.Context, id string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if _, found := store.webhooks[id]; !found {
+		return utils.ErrNotFound
+	}
+	delete(store.webhooks, id)
+	return nil
+}
+
+func (store *MemoryStore) BySeries(_ context.Context, series string) ([]models.Webhook, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	webhooks := []models.Webhook{}
+	for _, webhook := range store.webhooks {
+		if webhook.Series == series {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	return webhooks, nil
+}
+
+// Series returns the series with at least a webhook
+func (store *MemoryStore) Series(_ context.Context) ([]string, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	seen := map[string]bool{}
+	series := []string{}
+	for _, webhook := range store.webhooks {
+		if !seen[webhook.Series] {
+			seen[webhook.Series] = true
+			series = append(series, webhook.Series)
+		}
+	}
+	return series, nil
+}
+
+func (store *MemoryStore) AddDeadLetter(_ context.Context, deadLetter models.DeadLetter) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.deadLetters = append(store.deadLetters, deadLetter)
+	if len(store.deadLetters) > store.maxDeadLetters {
+		store.deadLetters = store.deadLetters[len(store.deadLetters)-store.maxDeadLetters:]
+	}
+	return nil
+}
+
+// DeadLetters returns the failed deliveries of the owner, the newest first
+func (store *MemoryStore) DeadLetters(_ context.Context, owner string) ([]models.DeadLetter, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	deadLetters := []models.DeadLetter{}
+	for i := len(store.deadLetters) - 1; i >= 0; i-- {
+		if store.deadLetters[i].Owner == owner {
+			deadLetters = append(deadLetters, store.deadLetters[i])
+		}
+	}
+	return deadLetters, nil
+}
@@ -1,6 +1,11 @@
 package main
 
-import "github.com/jrodolforojas/libertadfinanciera-backend/internal/transports"
+import (
+	// the timezone database is embedded, the containers do not always have it
+	_ "time/tzdata"
+
+	"github.com/jrodolforojas/libertadfinanciera-backend/internal/transports"
+)
 
 func main() {
 	server := transports.WebServer{}